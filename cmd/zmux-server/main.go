@@ -7,13 +7,18 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/edirooss/zmux-server/internal/config"
+	"github.com/edirooss/zmux-server/internal/domain/principal"
 	"github.com/edirooss/zmux-server/internal/http/handler"
 	mw "github.com/edirooss/zmux-server/internal/http/middleware"
 	"github.com/edirooss/zmux-server/internal/infrastructure/processmgr"
+	"github.com/edirooss/zmux-server/internal/infrastructure/telemetry"
 	"github.com/edirooss/zmux-server/internal/service"
+	"github.com/edirooss/zmux-server/pkg/envelope"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-contrib/secure"
 	"github.com/gin-gonic/gin"
@@ -22,17 +27,24 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-func init() {
-	// Handle version display
-	handleVersion()
-}
-
 func main() {
-	// Read env
-	isDev := os.Getenv("ENV") == "dev"
+	// Parse flags; handles -v/--version
+	flags := parseFlags()
+
+	// Load config (defaults → file → env → flags)
+	cfg, err := config.Load(flags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zmux-server: %v\n", err)
+		os.Exit(2)
+	}
+	if flags.PrintConfig {
+		printConfig(cfg)
+		os.Exit(0)
+	}
+	isDev := cfg.IsDev()
 
 	// Create Zap logger
-	log := buildLogger()
+	log := buildLogger(cfg.Log)
 	defer log.Sync()
 	log = log.Named("main")
 
@@ -44,17 +56,105 @@ func main() {
 	r := gin.New()
 
 	// Apply Gin middlewares
-	rdb := buildRedisClient("127.0.0.1:6379", 0)
-	logmngr := processmgr.NewLogManager()
-	b2bclntsvc, err := service.NewB2BClientService(context.TODO(), log, rdb, logmngr)
+	rdb := buildRedisClient(cfg.Redis)
+	var logfiles *processmgr.LogFiles // nil → channel logs are kept in memory only
+	if cfg.ChannelLogs.Dir != "" {
+		if logfiles, err = processmgr.NewLogFiles(log, processmgr.LogFilesOptions{
+			Dir:             cfg.ChannelLogs.Dir,
+			MaxSegmentBytes: cfg.ChannelLogs.MaxSegmentBytes,
+			MaxSegmentAge:   cfg.ChannelLogs.MaxSegmentAge,
+			MaxTotalBytes:   cfg.ChannelLogs.MaxTotalBytes,
+			Retention:       cfg.ChannelLogs.Retention,
+		}); err != nil {
+			log.Fatal("channel log files creation failed", zap.Error(err))
+		}
+	}
+	logmngr := processmgr.NewLogManager(logfiles)
+	var procreg *processmgr.Registry // nil → children are tied to the server's lifetime
+	if cfg.Process.StateDir != "" {
+		if procreg, err = processmgr.NewRegistry(log, cfg.Process.StateDir); err != nil {
+			log.Fatal("process registry creation failed", zap.Error(err))
+		}
+	}
+	outdestsvc, err := service.NewOutputDestinationService(context.TODO(), log, rdb)
+	if err != nil {
+		log.Fatal("output destination service creation failed", zap.Error(err))
+	}
+	chnlevts := service.NewChannelEventHub(log, service.ChannelEventHubOptions{
+		Backlog:          cfg.Events.Backlog,
+		StatusInterval:   cfg.Events.StatusInterval,
+		B2BOutputVisible: outdestsvc.B2BVisible,
+	})
+	chnlhist := service.NewChannelHistory(log, rdb, chnlevts, service.ChannelHistoryOptions{
+		MaxLen: cfg.Events.HistoryMaxLen,
+	})
+	uptimesvc := service.NewUptimeService(log, rdb, chnlevts)
+	auditsvc := service.NewAuditService(log, rdb, service.AuditOptions{
+		Retention: cfg.Audit.Retention,
+	})
+	usagesvc := service.NewUsageService(log, rdb, chnlevts, service.UsageOptions{
+		FlushInterval: cfg.Usage.FlushInterval,
+		Retention:     cfg.Usage.Retention,
+	})
+	webhooksvc, err := service.NewWebhookService(context.TODO(), log, rdb, service.WebhookOptions{
+		Workers:     cfg.Webhooks.Workers,
+		Timeout:     cfg.Webhooks.Timeout,
+		MaxAttempts: cfg.Webhooks.MaxAttempts,
+	})
+	if err != nil {
+		log.Fatal("webhook service creation failed", zap.Error(err))
+	}
+	b2bclntsvc, err := service.NewB2BClientService(context.TODO(), log, rdb, logmngr, procreg, chnlevts, webhooksvc)
 	if err != nil {
 		log.Fatal("b2b client service creation failed", zap.Error(err))
 	}
-	chnlsvc, err := service.NewChannelService(context.TODO(), log, rdb, b2bclntsvc, logmngr)
+	keyring, _ := envelope.ParseKeys(cfg.Secrets.Keys) // validated by config.Load
+	if !keyring.Enabled() {
+		log.Warn("secrets.keys not set; channel input passwords are stored in plaintext")
+	}
+	chnlsvc, err := service.NewChannelService(context.TODO(), log, rdb, b2bclntsvc, logmngr, procreg, chnlevts, webhooksvc, keyring, outdestsvc)
 	if err != nil {
 		log.Fatal("channel service creation failed", zap.Error(err))
 	}
-	authsvc, err := service.NewAuthService(log, isDev, b2bclntsvc)
+	webhooksvc.Watch(chnlevts, chnlsvc)
+	if procreg != nil {
+		procreg.ReapOrphans() // all units are registered now; anything left over is stale
+	}
+	remuxrepo := service.NewRemuxRepository(log, rdb)
+	watchctx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	go chnlevts.WatchStatuses(watchctx, chnlsvc, remuxrepo)
+	go chnlhist.Run(watchctx)
+	go uptimesvc.Run(watchctx, chnlsvc, remuxrepo)
+	go usagesvc.Run(watchctx, chnlsvc, remuxrepo)
+	metricshist := service.NewMetricsHistoryService(log, rdb)
+	go metricshist.Run(watchctx, chnlsvc, remuxrepo)
+	telemetry.Registry.Register(service.NewMetricsCollector(log, chnlsvc, b2bclntsvc, remuxrepo).Collect)
+	alertsvc, err := service.NewAlertService(context.TODO(), log, rdb, webhooksvc, service.AlertOptions{
+		EvalInterval:      cfg.Alerts.EvalInterval,
+		ResolvedRetention: cfg.Alerts.ResolvedRetention,
+	})
+	if err != nil {
+		log.Fatal("alert service creation failed", zap.Error(err))
+	}
+	go alertsvc.Run(watchctx, chnlevts, chnlsvc, b2bclntsvc, remuxrepo)
+	adminsvc, err := service.NewAdminUserService(context.TODO(), log, rdb, service.AdminUserOptions{
+		BootstrapUsername: cfg.Admin.BootstrapUsername,
+		BootstrapPassword: cfg.Admin.BootstrapPassword,
+	})
+	if err != nil {
+		log.Fatal("admin user service creation failed", zap.Error(err))
+	}
+	authsvc, err := service.NewAuthService(log, service.UserSessionOptions{
+		RedisAddr:     cfg.Redis.Addr,
+		RedisUsername: cfg.Redis.Username,
+		RedisPassword: cfg.Redis.Password,
+		RedisDB:       cfg.Redis.DB,
+		PoolSize:      cfg.Session.PoolSize,
+		Secret:        cfg.Session.Secret,
+		MaxAge:        cfg.Session.MaxAge,
+		Secure:        !isDev,
+	}, adminsvc, b2bclntsvc)
 	if err != nil {
 		log.Fatal("auth service creation failed", zap.Error(err))
 	}
@@ -64,15 +164,17 @@ func main() {
 
 		if isDev { // Enable CORS for local Vite dev
 			r.Use(cors.New(cors.Config{
-				AllowOrigins:     []string{"http://localhost:5173", "http://localhost:4173", "http://localhost:3000", "http://127.0.0.1:3000"},
+				AllowOrigins:     cfg.HTTP.CORSAllowOrigins,
 				AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-				AllowHeaders:     []string{"X-Request-ID", "Content-Type", "X-CSRF-Token", "Authorization"},
+				AllowHeaders:     []string{"X-Request-ID", "Content-Type", "X-CSRF-Token", "Authorization", "Last-Event-ID"},
 				ExposeHeaders:    []string{"X-Request-ID", "X-Total-Count", "X-Cache", "X-Summary-Generated-At"},
 				AllowCredentials: true, // Allow cookies in dev
 				MaxAge:           12 * time.Hour,
 			}))
 		} else { // Behind Nginx + TLS
-			r.SetTrustedProxies([]string{"127.0.0.1"})
+			if err := r.SetTrustedProxies(cfg.HTTP.TrustedProxies); err != nil {
+				log.Fatal("set trusted proxies failed", zap.Error(err))
+			}
 			r.Use(secure.New(secure.Config{
 				SSLProxyHeaders: map[string]string{
 					"X-Forwarded-Proto": "https", // Fix scheme for secure cookies
@@ -82,13 +184,15 @@ func main() {
 
 		r.Use(authsvc.UserSession.Middleware()) // Attach user cookie-based session for auth
 
+		r.Use(mw.Metrics())                     // Observability (Prometheus; GET /metrics)
 		r.Use(accessLog(zap.NewNop(), authsvc)) // Observability (logger, tracing)
 		// r.Use(accessLog(log, authsvc)) // Observability (logger, tracing)
 
+		maxBodyBytes := cfg.HTTP.MaxBodyBytes
 		r.Use(func(c *gin.Context) {
-			// Enforce a hard 10MB max request body.
+			// Enforce a hard max request body (default 10MB).
 			// Protects against oversized or drip-fed request body ("slow body" / RUDY DoS)
-			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 10<<20)
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBodyBytes)
 			c.Next()
 		})
 	}
@@ -111,87 +215,243 @@ func main() {
 			authed := r.Group("", mw.Authentication(authsvc)) // any authenticated principal (admin|b2b_client)
 			authed.GET("/api/me", handler.Me(authsvc, b2bclntsvc))
 
-			admins := authed.Group("", mw.Authorization(authsvc)) // only admins
+			adminusrhndlr := handler.NewAdminUserHandler(authsvc, adminsvc)
+			authed.PUT("/api/me/password", adminusrhndlr.ChangeOwnPassword) // self-service (admin users only)
+
+			reporthndlr := handler.NewReportHandler(authsvc, uptimesvc, chnlsvc, b2bclntsvc)
+			authed.GET("/api/me/uptime", reporthndlr.GetOwnUptime) // self-service (B2B clients only)
+
+			// Route → permission matrix (see principal.Permissions for role → permissions)
+			var (
+				canReadChannels      = mw.RequirePermission(authsvc, principal.PermChannelsRead)
+				canMonitorChannels   = mw.RequirePermission(authsvc, principal.PermChannelsMonitor)
+				canRestartChannels   = mw.RequirePermission(authsvc, principal.PermChannelsRestart)
+				canControlChannels   = mw.RequirePermission(authsvc, principal.PermChannelsControl)
+				canEditChannels      = mw.RequirePermission(authsvc, principal.PermChannelsEdit)
+				canConfigureChannels = mw.RequirePermission(authsvc, principal.PermChannelsConfigure)
+				canDeleteChannels    = mw.RequirePermission(authsvc, principal.PermChannelsDelete)
+				canReadB2BClients    = mw.RequirePermission(authsvc, principal.PermB2BClientsRead)
+				canWriteB2BClients   = mw.RequirePermission(authsvc, principal.PermB2BClientsWrite)
+				canManageAdminUsers  = mw.RequirePermission(authsvc, principal.PermAdminUsersManage)
+				canReadSystem        = mw.RequirePermission(authsvc, principal.PermSystemRead)
+				canManageWebhooks    = mw.RequirePermission(authsvc, principal.PermWebhooksManage)
+				canReadAlerts        = mw.RequirePermission(authsvc, principal.PermAlertsRead)
+				canSilenceAlerts     = mw.RequirePermission(authsvc, principal.PermAlertsSilence)
+				canManageAlerts      = mw.RequirePermission(authsvc, principal.PermAlertsManage)
+				canReadReports       = mw.RequirePermission(authsvc, principal.PermReportsRead)
+				canReadUsage         = mw.RequirePermission(authsvc, principal.PermUsageRead)
+				canReadAudit         = mw.RequirePermission(authsvc, principal.PermAuditRead)
+				canManageOwnTokens   = mw.RequirePermission(authsvc, principal.PermTokensManage)
+				canManageOutputs     = mw.RequirePermission(authsvc, principal.PermOutputsManage)
+
+				// Admins on any channel, B2B clients on their own (within quota)
+				canCreateChannel = mw.RequireAnyPermission(authsvc, principal.PermChannelsConfigure, principal.PermChannelsManageOwn)
+				canDeleteChannel = mw.RequireAnyPermission(authsvc, principal.PermChannelsDelete, principal.PermChannelsManageOwn)
+			)
 			{
 				{
-					channelshndlr, err := handler.NewChannelsHandler(log, authsvc, chnlsvc, b2bclntsvc, service.NewRemuxRepository(log, rdb))
+					channelshndlr, err := handler.NewChannelsHandler(log, authsvc, chnlsvc, b2bclntsvc, outdestsvc, remuxrepo, chnlhist, metricshist, auditsvc)
 					if err != nil {
 						log.Fatal("channels http handler creation failed", zap.Error(err))
 					}
 					// --- Channel collection ---
-					admins.POST("/api/channels", channelshndlr.CreateChannel)    // create one
-					authed.GET("/api/channels", channelshndlr.GetChannelList)    // get list, get many
-					admins.DELETE("/api/channels", channelshndlr.DeleteChannels) // delete many
-					admins.PATCH("/api/channels", channelshndlr.ModifyChannels)  // update many (modify/partial-update)
+					authed.POST("/api/channels", canCreateChannel, channelshndlr.CreateChannel)       // create one
+					authed.GET("/api/channels", canReadChannels, channelshndlr.GetChannelList)        // get list, get many
+					authed.DELETE("/api/channels", canDeleteChannels, channelshndlr.DeleteChannels)   // delete many
+					authed.PATCH("/api/channels", canConfigureChannels, channelshndlr.ModifyChannels) // update many (modify/partial-update)
 
 					// --- Channel resource ---
 					requireValidID := mw.RequireValidChannelID()
 					requireChannelAccess := mw.RequireChannelIDAccess(authsvc, b2bclntsvc)
-					authed.GET("/api/channels/:id", requireValidID, requireChannelAccess, channelshndlr.GetChannel)      // get one
-					admins.GET("/api/channels/:id/logs", requireValidID, channelshndlr.GetChannelLogs)                   // get one (logs)
-					admins.PUT("/api/channels/:id", requireValidID, channelshndlr.ReplaceChannel)                        // update one (replace/full-update)
-					authed.PATCH("/api/channels/:id", requireValidID, requireChannelAccess, channelshndlr.ModifyChannel) // update one (modify/partial-update)
-					admins.DELETE("/api/channels/:id", requireValidID, channelshndlr.DeleteChannel)                      // delete one
+					authed.GET("/api/channels/:id", canReadChannels, requireValidID, requireChannelAccess, channelshndlr.GetChannel)                 // get one
+					authed.GET("/api/channels/:id/logs", canMonitorChannels, requireValidID, channelshndlr.GetChannelLogs)                           // get one (logs)
+					authed.GET("/api/channels/:id/logs/stream", canMonitorChannels, requireValidID, channelshndlr.StreamChannelLogs)                 // get one (live logs; SSE)
+					authed.GET("/api/channels/:id/events", canReadChannels, requireValidID, requireChannelAccess, channelshndlr.GetChannelEvents)    // get one (event history)
+					authed.GET("/api/channels/:id/metrics/history", canMonitorChannels, requireValidID, channelshndlr.GetChannelMetricsHistory)      // get one (metrics time-series)
+					authed.GET("/api/channels/:id/runtime", canMonitorChannels, requireValidID, channelshndlr.GetChannelRuntime)                     // get one (supervisor state)
+					authed.POST("/api/channels/:id/restart", canRestartChannels, requireValidID, requireChannelAccess, channelshndlr.RestartChannel) // restart process
+					authed.POST("/api/channels/:id/stop", canControlChannels, requireValidID, channelshndlr.StopChannel)                             // stop process
+					authed.POST("/api/channels/:id/start", canControlChannels, requireValidID, channelshndlr.StartChannel)                           // start process
+					authed.POST("/api/channels/:id/reset-failed", canControlChannels, requireValidID, channelshndlr.ResetFailedChannel)              // reset crash-loop breaker
+					authed.PUT("/api/channels/:id", canConfigureChannels, requireValidID, channelshndlr.ReplaceChannel)                              // update one (replace/full-update)
+					authed.PATCH("/api/channels/:id", canEditChannels, requireValidID, requireChannelAccess, channelshndlr.ModifyChannel)            // update one (modify/partial-update)
+					authed.DELETE("/api/channels/:id", canDeleteChannel, requireValidID, requireChannelAccess, channelshndlr.DeleteChannel)          // delete one
 
 					// --- Channel views ---
-					admins.GET("/api/channels/summary", channelshndlr.Summary)
-					authed.GET("/api/channels/status", channelshndlr.Status)
+					authed.GET("/api/channels/summary", canMonitorChannels, channelshndlr.Summary)
+					authed.GET("/api/channels/status", canReadChannels, channelshndlr.Status)
+					authed.GET("/api/channels/events", canReadChannels, handler.NewChannelEventsHandler(log, authsvc, chnlevts).Stream) // SSE
 				}
 
 				{
 					// B2B Client handler
-					b2bclnthndlr := handler.NewB2BClientHandler(b2bclntsvc)
+					b2bclnthndlr := handler.NewB2BClientHandler(b2bclntsvc, authsvc, auditsvc, outdestsvc)
 
 					// --- B2B Client collection ---
-					admins.POST("/api/b2b-clients", b2bclnthndlr.CreateB2BClient)       // create one
-					admins.GET("/api/b2b-clients", b2bclnthndlr.GetAllB2BClients)       // get all
-					admins.GET("/api/b2b-clients/:id", b2bclnthndlr.GetB2BClient)       // get one
-					admins.PUT("/api/b2b-clients/:id", b2bclnthndlr.UpdateB2BClient)    // update one
-					admins.DELETE("/api/b2b-clients/:id", b2bclnthndlr.DeleteB2BClient) // delete one
+					authed.POST("/api/b2b-clients", canWriteB2BClients, b2bclnthndlr.CreateB2BClient)       // create one
+					authed.GET("/api/b2b-clients", canReadB2BClients, b2bclnthndlr.GetAllB2BClients)        // get all
+					authed.GET("/api/b2b-clients/:id", canReadB2BClients, b2bclnthndlr.GetB2BClient)        // get one
+					authed.PUT("/api/b2b-clients/:id", canWriteB2BClients, b2bclnthndlr.UpdateB2BClient)    // update one
+					authed.DELETE("/api/b2b-clients/:id", canWriteB2BClients, b2bclnthndlr.DeleteB2BClient) // delete one
+
+					// --- B2B Client API tokens ---
+					authed.GET("/api/b2b-clients/:id/tokens", canReadB2BClients, b2bclnthndlr.GetTokens)                      // list
+					authed.POST("/api/b2b-clients/:id/tokens", canWriteB2BClients, b2bclnthndlr.CreateToken)                  // create one
+					authed.POST("/api/b2b-clients/:id/tokens/:token_id/rotate", canWriteB2BClients, b2bclnthndlr.RotateToken) // rotate one
+					authed.DELETE("/api/b2b-clients/:id/tokens/:token_id", canWriteB2BClients, b2bclnthndlr.RevokeToken)      // revoke one
+					authed.GET("/api/me/tokens", b2bclnthndlr.GetOwnTokens)                                                   // self-service (B2B clients only)
+					authed.POST("/api/me/tokens", canManageOwnTokens, b2bclnthndlr.CreateOwnToken)                            // self-service
+					authed.POST("/api/me/tokens/:token_id/rotate", canManageOwnTokens, b2bclnthndlr.RotateOwnToken)           // self-service
+					authed.DELETE("/api/me/tokens/:token_id", canManageOwnTokens, b2bclnthndlr.RevokeOwnToken)                // self-service
+
+					usagehndlr := handler.NewUsageHandler(usagesvc, b2bclntsvc)
+					authed.GET("/api/b2b-clients/:id/usage", canReadUsage, usagehndlr.GetB2BClientUsage) // monthly usage
+					authed.GET("/api/usage", canReadUsage, usagehndlr.ExportUsage)                       // billing export (JSON or ?format=csv)
+				}
+
+				{
+					// Webhook handler
+					webhookhndlr := handler.NewWebhookHandler(webhooksvc)
+
+					// --- Webhook collection ---
+					authed.POST("/api/webhooks", canManageWebhooks, webhookhndlr.CreateWebhook)                      // create one
+					authed.GET("/api/webhooks", canManageWebhooks, webhookhndlr.GetAllWebhooks)                      // get all
+					authed.GET("/api/webhooks/:id", canManageWebhooks, webhookhndlr.GetWebhook)                      // get one
+					authed.PUT("/api/webhooks/:id", canManageWebhooks, webhookhndlr.UpdateWebhook)                   // update one
+					authed.DELETE("/api/webhooks/:id", canManageWebhooks, webhookhndlr.DeleteWebhook)                // delete one
+					authed.GET("/api/webhooks/:id/deliveries", canManageWebhooks, webhookhndlr.GetWebhookDeliveries) // delivery log
+					authed.POST("/api/webhooks/:id/ping", canManageWebhooks, webhookhndlr.PingWebhook)               // test delivery
 				}
-			}
 
-			// --- Outputs Ref ---
-			admins.GET("/api/channels/outputs/ref", func(ctx *gin.Context) {
-				type OutputRef struct {
-					ID   string `json:"id"`
-					Name string `json:"name"`
+				{
+					// Alert handler
+					alerthndlr := handler.NewAlertHandler(alertsvc)
+
+					// --- Alert rule collection ---
+					authed.POST("/api/alert-rules", canManageAlerts, alerthndlr.CreateAlertRule)       // create one
+					authed.GET("/api/alert-rules", canReadAlerts, alerthndlr.GetAllAlertRules)         // get all
+					authed.GET("/api/alert-rules/:id", canReadAlerts, alerthndlr.GetAlertRule)         // get one
+					authed.PUT("/api/alert-rules/:id", canManageAlerts, alerthndlr.UpdateAlertRule)    // update one
+					authed.DELETE("/api/alert-rules/:id", canManageAlerts, alerthndlr.DeleteAlertRule) // delete one
+
+					// --- Alerts ---
+					authed.GET("/api/alerts", canReadAlerts, alerthndlr.GetAlerts)                        // get all (?state=)
+					authed.POST("/api/alerts/:id/silence", canSilenceAlerts, alerthndlr.SilenceAlert)     // silence one
+					authed.DELETE("/api/alerts/:id/silence", canSilenceAlerts, alerthndlr.UnsilenceAlert) // unsilence one
+				}
+
+				{
+					// --- Audit log ---
+					authed.GET("/api/audit", canReadAudit, handler.NewAuditHandler(auditsvc).GetAudit) // ?resource=&actor=&since=&limit=
+				}
+
+				{
+					// --- Reports ---
+					authed.GET("/api/reports/uptime", canReadReports, reporthndlr.GetUptimeReport) // JSON or ?format=csv
 				}
-				ctx.JSON(http.StatusOK, []OutputRef{{"onprem_mr01", "onprem_mr01"}, {"onprem_mz01", "onprem_mz01"}, {"pubcloud_sky320", "pubcloud_sky320"}})
-			})
+
+				{
+					// --- Admin User collection ---
+					authed.POST("/api/admin-users", canManageAdminUsers, adminusrhndlr.CreateAdminUser)       // create one
+					authed.GET("/api/admin-users", canManageAdminUsers, adminusrhndlr.GetAllAdminUsers)       // get all
+					authed.GET("/api/admin-users/:id", canManageAdminUsers, adminusrhndlr.GetAdminUser)       // get one
+					authed.PUT("/api/admin-users/:id", canManageAdminUsers, adminusrhndlr.UpdateAdminUser)    // update one
+					authed.DELETE("/api/admin-users/:id", canManageAdminUsers, adminusrhndlr.DeleteAdminUser) // delete one
+				}
+			}
+
+			{
+				outdesthndlr := handler.NewOutputDestinationHandler(outdestsvc, b2bclntsvc, authsvc, auditsvc)
+
+				// --- Output destinations ---
+				authed.POST("/api/output-destinations", canManageOutputs, outdesthndlr.CreateOutputDestination)       // create one
+				authed.GET("/api/output-destinations", canReadSystem, outdesthndlr.GetAllOutputDestinations)          // get all
+				authed.GET("/api/output-destinations/:id", canReadSystem, outdesthndlr.GetOutputDestination)          // get one
+				authed.PUT("/api/output-destinations/:id", canManageOutputs, outdesthndlr.UpdateOutputDestination)    // update one
+				authed.DELETE("/api/output-destinations/:id", canManageOutputs, outdesthndlr.DeleteOutputDestination) // delete one
+
+				// --- Outputs Ref ---
+				authed.GET("/api/channels/outputs/ref", canReadSystem, outdesthndlr.GetOutputRefs)
+			}
 
 			// --- System ---
-			admins.GET("/api/system/net/localaddrs", handler.NewLocalAddrHandler(log).GetLocalAddrList) // GET local network addresses
+			authed.GET("/api/system/net/localaddrs", canReadSystem, handler.NewLocalAddrHandler(log).GetLocalAddrList) // GET local network addresses
+			authed.GET("/metrics", canReadSystem, gin.WrapH(telemetry.Registry.Handler()))                             // Prometheus exposition
 		}
 	}
 
 	httpsrv := &http.Server{
-		Addr:              "127.0.0.1:8080",
+		Addr:              cfg.HTTP.Addr,
 		Handler:           r,
-		ReadHeaderTimeout: 2 * time.Second,  // kills header-drip Slowloris
-		ReadTimeout:       10 * time.Second, // full request read (incl. body)
-		WriteTimeout:      15 * time.Second, // avoid forever-hangs on writes
-		IdleTimeout:       60 * time.Second, // keep-alive cap
-		MaxHeaderBytes:    1 << 20,          // 1MB cap
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout, // kills header-drip Slowloris
+		ReadTimeout:       cfg.HTTP.ReadTimeout,       // full request read (incl. body)
+		WriteTimeout:      cfg.HTTP.WriteTimeout,      // avoid forever-hangs on writes
+		IdleTimeout:       cfg.HTTP.IdleTimeout,       // keep-alive cap
+		MaxHeaderBytes:    cfg.HTTP.MaxHeaderBytes,
 	}
+	httpsrv.RegisterOnShutdown(chnlevts.Close) // end SSE streams so the drain can complete
+
+	// Graceful shutdown on SIGTERM/SIGINT:
+	//   1. stop accepting connections and drain in-flight requests
+	//   2. stop or detach remux children (shutdown.children)
+	sigctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	srvErr := make(chan error, 1)
+	go func() {
+		log.Info("running HTTP server", zap.String("addr", httpsrv.Addr))
+		srvErr <- httpsrv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-srvErr:
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatal("server failed", zap.Error(err))
+		}
+	case <-sigctx.Done():
+		stop() // a second signal kills the process immediately
+	}
+
+	mode, _ := processmgr.ParseShutdownMode(cfg.Shutdown.Children) // validated by config
+	log.Info("shutting down", zap.String("children", cfg.Shutdown.Children), zap.Duration("timeout", cfg.Shutdown.Timeout))
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Shutdown.Timeout)
+	defer cancel()
 
-	log.Info("running HTTP server", zap.String("addr", httpsrv.Addr))
-	if err := httpsrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatal("server failed", zap.Error(err))
+	if err := httpsrv.Shutdown(ctx); err != nil {
+		log.Warn("HTTP drain incomplete", zap.Error(err))
 	}
+	stopWatch()
+	chnlsvc.Shutdown(ctx, mode)
+	b2bclntsvc.Shutdown(ctx, mode)
+	webhooksvc.Shutdown(ctx)
+	if logfiles != nil {
+		logfiles.Close()
+	}
+
 	log.Info("server closed")
 }
 
-// handleVersion prints build metadata and exits when -v/--version is provided.
-func handleVersion() {
-	v := flag.Bool("v", false, "print version and exit")
-	flag.BoolVar(v, "version", false, "print version and exit")
+// parseFlags parses the command line and exits after printing build metadata when -v/--version is provided.
+func parseFlags() *config.Flags {
+	flags := config.BindFlags(flag.CommandLine)
 	flag.Parse()
 
-	if *v {
+	if flags.Version {
 		fmt.Printf("remux %s (commit %s, built %s)\n", config.Version, config.GitCommit, config.BuildDate)
 		os.Exit(0)
 	}
+	return flags
+}
+
+// printConfig writes the effective config to stdout as YAML, with secrets redacted.
+func printConfig(cfg *config.Config) {
+	out, err := cfg.Redacted().YAML()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zmux-server: render config: %v\n", err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(out)
 }
 
 // accessLog is a Gin middleware that records HTTP request/response details with Zap after handling.
@@ -229,6 +489,7 @@ func accessLog(log *zap.Logger, authsvc *service.AuthService) gin.HandlerFunc {
 			fields = append(fields, zap.Dict("auth",
 				zap.String("id", p.ID),
 				zap.String("kind", p.Kind.String()),
+				zap.String("role", string(p.Role)),
 			))
 		}
 		if joinedErr != nil {
@@ -248,26 +509,29 @@ func accessLog(log *zap.Logger, authsvc *service.AuthService) gin.HandlerFunc {
 
 // helpers
 
-func buildLogger() *zap.Logger {
+func buildLogger(cfg config.LogConfig) *zap.Logger {
 	logConfig := zap.NewDevelopmentConfig()
 	logConfig.EncoderConfig.TimeKey = ""
 	logConfig.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
 	logConfig.DisableStacktrace = true
 	logConfig.DisableCaller = true
-	logConfig.Level.SetLevel(zap.DebugLevel)
+	level, _ := zapcore.ParseLevel(cfg.Level) // already validated
+	logConfig.Level.SetLevel(level)
 	return zap.Must(logConfig.Build())
 }
 
-func buildRedisClient(addr string, db int) *redis.Client {
+func buildRedisClient(cfg config.RedisConfig) *redis.Client {
 	opts := &redis.Options{
-		Addr:         addr,
-		DB:           db,
-		DialTimeout:  5 * time.Second,
-		ReadTimeout:  3 * time.Second,
-		WriteTimeout: 3 * time.Second,
-		PoolSize:     10,
-		MinIdleConns: 5,
-		MaxRetries:   3,
+		Addr:         cfg.Addr,
+		Username:     cfg.Username,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		PoolSize:     cfg.PoolSize,
+		MinIdleConns: cfg.MinIdleConns,
+		MaxRetries:   cfg.MaxRetries,
 	}
 
 	return redis.NewClient(opts)
@@ -0,0 +1,396 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/edirooss/zmux-server/pkg/envelope"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the typed runtime configuration of zmux-server.
+//
+// Sources are merged in the following order (later wins):
+//
+//  1. Built-in defaults (Default)
+//  2. YAML file (--config / ZMUX_CONFIG; DefaultPath is optional)
+//  3. ZMUX_* environment variables
+//  4. Command-line flags
+//
+// The merged result is validated once at startup; constructors receive
+// already-validated values and never read the environment themselves.
+type Config struct {
+	Env      string         `yaml:"env"` // "prod" | "dev"
+	HTTP     HTTPConfig     `yaml:"http"`
+	Redis    RedisConfig    `yaml:"redis"`
+	Session  SessionConfig  `yaml:"session"`
+	Log      LogConfig      `yaml:"log"`
+	Admin    AdminConfig    `yaml:"admin"`
+	Process  ProcessConfig  `yaml:"process"`
+	Shutdown ShutdownConfig `yaml:"shutdown"`
+	Events   EventsConfig   `yaml:"events"`
+	Webhooks WebhooksConfig `yaml:"webhooks"`
+	Alerts   AlertsConfig   `yaml:"alerts"`
+	Usage    UsageConfig    `yaml:"usage"`
+	Audit    AuditConfig    `yaml:"audit"`
+	Secrets  SecretsConfig  `yaml:"secrets"`
+
+	ChannelLogs ChannelLogsConfig `yaml:"channel_logs"`
+}
+
+type HTTPConfig struct {
+	Addr              string        `yaml:"addr"`                // listen address
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"` // kills header-drip Slowloris
+	ReadTimeout       time.Duration `yaml:"read_timeout"`        // full request read (incl. body)
+	WriteTimeout      time.Duration `yaml:"write_timeout"`       // avoid forever-hangs on writes
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`        // keep-alive cap
+	MaxHeaderBytes    int           `yaml:"max_header_bytes"`    //
+	MaxBodyBytes      int64         `yaml:"max_body_bytes"`      // hard request body cap
+	TrustedProxies    []string      `yaml:"trusted_proxies"`     // prod only (behind Nginx)
+	CORSAllowOrigins  []string      `yaml:"cors_allow_origins"`  // dev only (local Vite dev)
+}
+
+type RedisConfig struct {
+	Addr         string        `yaml:"addr"`
+	Username     string        `yaml:"username"`
+	Password     string        `yaml:"password"` // secret
+	DB           int           `yaml:"db"`
+	DialTimeout  time.Duration `yaml:"dial_timeout"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	PoolSize     int           `yaml:"pool_size"`
+	MinIdleConns int           `yaml:"min_idle_conns"`
+	MaxRetries   int           `yaml:"max_retries"`
+}
+
+type SessionConfig struct {
+	Secret   string        `yaml:"secret"`    // secret; cookie signing key
+	MaxAge   time.Duration `yaml:"max_age"`   // cookie lifetime
+	PoolSize int           `yaml:"pool_size"` // session store Redis pool (shares Redis addr/db)
+}
+
+type LogConfig struct {
+	Level string `yaml:"level"` // debug | info | warn | error
+}
+
+// AdminConfig seeds the first admin user when the store holds none.
+// Ignored once any admin user exists.
+type AdminConfig struct {
+	BootstrapUsername string `yaml:"bootstrap_username"`
+	BootstrapPassword string `yaml:"bootstrap_password"` // secret
+}
+
+// ProcessConfig controls supervision of remux children.
+type ProcessConfig struct {
+	// StateDir holds child stdio and PID records so that children can outlive
+	// the server and be re-adopted on the next start. Empty disables adoption
+	// (children are tied to the server's lifetime).
+	StateDir string `yaml:"state_dir"`
+}
+
+// ShutdownConfig controls SIGTERM/SIGINT handling.
+type ShutdownConfig struct {
+	Timeout  time.Duration `yaml:"timeout"`  // deadline for draining HTTP and stopping children
+	Children string        `yaml:"children"` // stop | detach
+}
+
+// EventsConfig controls the channel event stream (GET /api/channels/events).
+type EventsConfig struct {
+	StatusInterval time.Duration `yaml:"status_interval"` // remux status polling period
+	Backlog        int           `yaml:"backlog"`         // recent events kept for Last-Event-ID resume
+	HistoryMaxLen  int64         `yaml:"history_max_len"` // events kept per channel in its Redis history stream
+}
+
+// WebhooksConfig controls webhook delivery (see /api/webhooks).
+type WebhooksConfig struct {
+	Workers     int           `yaml:"workers"`      // concurrent deliveries
+	Timeout     time.Duration `yaml:"timeout"`      // per attempt
+	MaxAttempts int           `yaml:"max_attempts"` // per event, first attempt included
+}
+
+// AlertsConfig controls alert rule evaluation (see /api/alerts).
+type AlertsConfig struct {
+	EvalInterval      time.Duration `yaml:"eval_interval"`      // rule evaluation period
+	ResolvedRetention time.Duration `yaml:"resolved_retention"` // how long resolved alerts stay listed
+}
+
+// UsageConfig controls B2B client usage metering (see /api/usage).
+type UsageConfig struct {
+	FlushInterval time.Duration `yaml:"flush_interval"` // how often accrued usage is written to Redis
+	Retention     time.Duration `yaml:"retention"`      // how long daily usage counters are kept
+}
+
+// AuditConfig controls the audit log (see /api/audit).
+type AuditConfig struct {
+	Retention time.Duration `yaml:"retention"` // how long audit records are kept
+}
+
+// SecretsConfig controls encryption of stored channel input credentials.
+type SecretsConfig struct {
+	// Keys are the key encryption keys, "<id>:<base64 32-byte key>"; the
+	// first is primary (encrypts), the rest only decrypt (rotation). Values
+	// under a non-primary key are re-encrypted at startup. Empty stores
+	// credentials in plaintext.
+	Keys []string `yaml:"keys"` // secret
+}
+
+// ChannelLogsConfig controls the optional on-disk copy of remux output.
+type ChannelLogsConfig struct {
+	Dir             string        `yaml:"dir"`               // one sub-directory per channel; empty disables
+	MaxSegmentBytes int64         `yaml:"max_segment_bytes"` // rotate at this size
+	MaxSegmentAge   time.Duration `yaml:"max_segment_age"`   // rotate at this age
+	MaxTotalBytes   int64         `yaml:"max_total_bytes"`   // retention cap across all channels
+	Retention       time.Duration `yaml:"retention"`         // delete older segments; 0 keeps until max_total_bytes
+}
+
+// Default returns the built-in configuration (matches the historical hard-coded values).
+func Default() *Config {
+	return &Config{
+		Env: "prod",
+		HTTP: HTTPConfig{
+			Addr:              "127.0.0.1:8080",
+			ReadHeaderTimeout: 2 * time.Second,
+			ReadTimeout:       10 * time.Second,
+			WriteTimeout:      15 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			MaxHeaderBytes:    1 << 20,  // 1MB
+			MaxBodyBytes:      10 << 20, // 10MB
+			TrustedProxies:    []string{"127.0.0.1"},
+			CORSAllowOrigins:  []string{"http://localhost:5173", "http://localhost:4173", "http://localhost:3000", "http://127.0.0.1:3000"},
+		},
+		Redis: RedisConfig{
+			Addr:         "127.0.0.1:6379",
+			DB:           0,
+			DialTimeout:  5 * time.Second,
+			ReadTimeout:  3 * time.Second,
+			WriteTimeout: 3 * time.Second,
+			PoolSize:     10,
+			MinIdleConns: 5,
+			MaxRetries:   3,
+		},
+		Session: SessionConfig{
+			Secret:   "nZCowo9+aofuYO/54sK2mca+aj8M9XA2zVLrP1kh6uk=", // TODO(security): rotate key; override in production
+			MaxAge:   4 * time.Hour,
+			PoolSize: 10,
+		},
+		Log: LogConfig{
+			Level: "debug",
+		},
+		Admin: AdminConfig{
+			BootstrapUsername: "admin",
+		},
+		Process: ProcessConfig{
+			StateDir: "/var/lib/zmux-server/proc",
+		},
+		Shutdown: ShutdownConfig{
+			Timeout:  10 * time.Second,
+			Children: "detach",
+		},
+		Events: EventsConfig{
+			StatusInterval: time.Second,
+			Backlog:        1024,
+			HistoryMaxLen:  1000,
+		},
+		Webhooks: WebhooksConfig{
+			Workers:     4,
+			Timeout:     10 * time.Second,
+			MaxAttempts: 6,
+		},
+		Alerts: AlertsConfig{
+			EvalInterval:      5 * time.Second,
+			ResolvedRetention: 24 * time.Hour,
+		},
+		Usage: UsageConfig{
+			FlushInterval: time.Minute,
+			Retention:     400 * 24 * time.Hour, // 13 months
+		},
+		Audit: AuditConfig{
+			Retention: 365 * 24 * time.Hour,
+		},
+		ChannelLogs: ChannelLogsConfig{
+			MaxSegmentBytes: 16 << 20, // 16MiB
+			MaxSegmentAge:   24 * time.Hour,
+			MaxTotalBytes:   1 << 30, // 1GiB
+			Retention:       30 * 24 * time.Hour,
+		},
+	}
+}
+
+// IsDev reports whether the server runs in development mode.
+func (c *Config) IsDev() bool { return c.Env == "dev" }
+
+// Validate checks the merged configuration. All violations are reported at once.
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...any) { errs = append(errs, fmt.Errorf(format, args...)) }
+
+	if c.Env != "prod" && c.Env != "dev" {
+		add("env: must be one of prod, dev (got %q)", c.Env)
+	}
+
+	// http
+	if _, _, err := net.SplitHostPort(c.HTTP.Addr); err != nil {
+		add("http.addr: %v", err)
+	}
+	for _, d := range []struct {
+		name string
+		val  time.Duration
+	}{
+		{"http.read_header_timeout", c.HTTP.ReadHeaderTimeout},
+		{"http.read_timeout", c.HTTP.ReadTimeout},
+		{"http.write_timeout", c.HTTP.WriteTimeout},
+		{"http.idle_timeout", c.HTTP.IdleTimeout},
+		{"redis.dial_timeout", c.Redis.DialTimeout},
+		{"redis.read_timeout", c.Redis.ReadTimeout},
+		{"redis.write_timeout", c.Redis.WriteTimeout},
+		{"session.max_age", c.Session.MaxAge},
+		{"shutdown.timeout", c.Shutdown.Timeout},
+		{"events.status_interval", c.Events.StatusInterval},
+		{"webhooks.timeout", c.Webhooks.Timeout},
+		{"alerts.eval_interval", c.Alerts.EvalInterval},
+		{"alerts.resolved_retention", c.Alerts.ResolvedRetention},
+		{"usage.flush_interval", c.Usage.FlushInterval},
+		{"usage.retention", c.Usage.Retention},
+		{"audit.retention", c.Audit.Retention},
+	} {
+		if d.val <= 0 {
+			add("%s: must be > 0 (got %s)", d.name, d.val)
+		}
+	}
+	if c.HTTP.MaxHeaderBytes <= 0 {
+		add("http.max_header_bytes: must be > 0")
+	}
+	if c.HTTP.MaxBodyBytes <= 0 {
+		add("http.max_body_bytes: must be > 0")
+	}
+	for i, p := range c.HTTP.TrustedProxies {
+		if net.ParseIP(p) == nil {
+			if _, _, err := net.ParseCIDR(p); err != nil {
+				add("http.trusted_proxies[%d]: invalid IP or CIDR %q", i, p)
+			}
+		}
+	}
+
+	// redis
+	if _, _, err := net.SplitHostPort(c.Redis.Addr); err != nil {
+		add("redis.addr: %v", err)
+	}
+	if c.Redis.DB < 0 {
+		add("redis.db: must be >= 0 (got %d)", c.Redis.DB)
+	}
+	if c.Redis.PoolSize <= 0 {
+		add("redis.pool_size: must be > 0")
+	}
+	if c.Redis.MinIdleConns < 0 || c.Redis.MinIdleConns > c.Redis.PoolSize {
+		add("redis.min_idle_conns: must be between 0 and redis.pool_size")
+	}
+	if c.Redis.MaxRetries < -1 {
+		add("redis.max_retries: must be >= -1")
+	}
+
+	// session
+	if len(c.Session.Secret) < 32 {
+		add("session.secret: must be at least 32 bytes")
+	}
+	if c.Session.PoolSize <= 0 {
+		add("session.pool_size: must be > 0")
+	}
+
+	// log
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		add("log.level: must be one of debug, info, warn, error (got %q)", c.Log.Level)
+	}
+
+	// admin
+	if c.Admin.BootstrapPassword != "" {
+		if c.Admin.BootstrapUsername == "" {
+			add("admin.bootstrap_username: required when admin.bootstrap_password is set")
+		}
+		if len(c.Admin.BootstrapPassword) < 8 {
+			add("admin.bootstrap_password: must be at least 8 characters")
+		}
+	}
+
+	// shutdown
+	switch c.Shutdown.Children {
+	case "stop":
+	case "detach":
+		if c.Process.StateDir == "" {
+			add("shutdown.children: detach requires process.state_dir")
+		}
+	default:
+		add("shutdown.children: must be one of stop, detach (got %q)", c.Shutdown.Children)
+	}
+
+	// events
+	if c.Events.Backlog <= 0 {
+		add("events.backlog: must be > 0")
+	}
+	if c.Events.HistoryMaxLen <= 0 {
+		add("events.history_max_len: must be > 0")
+	}
+
+	// webhooks
+	if c.Webhooks.Workers <= 0 {
+		add("webhooks.workers: must be > 0")
+	}
+	if c.Webhooks.MaxAttempts <= 0 {
+		add("webhooks.max_attempts: must be > 0")
+	}
+
+	// secrets
+	if _, err := envelope.ParseKeys(c.Secrets.Keys); err != nil {
+		add("secrets.keys: %v", err)
+	}
+
+	// channel_logs
+	if c.ChannelLogs.Dir != "" {
+		if c.ChannelLogs.MaxSegmentBytes <= 0 {
+			add("channel_logs.max_segment_bytes: must be > 0")
+		}
+		if c.ChannelLogs.MaxSegmentAge <= 0 {
+			add("channel_logs.max_segment_age: must be > 0")
+		}
+		if c.ChannelLogs.MaxTotalBytes < c.ChannelLogs.MaxSegmentBytes {
+			add("channel_logs.max_total_bytes: must be >= channel_logs.max_segment_bytes")
+		}
+		if c.ChannelLogs.Retention < 0 {
+			add("channel_logs.retention: must be >= 0")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Redacted returns a copy of the configuration with secrets masked; safe to print or log.
+func (c *Config) Redacted() *Config {
+	out := *c
+	out.HTTP.TrustedProxies = append([]string(nil), c.HTTP.TrustedProxies...)
+	out.HTTP.CORSAllowOrigins = append([]string(nil), c.HTTP.CORSAllowOrigins...)
+	out.Redis.Password = redact(c.Redis.Password)
+	out.Session.Secret = redact(c.Session.Secret)
+	out.Admin.BootstrapPassword = redact(c.Admin.BootstrapPassword)
+	out.Secrets.Keys = make([]string, len(c.Secrets.Keys))
+	for i, k := range c.Secrets.Keys {
+		id, _, _ := strings.Cut(k, ":")
+		out.Secrets.Keys[i] = id + ":" + redact(k)
+	}
+	return &out
+}
+
+// YAML renders the configuration as YAML (callers should render Redacted()).
+func (c *Config) YAML() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+func redact(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "<redacted>"
+}
@@ -0,0 +1,254 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPath is read when no explicit config path is given; a missing file is not an error.
+const DefaultPath = "/etc/zmux-server/config.yaml"
+
+// envPrefix namespaces all environment overrides (e.g. ZMUX_HTTP_ADDR).
+const envPrefix = "ZMUX_"
+
+// binding maps a single scalar setting to its environment variable and flag.
+//
+// The same table drives both ZMUX_* environment overrides and command-line
+// flags, so every overridable key is spelled identically in both places:
+//
+//	http.addr  →  ZMUX_HTTP_ADDR  →  --http-addr
+type binding struct {
+	key   string // dotted YAML key, e.g. "http.addr"
+	usage string
+	set   func(c *Config, v string) error
+}
+
+func (b binding) envName() string {
+	return envPrefix + strings.ToUpper(strings.NewReplacer(".", "_").Replace(b.key))
+}
+
+func (b binding) flagName() string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(b.key)
+}
+
+var bindings = []binding{
+	{"env", "runtime environment (prod|dev)", setString(func(c *Config) *string { return &c.Env })},
+
+	{"http.addr", "HTTP listen address", setString(func(c *Config) *string { return &c.HTTP.Addr })},
+	{"http.read_header_timeout", "HTTP read header timeout", setDuration(func(c *Config) *time.Duration { return &c.HTTP.ReadHeaderTimeout })},
+	{"http.read_timeout", "HTTP read timeout", setDuration(func(c *Config) *time.Duration { return &c.HTTP.ReadTimeout })},
+	{"http.write_timeout", "HTTP write timeout", setDuration(func(c *Config) *time.Duration { return &c.HTTP.WriteTimeout })},
+	{"http.idle_timeout", "HTTP keep-alive idle timeout", setDuration(func(c *Config) *time.Duration { return &c.HTTP.IdleTimeout })},
+	{"http.max_header_bytes", "HTTP max header bytes", setInt(func(c *Config) *int { return &c.HTTP.MaxHeaderBytes })},
+	{"http.max_body_bytes", "HTTP max request body bytes", setInt64(func(c *Config) *int64 { return &c.HTTP.MaxBodyBytes })},
+	{"http.trusted_proxies", "comma-separated trusted proxy IPs/CIDRs", setList(func(c *Config) *[]string { return &c.HTTP.TrustedProxies })},
+	{"http.cors_allow_origins", "comma-separated CORS origins (dev only)", setList(func(c *Config) *[]string { return &c.HTTP.CORSAllowOrigins })},
+
+	{"redis.addr", "Redis address (host:port)", setString(func(c *Config) *string { return &c.Redis.Addr })},
+	{"redis.username", "Redis ACL username", setString(func(c *Config) *string { return &c.Redis.Username })},
+	{"redis.password", "Redis password", setString(func(c *Config) *string { return &c.Redis.Password })},
+	{"redis.db", "Redis database index", setInt(func(c *Config) *int { return &c.Redis.DB })},
+	{"redis.dial_timeout", "Redis dial timeout", setDuration(func(c *Config) *time.Duration { return &c.Redis.DialTimeout })},
+	{"redis.read_timeout", "Redis read timeout", setDuration(func(c *Config) *time.Duration { return &c.Redis.ReadTimeout })},
+	{"redis.write_timeout", "Redis write timeout", setDuration(func(c *Config) *time.Duration { return &c.Redis.WriteTimeout })},
+	{"redis.pool_size", "Redis connection pool size", setInt(func(c *Config) *int { return &c.Redis.PoolSize })},
+	{"redis.min_idle_conns", "Redis minimum idle connections", setInt(func(c *Config) *int { return &c.Redis.MinIdleConns })},
+	{"redis.max_retries", "Redis max retries (-1 disables)", setInt(func(c *Config) *int { return &c.Redis.MaxRetries })},
+
+	{"session.secret", "session cookie signing secret (>= 32 bytes)", setString(func(c *Config) *string { return &c.Session.Secret })},
+	{"session.max_age", "session cookie lifetime", setDuration(func(c *Config) *time.Duration { return &c.Session.MaxAge })},
+	{"session.pool_size", "session store Redis pool size", setInt(func(c *Config) *int { return &c.Session.PoolSize })},
+
+	{"log.level", "log level (debug|info|warn|error)", setString(func(c *Config) *string { return &c.Log.Level })},
+
+	{"admin.bootstrap_username", "username of the first admin user (empty store only)", setString(func(c *Config) *string { return &c.Admin.BootstrapUsername })},
+	{"admin.bootstrap_password", "password of the first admin user (empty store only)", setString(func(c *Config) *string { return &c.Admin.BootstrapPassword })},
+
+	{"process.state_dir", "directory for child stdio and PID records (empty disables adoption)", setString(func(c *Config) *string { return &c.Process.StateDir })},
+
+	{"shutdown.timeout", "deadline for draining HTTP and stopping children", setDuration(func(c *Config) *time.Duration { return &c.Shutdown.Timeout })},
+	{"shutdown.children", "what to do with running children on shutdown (stop|detach)", setString(func(c *Config) *string { return &c.Shutdown.Children })},
+
+	{"events.status_interval", "remux status polling period of the channel event stream", setDuration(func(c *Config) *time.Duration { return &c.Events.StatusInterval })},
+	{"events.backlog", "channel events kept for Last-Event-ID resume", setInt(func(c *Config) *int { return &c.Events.Backlog })},
+	{"events.history_max_len", "events kept per channel in its history stream", setInt64(func(c *Config) *int64 { return &c.Events.HistoryMaxLen })},
+
+	{"webhooks.workers", "concurrent webhook deliveries", setInt(func(c *Config) *int { return &c.Webhooks.Workers })},
+	{"webhooks.timeout", "timeout of a single webhook delivery attempt", setDuration(func(c *Config) *time.Duration { return &c.Webhooks.Timeout })},
+	{"webhooks.max_attempts", "webhook delivery attempts per event (first included)", setInt(func(c *Config) *int { return &c.Webhooks.MaxAttempts })},
+
+	{"alerts.eval_interval", "alert rule evaluation period", setDuration(func(c *Config) *time.Duration { return &c.Alerts.EvalInterval })},
+	{"alerts.resolved_retention", "how long resolved alerts stay listed", setDuration(func(c *Config) *time.Duration { return &c.Alerts.ResolvedRetention })},
+
+	{"usage.flush_interval", "how often accrued B2B client usage is written to Redis", setDuration(func(c *Config) *time.Duration { return &c.Usage.FlushInterval })},
+	{"usage.retention", "how long daily B2B client usage counters are kept", setDuration(func(c *Config) *time.Duration { return &c.Usage.Retention })},
+
+	{"audit.retention", "how long audit records are kept", setDuration(func(c *Config) *time.Duration { return &c.Audit.Retention })},
+
+	{"secrets.keys", "comma-separated <id>:<base64 32-byte key> credential encryption keys, primary first", setList(func(c *Config) *[]string { return &c.Secrets.Keys })},
+
+	{"channel_logs.dir", "directory for on-disk channel logs (empty disables)", setString(func(c *Config) *string { return &c.ChannelLogs.Dir })},
+	{"channel_logs.max_segment_bytes", "rotate a channel log segment at this size", setInt64(func(c *Config) *int64 { return &c.ChannelLogs.MaxSegmentBytes })},
+	{"channel_logs.max_segment_age", "rotate a channel log segment at this age", setDuration(func(c *Config) *time.Duration { return &c.ChannelLogs.MaxSegmentAge })},
+	{"channel_logs.max_total_bytes", "retention cap for channel logs across all channels", setInt64(func(c *Config) *int64 { return &c.ChannelLogs.MaxTotalBytes })},
+	{"channel_logs.retention", "delete channel log segments older than this (0 disables)", setDuration(func(c *Config) *time.Duration { return &c.ChannelLogs.Retention })},
+}
+
+// Flags holds parsed command-line options.
+type Flags struct {
+	ConfigPath  string // --config
+	PrintConfig bool   // --print-config
+	Version     bool   // -v / --version
+
+	fs     *flag.FlagSet
+	values map[string]*string // binding key → raw flag value
+}
+
+// BindFlags registers all config flags on the given flag set. Call Parse before Load.
+func BindFlags(flagset *flag.FlagSet) *Flags {
+	f := &Flags{fs: flagset, values: make(map[string]*string, len(bindings))}
+
+	flagset.StringVar(&f.ConfigPath, "config", "", "path to YAML config file (default "+DefaultPath+" if present; env ZMUX_CONFIG)")
+	flagset.BoolVar(&f.PrintConfig, "print-config", false, "print the effective config (secrets redacted) and exit")
+	flagset.BoolVar(&f.Version, "v", false, "print version and exit")
+	flagset.BoolVar(&f.Version, "version", false, "print version and exit")
+
+	for _, b := range bindings {
+		f.values[b.key] = flagset.String(b.flagName(), "", b.usage+" (env "+b.envName()+")")
+	}
+	return f
+}
+
+// Load builds the effective configuration: defaults → file → env → flags.
+// The result is validated before it is returned.
+func Load(f *Flags) (*Config, error) {
+	cfg := Default()
+
+	// Legacy: ENV=dev predates the config subsystem; ZMUX_ENV / --env still win.
+	if os.Getenv("ENV") == "dev" {
+		cfg.Env = "dev"
+	}
+
+	// 1. file
+	path, explicit := DefaultPath, false
+	if p := os.Getenv(envPrefix + "CONFIG"); p != "" {
+		path, explicit = p, true
+	}
+	if f != nil && f.ConfigPath != "" {
+		path, explicit = f.ConfigPath, true
+	}
+	if err := loadFile(cfg, path, explicit); err != nil {
+		return nil, err
+	}
+
+	// 2. env
+	for _, b := range bindings {
+		if v, ok := os.LookupEnv(b.envName()); ok {
+			if err := b.set(cfg, v); err != nil {
+				return nil, fmt.Errorf("env %s: %w", b.envName(), err)
+			}
+		}
+	}
+
+	// 3. flags (only those explicitly set on the command line)
+	if f != nil && f.fs != nil {
+		visited := make(map[string]struct{})
+		f.fs.Visit(func(fl *flag.Flag) { visited[fl.Name] = struct{}{} })
+		for _, b := range bindings {
+			if _, ok := visited[b.flagName()]; !ok {
+				continue
+			}
+			if err := b.set(cfg, *f.values[b.key]); err != nil {
+				return nil, fmt.Errorf("flag --%s: %w", b.flagName(), err)
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config:\n%w", err)
+	}
+	return cfg, nil
+}
+
+// loadFile decodes YAML at path over cfg. Unknown keys are rejected to catch typos.
+func loadFile(cfg *Config, path string, explicit bool) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return nil // default path is optional
+		}
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// ----- setters --------------------------------------------------------------
+
+func setString(field func(*Config) *string) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		*field(c) = v
+		return nil
+	}
+}
+
+func setInt(field func(*Config) *int) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", v)
+		}
+		*field(c) = n
+		return nil
+	}
+}
+
+func setInt64(field func(*Config) *int64) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", v)
+		}
+		*field(c) = n
+		return nil
+	}
+}
+
+func setDuration(field func(*Config) *time.Duration) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", v)
+		}
+		*field(c) = d
+		return nil
+	}
+}
+
+func setList(field func(*Config) *[]string) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		out := make([]string, 0)
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+		*field(c) = out
+		return nil
+	}
+}
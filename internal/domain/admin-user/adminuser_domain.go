@@ -0,0 +1,69 @@
+package adminuser
+
+import "github.com/edirooss/zmux-server/internal/domain/principal"
+
+// Domain (Application Layer; Core runtime object)
+type AdminUser struct {
+	ID                int64
+	Username          string
+	PasswordHash      string
+	Enabled           bool
+	Role              principal.Role
+	CreatedAt         int64
+	PasswordChangedAt int64
+}
+
+// DB (Model) + ID → Domain
+func NewAdminUser(model *AdminUserModel, id int64) *AdminUser {
+	if model == nil {
+		return nil
+	}
+
+	role := principal.Role(model.Role)
+	if role == "" {
+		role = principal.RoleAdmin // records created before roles existed were full admins
+	}
+
+	return &AdminUser{
+		ID:                id,
+		Username:          model.Username,
+		PasswordHash:      model.PasswordHash,
+		Enabled:           model.Enabled,
+		Role:              role,
+		CreatedAt:         model.CreatedAt,
+		PasswordChangedAt: model.PasswordChangedAt,
+	}
+}
+
+// Domain → DB (Model)
+func (u *AdminUser) Model() *AdminUserModel {
+	if u == nil {
+		return nil
+	}
+
+	return &AdminUserModel{
+		Username:          u.Username,
+		PasswordHash:      u.PasswordHash,
+		Enabled:           u.Enabled,
+		Role:              string(u.Role),
+		CreatedAt:         u.CreatedAt,
+		PasswordChangedAt: u.PasswordChangedAt,
+	}
+}
+
+// Domain → API Response (View)
+func (u *AdminUser) View() *AdminUserView {
+	if u == nil {
+		return nil
+	}
+
+	return &AdminUserView{
+		ID:                u.ID,
+		Username:          u.Username,
+		Enabled:           u.Enabled,
+		Role:              u.Role,
+		Permissions:       u.Role.Permissions(),
+		CreatedAt:         u.CreatedAt,
+		PasswordChangedAt: u.PasswordChangedAt,
+	}
+}
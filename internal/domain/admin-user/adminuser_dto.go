@@ -0,0 +1,30 @@
+package adminuser
+
+import "github.com/edirooss/zmux-server/internal/domain/principal"
+
+// DTO (API Layer; Request schema)
+//   - POST: password required.
+//   - PUT:  password optional; null/omitted keeps the current password.
+type AdminUserResource struct {
+	Username string  `json:"username"`
+	Password *string `json:"password"`
+	Enabled  bool    `json:"enabled"`
+	Role     string  `json:"role"` // viewer | operator | admin
+}
+
+// DTO (API Layer; Self-service password change request)
+type PasswordChangeResource struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// DTO (API Layer; Response schema) — never exposes the password hash
+type AdminUserView struct {
+	ID                int64                 `json:"id"`
+	Username          string                `json:"username"`
+	Enabled           bool                  `json:"enabled"`
+	Role              principal.Role        `json:"role"`
+	Permissions       principal.Permissions `json:"permissions"`
+	CreatedAt         int64                 `json:"created_at"`
+	PasswordChangedAt int64                 `json:"password_changed_at"`
+}
@@ -0,0 +1,11 @@
+package adminuser
+
+// DB (Persistence Layer; Redis record)
+type AdminUserModel struct {
+	Username          string `json:"username"`
+	PasswordHash      string `json:"password_hash"` // PHC-encoded (argon2id; bcrypt accepted)
+	Enabled           bool   `json:"enabled"`
+	Role              string `json:"role,omitempty"`      // viewer | operator | admin; empty (pre-RBAC record) = admin
+	CreatedAt         int64  `json:"created_at"`          // UTC millis
+	PasswordChangedAt int64  `json:"password_changed_at"` // UTC millis
+}
@@ -0,0 +1,49 @@
+package adminuser
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/edirooss/zmux-server/internal/domain/principal"
+)
+
+// ValidateUsername enforces 3–64 characters of [A-Za-z0-9._-].
+func ValidateUsername(username string) error {
+	if len(username) < 3 || len(username) > 64 {
+		return errors.New("username must be between 3 and 64 characters")
+	}
+	for _, r := range username {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-') {
+			return fmt.Errorf("username contains invalid character %q (allowed: letters, digits, '.', '_', '-')", r)
+		}
+	}
+	return nil
+}
+
+// ValidatePassword enforces 8–128 characters.
+func ValidatePassword(password string) error {
+	if len(password) < 8 {
+		return errors.New("password must be at least 8 characters")
+	}
+	if len(password) > 128 {
+		return errors.New("password must be at most 128 characters")
+	}
+	return nil
+}
+
+// Validate checks the request. requirePassword is true on create (POST).
+func (r *AdminUserResource) Validate(requirePassword bool) error {
+	if err := ValidateUsername(r.Username); err != nil {
+		return err
+	}
+	if _, err := principal.ParseRole(r.Role); err != nil {
+		return err
+	}
+	if r.Password == nil {
+		if requirePassword {
+			return errors.New("password is required")
+		}
+		return nil
+	}
+	return ValidatePassword(*r.Password)
+}
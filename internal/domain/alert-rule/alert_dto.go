@@ -0,0 +1,36 @@
+package alertrule
+
+// AlertState is the lifecycle state of an alert.
+type AlertState string
+
+const (
+	AlertPending  AlertState = "pending"  // condition holds, waiting out the rule's for_sec
+	AlertFiring   AlertState = "firing"   // condition held for for_sec; notified
+	AlertResolved AlertState = "resolved" // condition cleared after firing; notified
+)
+
+// DTO (API Layer; Response schema)
+//
+// An alert is one rule instance for one subject (channel, B2B client or
+// B2B client output ref). ID is a stable fingerprint of (rule, subject):
+// re-evaluations update the same alert instead of raising duplicates.
+type AlertView struct {
+	ID          string     `json:"id"`
+	RuleID      int64      `json:"rule_id"`
+	RuleName    string     `json:"rule_name"`
+	Kind        string     `json:"kind"`
+	Severity    string     `json:"severity"`
+	State       AlertState `json:"state"`
+	ChannelID   *int64     `json:"channel_id,omitempty"`
+	B2BClientID *int64     `json:"b2b_client_id,omitempty"`
+	OutputRef   string     `json:"output_ref,omitempty"`
+	Value       float64    `json:"value"` // last observed value (offline seconds, metric, restarts, quota percent)
+	Threshold   float64    `json:"threshold"`
+	Message     string     `json:"message"`
+
+	StartsAt      int64 `json:"starts_at"`                // UTC millis when the condition was first seen
+	FiredAt       int64 `json:"fired_at,omitempty"`       // UTC millis
+	ResolvedAt    int64 `json:"resolved_at,omitempty"`    // UTC millis
+	SilencedUntil int64 `json:"silenced_until,omitempty"` // UTC millis; alert-level silence
+	Silenced      bool  `json:"silenced"`                 // alert or rule silence in effect
+}
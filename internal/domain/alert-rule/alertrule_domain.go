@@ -0,0 +1,159 @@
+package alertrule
+
+import (
+	"slices"
+	"time"
+)
+
+// Kind is the condition evaluated by a rule.
+type Kind string
+
+const (
+	KindChannelOffline     Kind = "channel_offline"      // enabled channel not online
+	KindChannelMetricBelow Kind = "channel_metric_below" // remux metric < threshold (online channels)
+	KindChannelMetricAbove Kind = "channel_metric_above" // remux metric > threshold (online channels)
+	KindChannelRestarts    Kind = "channel_restarts"     // more than count restarts within window
+	KindB2BQuota           Kind = "b2b_quota"            // quota usage >= threshold percent
+)
+
+// Kinds lists the supported rule kinds.
+var Kinds = []Kind{KindChannelOffline, KindChannelMetricBelow, KindChannelMetricAbove, KindChannelRestarts, KindB2BQuota}
+
+// Quota names of b2b_quota rules.
+const (
+	QuotaEnabledChannels = "enabled_channels"
+	QuotaOnlineChannels  = "online_channels"
+	QuotaEnabledOutputs  = "enabled_outputs" // evaluated per output ref
+)
+
+// Severities lists the accepted severity labels.
+var Severities = []string{"info", "warning", "critical"}
+
+// Domain (Application Layer; Core runtime object)
+type AlertRule struct {
+	ID       int64
+	Name     string
+	Kind     Kind
+	Severity string
+	Enabled  bool
+
+	For       time.Duration
+	Metric    string
+	Threshold float64
+	Count     int64
+	Window    time.Duration
+	Quota     string
+
+	ChannelIDs   []int64
+	B2BClientIDs []int64
+
+	SilencedUntil int64
+	CreatedAt     int64
+	UpdatedAt     int64
+}
+
+// DB (Model) + ID → Domain
+func NewAlertRule(model *AlertRuleModel, id int64) *AlertRule {
+	if model == nil {
+		return nil
+	}
+
+	return &AlertRule{
+		ID:            id,
+		Name:          model.Name,
+		Kind:          Kind(model.Kind),
+		Severity:      model.Severity,
+		Enabled:       model.Enabled,
+		For:           time.Duration(model.ForSec) * time.Second,
+		Metric:        model.Metric,
+		Threshold:     model.Threshold,
+		Count:         model.Count,
+		Window:        time.Duration(model.WindowSec) * time.Second,
+		Quota:         model.Quota,
+		ChannelIDs:    slices.Clone(model.ChannelIDs),
+		B2BClientIDs:  slices.Clone(model.B2BClientIDs),
+		SilencedUntil: model.SilencedUntil,
+		CreatedAt:     model.CreatedAt,
+		UpdatedAt:     model.UpdatedAt,
+	}
+}
+
+// Domain → DB (Model)
+func (r *AlertRule) Model() *AlertRuleModel {
+	if r == nil {
+		return nil
+	}
+
+	return &AlertRuleModel{
+		Name:          r.Name,
+		Kind:          string(r.Kind),
+		Severity:      r.Severity,
+		Enabled:       r.Enabled,
+		ForSec:        int64(r.For / time.Second),
+		Metric:        r.Metric,
+		Threshold:     r.Threshold,
+		Count:         r.Count,
+		WindowSec:     int64(r.Window / time.Second),
+		Quota:         r.Quota,
+		ChannelIDs:    slices.Clone(r.ChannelIDs),
+		B2BClientIDs:  slices.Clone(r.B2BClientIDs),
+		SilencedUntil: r.SilencedUntil,
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+	}
+}
+
+// Domain → API Response (View)
+func (r *AlertRule) View() *AlertRuleView {
+	if r == nil {
+		return nil
+	}
+
+	return &AlertRuleView{
+		ID: r.ID,
+		AlertRuleResource: AlertRuleResource{
+			Name:          r.Name,
+			Kind:          string(r.Kind),
+			Severity:      r.Severity,
+			Enabled:       r.Enabled,
+			ForSec:        int64(r.For / time.Second),
+			Metric:        r.Metric,
+			Threshold:     r.Threshold,
+			Count:         r.Count,
+			WindowSec:     int64(r.Window / time.Second),
+			Quota:         r.Quota,
+			ChannelIDs:    nonNil(r.ChannelIDs),
+			B2BClientIDs:  nonNil(r.B2BClientIDs),
+			SilencedUntil: r.SilencedUntil,
+		},
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
+
+// MatchesChannel reports whether a channel (owned by b2bclntID; nil: no
+// owner) is in the rule's scope.
+func (r *AlertRule) MatchesChannel(chID int64, b2bclntID *int64) bool {
+	if len(r.ChannelIDs) > 0 && !slices.Contains(r.ChannelIDs, chID) {
+		return false
+	}
+	if len(r.B2BClientIDs) > 0 && (b2bclntID == nil || !slices.Contains(r.B2BClientIDs, *b2bclntID)) {
+		return false
+	}
+	return true
+}
+
+// MatchesB2BClient reports whether a B2B client is in the rule's scope.
+func (r *AlertRule) MatchesB2BClient(b2bclntID int64) bool {
+	return len(r.B2BClientIDs) == 0 || slices.Contains(r.B2BClientIDs, b2bclntID)
+}
+
+// Silenced reports whether notifications are suppressed at now (UTC millis).
+func (r *AlertRule) Silenced(now int64) bool { return r.SilencedUntil > now }
+
+func nonNil(ids []int64) []int64 {
+	if ids == nil {
+		return []int64{}
+	}
+	return slices.Clone(ids)
+}
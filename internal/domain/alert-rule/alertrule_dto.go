@@ -0,0 +1,40 @@
+package alertrule
+
+// DTO (API Layer; Request schema)
+//
+// Parameters by kind:
+//   - channel_offline:      for_sec
+//   - channel_metric_below: metric, threshold, for_sec
+//   - channel_metric_above: metric, threshold, for_sec
+//   - channel_restarts:     count, window_sec
+//   - b2b_quota:            quota, threshold (percent of quota), for_sec
+//
+// channel_ids/b2b_client_ids narrow the rule's scope (empty: all). Channel
+// rules accept both (a channel matches when owned by a listed client);
+// b2b_quota accepts b2b_client_ids only.
+type AlertRuleResource struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Severity string `json:"severity"` // info | warning | critical
+	Enabled  bool   `json:"enabled"`
+
+	ForSec    int64   `json:"for_sec"`
+	Metric    string  `json:"metric"` // dotted path into remux:<id>:metrics, e.g. "input.bitrate"
+	Threshold float64 `json:"threshold"`
+	Count     int64   `json:"count"`
+	WindowSec int64   `json:"window_sec"`
+	Quota     string  `json:"quota"` // enabled_channels | online_channels | enabled_outputs
+
+	ChannelIDs   []int64 `json:"channel_ids"`
+	B2BClientIDs []int64 `json:"b2b_client_ids"`
+
+	SilencedUntil int64 `json:"silenced_until"` // UTC millis; notifications suppressed until then
+}
+
+// DTO (API Layer; Response schema)
+type AlertRuleView struct {
+	ID int64 `json:"id"`
+	AlertRuleResource
+	CreatedAt int64 `json:"created_at"`
+	UpdatedAt int64 `json:"updated_at"`
+}
@@ -0,0 +1,48 @@
+package alertrule
+
+// DB (Persistence Layer; Redis record)
+type AlertRuleModel struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Severity string `json:"severity"`
+	Enabled  bool   `json:"enabled"`
+
+	ForSec    int64   `json:"for_sec,omitempty"`
+	Metric    string  `json:"metric,omitempty"`
+	Threshold float64 `json:"threshold,omitempty"`
+	Count     int64   `json:"count,omitempty"`
+	WindowSec int64   `json:"window_sec,omitempty"`
+	Quota     string  `json:"quota,omitempty"`
+
+	ChannelIDs   []int64 `json:"channel_ids,omitempty"`
+	B2BClientIDs []int64 `json:"b2b_client_ids,omitempty"`
+
+	SilencedUntil int64 `json:"silenced_until,omitempty"` // UTC millis
+	CreatedAt     int64 `json:"created_at"`               // UTC millis
+	UpdatedAt     int64 `json:"updated_at"`               // UTC millis
+}
+
+// API Request (Resource) + timestamps → DB (Model)
+func NewAlertRuleModel(r *AlertRuleResource, createdAt, updatedAt int64) *AlertRuleModel {
+	if r == nil {
+		return nil
+	}
+
+	return &AlertRuleModel{
+		Name:          r.Name,
+		Kind:          r.Kind,
+		Severity:      r.Severity,
+		Enabled:       r.Enabled,
+		ForSec:        r.ForSec,
+		Metric:        r.Metric,
+		Threshold:     r.Threshold,
+		Count:         r.Count,
+		WindowSec:     r.WindowSec,
+		Quota:         r.Quota,
+		ChannelIDs:    append([]int64(nil), r.ChannelIDs...),
+		B2BClientIDs:  append([]int64(nil), r.B2BClientIDs...),
+		SilencedUntil: r.SilencedUntil,
+		CreatedAt:     createdAt,
+		UpdatedAt:     updatedAt,
+	}
+}
@@ -0,0 +1,61 @@
+package alertrule
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Validate checks the request, including the parameters required by its kind.
+func (r *AlertRuleResource) Validate() error {
+	if strings.TrimSpace(r.Name) == "" || len(r.Name) > 128 {
+		return errors.New("name must be between 1 and 128 characters")
+	}
+	if !slices.Contains(Kinds, Kind(r.Kind)) {
+		return fmt.Errorf("kind: must be one of %v (got %q)", Kinds, r.Kind)
+	}
+	if !slices.Contains(Severities, r.Severity) {
+		return fmt.Errorf("severity: must be one of %v (got %q)", Severities, r.Severity)
+	}
+	if r.ForSec < 0 {
+		return errors.New("for_sec must be >= 0")
+	}
+	if r.SilencedUntil < 0 {
+		return errors.New("silenced_until must be >= 0")
+	}
+
+	switch Kind(r.Kind) {
+	case KindChannelMetricBelow, KindChannelMetricAbove:
+		if r.Metric == "" {
+			return errors.New("metric is required")
+		}
+		for _, part := range strings.Split(r.Metric, ".") {
+			if part == "" {
+				return fmt.Errorf("metric: invalid path %q", r.Metric)
+			}
+		}
+
+	case KindChannelRestarts:
+		if r.Count <= 0 {
+			return errors.New("count must be > 0")
+		}
+		if r.WindowSec <= 0 {
+			return errors.New("window_sec must be > 0")
+		}
+
+	case KindB2BQuota:
+		switch r.Quota {
+		case QuotaEnabledChannels, QuotaOnlineChannels, QuotaEnabledOutputs:
+		default:
+			return fmt.Errorf("quota: must be one of %s, %s, %s (got %q)", QuotaEnabledChannels, QuotaOnlineChannels, QuotaEnabledOutputs, r.Quota)
+		}
+		if r.Threshold <= 0 || r.Threshold > 100 {
+			return errors.New("threshold must be a percentage in (0, 100]")
+		}
+		if len(r.ChannelIDs) > 0 {
+			return errors.New("channel_ids is not applicable to b2b_quota rules")
+		}
+	}
+	return nil
+}
@@ -1,38 +1,112 @@
 package b2bclient
 
+import (
+	"slices"
+	"time"
+
+	"github.com/edirooss/zmux-server/internal/domain/principal"
+)
+
 // Domain (Application Layer; Core runtime object)
 type B2BClient struct {
-	ID          int64
-	Name        string
-	BearerToken string
-	Quotas      Quotas
+	ID     int64
+	Name   string
+	Tokens []Token
+	Quotas Quotas
+
+	ChannelTemplate *ChannelTemplate // nil: no self-service channel creation
+	Policy          Policy
 }
 
+// legacyTokenID identifies the token migrated from the single plaintext
+// bearer_token of records predating named tokens.
+const legacyTokenID = "legacy"
+
 // DB (Model) + ID → Domain
 func NewB2BClient(model *B2BClientModel, id int64) *B2BClient {
 	if model == nil {
 		return nil
 	}
 
+	tokens := make([]Token, 0, len(model.Tokens)+1)
+	for i := range model.Tokens {
+		tokens = append(tokens, NewToken(&model.Tokens[i]))
+	}
+	if model.BearerToken != "" {
+		tokens = append(tokens, Token{
+			ID:    legacyTokenID,
+			Name:  "default",
+			Hash:  HashToken(model.BearerToken),
+			Scope: principal.ScopeReadWrite,
+		})
+	}
+
 	return &B2BClient{
-		ID:          id,
-		Name:        model.Name,
-		BearerToken: model.BearerToken,
-		Quotas:      NewQuotas(&model.Quotas),
+		ID:     id,
+		Name:   model.Name,
+		Tokens: tokens,
+		Quotas: NewQuotas(&model.Quotas),
+
+		ChannelTemplate: model.ChannelTemplate.Clone(),
+		Policy:          model.Policy.Clone(),
 	}
 }
 
+// Domain → DB (Model)
+func (c *B2BClient) Model() *B2BClientModel {
+	if c == nil {
+		return nil
+	}
+
+	return &B2BClientModel{
+		Name:   c.Name,
+		Tokens: c.TokenModels(),
+		Quotas: c.Quotas.Model(),
+
+		ChannelTemplate: c.ChannelTemplate.Clone(),
+		Policy:          c.Policy.Clone(),
+	}
+}
+
+// TokenModels returns the persistence models of the client's tokens.
+func (c *B2BClient) TokenModels() []TokenModel {
+	out := make([]TokenModel, len(c.Tokens))
+	for i, t := range c.Tokens {
+		out[i] = t.Model()
+	}
+	return out
+}
+
+// UsesOutputRef reports whether the client's quotas, policy or channel
+// template name the output ref.
+func (c *B2BClient) UsesOutputRef(ref string) bool {
+	if slices.ContainsFunc(c.Quotas.EnabledOutputs, func(o EnabledOutput) bool { return o.Ref == ref }) ||
+		slices.Contains(c.Policy.OutputRefs, ref) {
+		return true
+	}
+	return c.ChannelTemplate != nil &&
+		slices.ContainsFunc(c.ChannelTemplate.Outputs, func(o ChannelTemplateOutput) bool { return o.Ref == ref })
+}
+
 // Domain + Nested views → API Response (View)
-func (c *B2BClient) View(enabledChannelsUsage int64, enabledOutputsUsage map[string]int64, onlineChannelsUsage int64, channelIDs []int64) *B2BClientView {
+func (c *B2BClient) View(enabledChannelsUsage int64, enabledOutputsUsage map[string]int64, onlineChannelsUsage int64, channelIDs []int64, now time.Time) *B2BClientView {
 	if c == nil {
 		return nil
 	}
 
+	tokens := make([]TokenView, len(c.Tokens))
+	for i, t := range c.Tokens {
+		tokens[i] = t.View(now)
+	}
+
 	return &B2BClientView{
-		ID:          c.ID,
-		Name:        c.Name,
-		BearerToken: c.BearerToken,
-		Quotas:      c.Quotas.View(enabledChannelsUsage, enabledOutputsUsage, onlineChannelsUsage),
-		ChannelIDs:  append(make([]int64, 0), channelIDs...),
+		ID:         c.ID,
+		Name:       c.Name,
+		Tokens:     tokens,
+		Quotas:     c.Quotas.View(int64(len(channelIDs)), enabledChannelsUsage, enabledOutputsUsage, onlineChannelsUsage),
+		ChannelIDs: append(make([]int64, 0), channelIDs...),
+
+		ChannelTemplate: c.ChannelTemplate.Clone(),
+		Policy:          c.Policy.Clone(),
 	}
 }
@@ -1,16 +1,22 @@
 package b2bclient
 
-// DTO (API Layer; Request schema) — same as model minus BearerToken
+// DTO (API Layer; Request schema) — same as model minus Tokens
 type B2BClientResource struct {
-	Name   string         `json:"name"`
-	Quotas QuotasResource `json:"quotas"`
+	Name            string           `json:"name"`
+	Quotas          QuotasResource   `json:"quotas"`
+	ChannelTemplate *ChannelTemplate `json:"channel_template"` // nullable (null: no self-service channel creation)
+	Policy          Policy           `json:"policy"`           // input/output restrictions of the client's channels
 }
 
 // DTO (API Layer; Response schema)
 type B2BClientView struct {
-	ID          int64      `json:"id"`
-	Name        string     `json:"name"`
-	BearerToken string     `json:"bearer_token"`
-	Quotas      QuotasView `json:"quotas"`
-	ChannelIDs  []int64    `json:"channel_ids"`
+	ID          int64       `json:"id"`
+	Name        string      `json:"name"`
+	BearerToken string      `json:"bearer_token,omitempty"` // initial token secret; create response only
+	Tokens      []TokenView `json:"tokens"`
+	Quotas      QuotasView  `json:"quotas"`
+	ChannelIDs  []int64     `json:"channel_ids"`
+
+	ChannelTemplate *ChannelTemplate `json:"channel_template"`
+	Policy          Policy           `json:"policy"`
 }
@@ -2,20 +2,27 @@ package b2bclient
 
 // DB (Persistence Layer; Redis record)
 type B2BClientModel struct {
-	Name        string      `json:"name"`
-	BearerToken string      `json:"bearer_token"`
-	Quotas      QuotasModel `json:"quotas"`
+	Name        string       `json:"name"`
+	BearerToken string       `json:"bearer_token,omitempty"` // legacy plaintext token; migrated into Tokens on load
+	Tokens      []TokenModel `json:"tokens"`
+	Quotas      QuotasModel  `json:"quotas"`
+
+	ChannelTemplate *ChannelTemplate `json:"channel_template"` // nullable
+	Policy          Policy           `json:"policy"`
 }
 
-// API Request (Resource) + BearerToken → DB (Model)
-func NewB2BClientModel(r *B2BClientResource, bearerToken string) *B2BClientModel {
+// API Request (Resource) + Tokens → DB (Model)
+func NewB2BClientModel(r *B2BClientResource, tokens []TokenModel) *B2BClientModel {
 	if r == nil {
 		return nil
 	}
 
 	return &B2BClientModel{
-		Name:        r.Name,
-		BearerToken: bearerToken,
-		Quotas:      NewQuotasModel(&r.Quotas),
+		Name:   r.Name,
+		Tokens: tokens,
+		Quotas: NewQuotasModel(&r.Quotas),
+
+		ChannelTemplate: r.ChannelTemplate.Clone(),
+		Policy:          r.Policy.Clone(),
 	}
 }
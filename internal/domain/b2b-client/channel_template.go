@@ -0,0 +1,157 @@
+package b2bclient
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/edirooss/zmux-server/internal/domain/channel"
+)
+
+// ChannelTemplate holds the admin-defined settings of the channels a B2B
+// client creates itself: everything outside the B2B client channel view
+// (name, enabled, input url/credentials, output enabled). Clients without a
+// template cannot create channels.
+//
+// Shared by all layers (resource, model, domain, view). Omitted fields get the
+// POST /api/channels defaults.
+type ChannelTemplate struct {
+	Input      ChannelTemplateInput    `json:"input"`
+	Outputs    []ChannelTemplateOutput `json:"outputs"`
+	RestartSec uint                    `json:"restart_sec"`
+
+	RestartMaxDelaySec    uint `json:"restart_max_delay_sec"`
+	RestartResetSec       uint `json:"restart_reset_sec"`
+	StartLimitBurst       uint `json:"start_limit_burst"`
+	StartLimitIntervalSec uint `json:"start_limit_interval_sec"`
+}
+
+type ChannelTemplateInput struct {
+	AVIOFlags       *string `json:"avioflags"`       // nullable
+	Probesize       uint    `json:"probesize"`       //
+	Analyzeduration uint    `json:"analyzeduration"` //
+	FFlags          *string `json:"fflags"`          // nullable
+	MaxDelay        int     `json:"max_delay"`       //
+	Localaddr       *string `json:"localaddr"`       // nullable
+	Timeout         uint    `json:"timeout"`         //
+	RTSPTransport   *string `json:"rtsp_transport"`  // nullable
+}
+
+type ChannelTemplateOutput struct {
+	Ref           string   `json:"ref"`            //
+	URL           *string  `json:"url"`            // nullable
+	Localaddr     *string  `json:"localaddr"`      // nullable
+	PktSize       uint     `json:"pkt_size"`       //
+	StreamMapping []string `json:"stream_mapping"` //
+	Enabled       bool     `json:"enabled"`        // used when the client does not set it
+}
+
+func (t *ChannelTemplate) UnmarshalJSON(b []byte) error {
+	type plain ChannelTemplate
+	p := plain{
+		Input:                 defaultChannelTemplateInput(),
+		Outputs:               []ChannelTemplateOutput{},
+		RestartSec:            channel.DefaultRestartSec,
+		RestartMaxDelaySec:    channel.DefaultRestartMaxDelaySec,
+		RestartResetSec:       channel.DefaultRestartResetSec,
+		StartLimitBurst:       channel.DefaultStartLimitBurst,
+		StartLimitIntervalSec: channel.DefaultStartLimitIntervalSec,
+	}
+	if err := json.Unmarshal(b, &p); err != nil {
+		return err
+	}
+	*t = ChannelTemplate(p)
+	return nil
+}
+
+func (in *ChannelTemplateInput) UnmarshalJSON(b []byte) error {
+	type plain ChannelTemplateInput
+	p := plain(defaultChannelTemplateInput())
+	if err := json.Unmarshal(b, &p); err != nil {
+		return err
+	}
+	*in = ChannelTemplateInput(p)
+	return nil
+}
+
+func defaultChannelTemplateInput() ChannelTemplateInput {
+	fflags := "nobuffer"
+	return ChannelTemplateInput{
+		Probesize: 5000000,
+		FFlags:    &fflags,
+		MaxDelay:  -1,
+		Timeout:   3000000,
+	}
+}
+
+func (o *ChannelTemplateOutput) UnmarshalJSON(b []byte) error {
+	type plain ChannelTemplateOutput
+	p := plain{
+		PktSize:       1316,
+		StreamMapping: []string{"video"},
+		Enabled:       true,
+	}
+	if err := json.Unmarshal(b, &p); err != nil {
+		return err
+	}
+	*o = ChannelTemplateOutput(p)
+	return nil
+}
+
+// Clone returns a deep copy (nil for nil).
+func (t *ChannelTemplate) Clone() *ChannelTemplate {
+	if t == nil {
+		return nil
+	}
+
+	out := *t
+	out.Input.AVIOFlags = cloneString(t.Input.AVIOFlags)
+	out.Input.FFlags = cloneString(t.Input.FFlags)
+	out.Input.Localaddr = cloneString(t.Input.Localaddr)
+	out.Input.RTSPTransport = cloneString(t.Input.RTSPTransport)
+	out.Outputs = make([]ChannelTemplateOutput, len(t.Outputs))
+	for i, o := range t.Outputs {
+		o.URL = cloneString(o.URL)
+		o.Localaddr = cloneString(o.Localaddr)
+		o.StreamMapping = slices.Clone(o.StreamMapping)
+		out.Outputs[i] = o
+	}
+	return &out
+}
+
+// NewChannel returns a disabled, unnamed channel of the B2B client built from
+// the template (no shared memory); callers fill in the client-owned fields.
+func (t *ChannelTemplate) NewChannel(b2bClientID int64) *channel.ZmuxChannel {
+	c := t.Clone()
+
+	outputs := make([]channel.ZmuxChannelOutput, len(c.Outputs))
+	for i, o := range c.Outputs {
+		outputs[i] = channel.ZmuxChannelOutput{
+			Ref:           o.Ref,
+			URL:           o.URL,
+			Localaddr:     o.Localaddr,
+			PktSize:       o.PktSize,
+			StreamMapping: o.StreamMapping,
+			Enabled:       o.Enabled,
+		}
+	}
+
+	return &channel.ZmuxChannel{
+		B2BClientID: &b2bClientID,
+		Input: channel.ZmuxChannelInput{
+			AVIOFlags:       c.Input.AVIOFlags,
+			Probesize:       c.Input.Probesize,
+			Analyzeduration: c.Input.Analyzeduration,
+			FFlags:          c.Input.FFlags,
+			MaxDelay:        c.Input.MaxDelay,
+			Localaddr:       c.Input.Localaddr,
+			Timeout:         c.Input.Timeout,
+			RTSPTransport:   c.Input.RTSPTransport,
+		},
+		Outputs:               outputs,
+		RestartSec:            c.RestartSec,
+		RestartMaxDelaySec:    c.RestartMaxDelaySec,
+		RestartResetSec:       c.RestartResetSec,
+		StartLimitBurst:       c.StartLimitBurst,
+		StartLimitIntervalSec: c.StartLimitIntervalSec,
+	}
+}
@@ -7,3 +7,19 @@ func SliceToMapByRef(items []EnabledOutputView) map[string]EnabledOutputView {
 	}
 	return out
 }
+
+func cloneInt64(p *int64) *int64 {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+func cloneString(p *string) *string {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
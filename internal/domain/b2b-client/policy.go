@@ -0,0 +1,199 @@
+package b2bclient
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/edirooss/zmux-server/internal/domain/channel"
+	"github.com/edirooss/zmux-server/pkg/avurl"
+)
+
+// ErrPolicyViolation is wrapped by every Policy check failure.
+var ErrPolicyViolation = errors.New("policy violation")
+
+// Policy restricts the inputs and outputs of a B2B client's channels. Empty
+// lists impose no restriction (records predating policies).
+//
+// Host rules are CIDRs (10.0.0.0/8), IP addresses, or case-insensitive
+// hostname patterns (path.Match syntax, e.g. *.example.com). IPv4-mapped IPv6
+// rules (::ffff:10.0.0.0/104) are treated as their IPv4 form. Hostnames are
+// not resolved: CIDRs only match IP literal hosts, and while denied_hosts
+// holds a CIDR a hostname must match an allowed_hosts pattern (it could
+// otherwise resolve into the denied range).
+//
+// Shared by all layers (resource, model, domain, view).
+type Policy struct {
+	InputSchemes []string `json:"input_schemes"` // allowed input.url schemes (e.g. rtsp, srt)
+	AllowedHosts []string `json:"allowed_hosts"` // input.url host must match one of these
+	DeniedHosts  []string `json:"denied_hosts"`  // input.url host must match none of these (checked first)
+	OutputRefs   []string `json:"output_refs"`   // outputs the client's channels may enable
+}
+
+var schemeRe = regexp.MustCompile(`^[a-z][a-z0-9+.-]*$`)
+
+// Validate checks the policy rules.
+func (p *Policy) Validate() error {
+	for i, s := range p.InputSchemes {
+		if !schemeRe.MatchString(s) {
+			return fmt.Errorf("input_schemes[%d]: invalid scheme %q (lowercase, e.g. srt)", i, s)
+		}
+	}
+	for _, f := range []struct {
+		name  string
+		rules []string
+	}{
+		{"allowed_hosts", p.AllowedHosts},
+		{"denied_hosts", p.DeniedHosts},
+	} {
+		for i, s := range f.rules {
+			if _, err := parseHostRule(s); err != nil {
+				return fmt.Errorf("%s[%d]: %w", f.name, i, err)
+			}
+		}
+	}
+	for i, ref := range p.OutputRefs {
+		if ref == "" {
+			return fmt.Errorf("output_refs[%d] must not be empty", i)
+		}
+	}
+	return nil
+}
+
+// Clone returns a deep copy.
+func (p Policy) Clone() Policy {
+	return Policy{
+		InputSchemes: slices.Clone(p.InputSchemes),
+		AllowedHosts: slices.Clone(p.AllowedHosts),
+		DeniedHosts:  slices.Clone(p.DeniedHosts),
+		OutputRefs:   slices.Clone(p.OutputRefs),
+	}
+}
+
+// Check validates a channel write against the policy: the input URL when it
+// changed, and outputs that became enabled. prev is nil on create (or when the
+// channel moves to this client), so everything is checked.
+//
+// Settings that predate the policy are kept, e.g. a rename does not fail on
+// an input URL the policy no longer allows.
+func (p *Policy) Check(prev, next *channel.ZmuxChannel) error {
+	if url := next.Input.URL; url != nil && (prev == nil || prev.Input.URL == nil || *prev.Input.URL != *url) {
+		if err := p.CheckInputURL(*url); err != nil {
+			return err
+		}
+	}
+
+	var prevOutputs map[string]channel.OutputEntry
+	if prev != nil {
+		prevOutputs = prev.OutputsByRef()
+	}
+	for _, o := range next.Outputs {
+		if !o.Enabled {
+			continue
+		}
+		if e, ok := prevOutputs[o.Ref]; ok && e.Output.Enabled {
+			continue
+		}
+		if len(p.OutputRefs) > 0 && !slices.Contains(p.OutputRefs, o.Ref) {
+			return fmt.Errorf("%w: output %q is not allowed (allowed: %s)", ErrPolicyViolation, o.Ref, strings.Join(p.OutputRefs, ", "))
+		}
+	}
+	return nil
+}
+
+// CheckInputURL validates an input URL's scheme and host.
+func (p *Policy) CheckInputURL(raw string) error {
+	if len(p.InputSchemes) == 0 && len(p.AllowedHosts) == 0 && len(p.DeniedHosts) == 0 {
+		return nil
+	}
+
+	u, err := avurl.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%w: input.url: %v", ErrPolicyViolation, err)
+	}
+
+	if scheme := strings.ToLower(u.Schema); len(p.InputSchemes) > 0 && !slices.Contains(p.InputSchemes, scheme) {
+		return fmt.Errorf("%w: input.url scheme %q is not allowed (allowed: %s)", ErrPolicyViolation, scheme, strings.Join(p.InputSchemes, ", "))
+	}
+
+	if len(p.AllowedHosts) == 0 && len(p.DeniedHosts) == 0 {
+		return nil
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%w: input.url must name a host", ErrPolicyViolation)
+	}
+
+	host := strings.ToLower(u.Host)
+	addr, err := netip.ParseAddr(host)
+	isIP := err == nil
+	addr = addr.Unmap()
+
+	deniedCIDR := false
+	for _, s := range p.DeniedHosts {
+		r, err := parseHostRule(s)
+		if err != nil {
+			continue // rejected by Validate
+		}
+		if r.match(host, addr, isIP) {
+			return fmt.Errorf("%w: input.url host %q is denied", ErrPolicyViolation, u.Host)
+		}
+		deniedCIDR = deniedCIDR || r.pattern == ""
+	}
+
+	allowed := false
+	for _, s := range p.AllowedHosts {
+		if r, err := parseHostRule(s); err == nil && r.match(host, addr, isIP) {
+			allowed = true
+			break
+		}
+	}
+	switch {
+	case len(p.AllowedHosts) > 0 && !allowed:
+		return fmt.Errorf("%w: input.url host %q is not allowed", ErrPolicyViolation, u.Host)
+	case !isIP && deniedCIDR && !allowed:
+		return fmt.Errorf("%w: input.url hostname %q must match allowed_hosts (denied_hosts has CIDRs; use an IP address)", ErrPolicyViolation, u.Host)
+	}
+	return nil
+}
+
+// hostRule is a parsed allowed/denied host: a prefix, or a hostname pattern.
+type hostRule struct {
+	prefix  netip.Prefix
+	pattern string
+}
+
+func parseHostRule(s string) (hostRule, error) {
+	if strings.Contains(s, "/") {
+		pfx, err := netip.ParsePrefix(s)
+		if err != nil {
+			return hostRule{}, fmt.Errorf("invalid CIDR %q", s)
+		}
+		if addr := pfx.Addr(); addr.Is4In6() && pfx.Bits() >= 96 {
+			pfx = netip.PrefixFrom(addr.Unmap(), pfx.Bits()-96) // ::ffff:10.0.0.0/104 → 10.0.0.0/8
+		}
+		return hostRule{prefix: pfx.Masked()}, nil
+	}
+	if addr, err := netip.ParseAddr(s); err == nil {
+		addr = addr.Unmap()
+		return hostRule{prefix: netip.PrefixFrom(addr, addr.BitLen())}, nil
+	}
+	pattern := strings.ToLower(s)
+	if _, err := path.Match(pattern, ""); pattern == "" || err != nil {
+		return hostRule{}, fmt.Errorf("invalid host pattern %q", s)
+	}
+	return hostRule{pattern: pattern}, nil
+}
+
+// match reports whether host (lowercase; addr when it is an IP literal)
+// matches the rule.
+func (r hostRule) match(host string, addr netip.Addr, isIP bool) bool {
+	if r.pattern != "" {
+		ok, _ := path.Match(r.pattern, host)
+		return !isIP && ok
+	}
+	return isIP && r.prefix.Contains(addr)
+}
@@ -0,0 +1,180 @@
+package b2bclient
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/edirooss/zmux-server/internal/domain/channel"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr bool
+	}{
+		{"empty", Policy{}, false},
+		{"valid", Policy{
+			InputSchemes: []string{"srt", "rtsp"},
+			AllowedHosts: []string{"10.0.0.0/8", "192.0.2.1", "::1", "*.example.com"},
+			DeniedHosts:  []string{"10.1.0.0/16"},
+			OutputRefs:   []string{"onprem_mr01"},
+		}, false},
+		{"uppercase scheme", Policy{InputSchemes: []string{"SRT"}}, true},
+		{"scheme with colon", Policy{InputSchemes: []string{"srt:"}}, true},
+		{"bad CIDR", Policy{AllowedHosts: []string{"10.0.0.0/33"}}, true},
+		{"bad denied CIDR", Policy{DeniedHosts: []string{"example.com/8"}}, true},
+		{"bad pattern", Policy{AllowedHosts: []string{"[a-"}}, true},
+		{"empty host rule", Policy{DeniedHosts: []string{""}}, true},
+		{"empty output ref", Policy{OutputRefs: []string{""}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPolicyCheckInputURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		url    string
+		ok     bool
+	}{
+		// no restrictions
+		{"empty policy", Policy{}, "srt://anything:9000", true},
+
+		// schemes
+		{"scheme allowed", Policy{InputSchemes: []string{"srt"}}, "srt://192.0.2.1:9000", true},
+		{"scheme case-insensitive", Policy{InputSchemes: []string{"srt"}}, "SRT://192.0.2.1:9000", true},
+		{"scheme denied", Policy{InputSchemes: []string{"srt"}}, "rtsp://192.0.2.1/live", false},
+
+		// CIDR vs hostname
+		{"IP in allowed CIDR", Policy{AllowedHosts: []string{"10.0.0.0/8"}}, "srt://10.1.2.3:9000", true},
+		{"IP outside allowed CIDR", Policy{AllowedHosts: []string{"10.0.0.0/8"}}, "srt://192.0.2.1:9000", false},
+		{"hostname never matches a CIDR", Policy{AllowedHosts: []string{"10.0.0.0/8"}}, "srt://cam.example.com:9000", false},
+		{"single IP rule", Policy{AllowedHosts: []string{"192.0.2.1"}}, "srt://192.0.2.1:9000", true},
+		{"single IP rule, other IP", Policy{AllowedHosts: []string{"192.0.2.1"}}, "srt://192.0.2.2:9000", false},
+		{"hostname pattern", Policy{AllowedHosts: []string{"*.example.com"}}, "srt://cam.example.com:9000", true},
+		{"hostname pattern case-insensitive", Policy{AllowedHosts: []string{"*.Example.COM"}}, "srt://CAM.example.com:9000", true},
+		{"hostname pattern, other domain", Policy{AllowedHosts: []string{"*.example.com"}}, "srt://cam.example.org:9000", false},
+		{"IP never matches a pattern", Policy{AllowedHosts: []string{"*"}}, "srt://192.0.2.1:9000", false},
+		{"IPv6 in CIDR", Policy{AllowedHosts: []string{"2001:db8::/32"}}, "srt://[2001:db8::1]:9000", true},
+		{"no host", Policy{AllowedHosts: []string{"*"}}, "srt://:9000", false},
+
+		// IPv4-mapped IPv6 (mapped hosts are rejected by the URL parser, so a
+		// denied IPv4 range cannot be bypassed through them)
+		{"mapped IPv6 host", Policy{DeniedHosts: []string{"10.0.0.0/8"}}, "srt://[::ffff:10.0.0.1]:9000", false},
+		{"mapped IPv6 rule matches IPv4", Policy{DeniedHosts: []string{"::ffff:192.0.2.1"}}, "srt://192.0.2.1:9000", false},
+		{"mapped IPv6 CIDR matches IPv4", Policy{DeniedHosts: []string{"::ffff:10.0.0.0/104"}}, "srt://10.1.2.3:9000", false},
+		{"mapped IPv6 CIDR, IPv4 outside", Policy{AllowedHosts: []string{"::ffff:10.0.0.0/104"}}, "srt://192.0.2.1:9000", false},
+
+		// denied first
+		{"denied wins over allowed", Policy{AllowedHosts: []string{"10.0.0.0/8"}, DeniedHosts: []string{"10.1.0.0/16"}}, "srt://10.1.2.3:9000", false},
+		{"denied pattern", Policy{DeniedHosts: []string{"*.internal"}}, "srt://db.internal:9000", false},
+		{"not denied", Policy{DeniedHosts: []string{"*.internal"}}, "srt://cam.example.com:9000", true},
+
+		// a denied CIDR forces hostnames onto the allowlist
+		{"denied CIDR, IP outside", Policy{DeniedHosts: []string{"10.0.0.0/8"}}, "srt://192.0.2.1:9000", true},
+		{"denied CIDR, unlisted hostname", Policy{DeniedHosts: []string{"10.0.0.0/8"}}, "srt://cam.example.com:9000", false},
+		{"denied CIDR, allowed hostname", Policy{AllowedHosts: []string{"*.example.com"}, DeniedHosts: []string{"10.0.0.0/8"}}, "srt://cam.example.com:9000", true},
+		{"denied IP, unlisted hostname", Policy{DeniedHosts: []string{"10.0.0.1"}}, "srt://cam.example.com:9000", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); err != nil {
+				t.Fatalf("Validate() = %v", err)
+			}
+			err := tt.policy.CheckInputURL(tt.url)
+			if tt.ok && err != nil {
+				t.Fatalf("CheckInputURL(%q) = %v, want nil", tt.url, err)
+			}
+			if !tt.ok && !errors.Is(err, ErrPolicyViolation) {
+				t.Fatalf("CheckInputURL(%q) = %v, want ErrPolicyViolation", tt.url, err)
+			}
+		})
+	}
+}
+
+func TestHostRuleMatch(t *testing.T) {
+	tests := []struct {
+		rule, host string
+		want       bool
+	}{
+		{"10.0.0.0/8", "10.0.0.1", true},
+		{"10.0.0.0/8", "::ffff:10.0.0.1", true}, // unmapped like CheckInputURL does
+		{"::ffff:10.0.0.0/104", "10.0.0.1", true},
+		{"::ffff:10.0.0.0/104", "11.0.0.1", false},
+		{"::ffff:10.0.0.1", "10.0.0.1", true},
+		{"10.0.0.1", "::ffff:10.0.0.1", true},
+		{"::/0", "10.0.0.1", false}, // IPv6 ranges do not cover IPv4 hosts
+		{"2001:db8::/32", "2001:db8::1", true},
+		{"10.0.0.0/8", "ten.example.com", false},
+		{"*.example.com", "cam.example.com", true},
+		{"*.example.com", "example.com", false},
+		{"*", "10.0.0.1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule+" "+tt.host, func(t *testing.T) {
+			r, err := parseHostRule(tt.rule)
+			if err != nil {
+				t.Fatalf("parseHostRule(%q) = %v", tt.rule, err)
+			}
+			addr, err := netip.ParseAddr(tt.host)
+			if got := r.match(tt.host, addr.Unmap(), err == nil); got != tt.want {
+				t.Fatalf("match(%q) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	str := func(s string) *string { return &s }
+	ch := func(url string, outputs ...channel.ZmuxChannelOutput) *channel.ZmuxChannel {
+		return &channel.ZmuxChannel{Input: channel.ZmuxChannelInput{URL: str(url)}, Outputs: outputs}
+	}
+	out := func(ref string, enabled bool) channel.ZmuxChannelOutput {
+		return channel.ZmuxChannelOutput{Ref: ref, Enabled: enabled}
+	}
+
+	policy := Policy{
+		InputSchemes: []string{"srt"},
+		OutputRefs:   []string{"onprem_mr01"},
+	}
+
+	tests := []struct {
+		name       string
+		prev, next *channel.ZmuxChannel
+		ok         bool
+	}{
+		{"create, allowed", nil, ch("srt://192.0.2.1:9000", out("onprem_mr01", true)), true},
+		{"create, scheme denied", nil, ch("rtsp://192.0.2.1/live"), false},
+		{"create, output denied", nil, ch("srt://192.0.2.1:9000", out("pubcloud_sky320", true)), false},
+		{"create, disabled output not checked", nil, ch("srt://192.0.2.1:9000", out("pubcloud_sky320", false)), true},
+
+		// settings predating the policy are kept
+		{"unchanged denied input", ch("rtsp://192.0.2.1/live"), ch("rtsp://192.0.2.1/live"), true},
+		{"changed input", ch("srt://192.0.2.1:9000"), ch("rtsp://192.0.2.1/live"), false},
+		{"output stays enabled", ch("srt://192.0.2.1:9000", out("pubcloud_sky320", true)), ch("srt://192.0.2.1:9000", out("pubcloud_sky320", true)), true},
+		{"output gets disabled", ch("srt://192.0.2.1:9000", out("pubcloud_sky320", true)), ch("srt://192.0.2.1:9000", out("pubcloud_sky320", false)), true},
+		{"output gets enabled", ch("srt://192.0.2.1:9000", out("pubcloud_sky320", false)), ch("srt://192.0.2.1:9000", out("pubcloud_sky320", true)), false},
+		{"output added enabled", ch("srt://192.0.2.1:9000"), ch("srt://192.0.2.1:9000", out("pubcloud_sky320", true)), false},
+		{"allowed output gets enabled", ch("srt://192.0.2.1:9000", out("onprem_mr01", false)), ch("srt://192.0.2.1:9000", out("onprem_mr01", true)), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.prev, tt.next)
+			if tt.ok && err != nil {
+				t.Fatalf("Check() = %v, want nil", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrPolicyViolation) {
+				t.Fatalf("Check() = %v, want ErrPolicyViolation", err)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ package b2bclient
 // ---------------------
 
 type Quotas struct {
+	Channels        Channels        `json:"channels"`
 	EnabledChannels EnabledChannels `json:"enabled_channels"`
 	EnabledOutputs  []EnabledOutput `json:"enabled_outputs"`
 	OnlineChannels  OnlineChannels  `json:"online_channels"`
@@ -22,6 +23,9 @@ func NewQuotas(model *QuotasModel) Quotas {
 	}
 
 	return Quotas{
+		Channels: Channels{
+			Quota: cloneInt64(model.Channels.Quota),
+		},
 		EnabledChannels: EnabledChannels{
 			Quota: model.EnabledChannels.Quota,
 		},
@@ -33,14 +37,37 @@ func NewQuotas(model *QuotasModel) Quotas {
 	}
 }
 
+// Domain → Model (pure, copies slices)
+func (q Quotas) Model() QuotasModel {
+	outs := make([]EnabledOutputModel, len(q.EnabledOutputs))
+	for i, o := range q.EnabledOutputs {
+		outs[i] = EnabledOutputModel(o)
+	}
+
+	return QuotasModel{
+		Channels: ChannelsModel{
+			Quota: cloneInt64(q.Channels.Quota),
+		},
+		EnabledChannels: EnabledChannelsModel{
+			Quota: q.EnabledChannels.Quota,
+		},
+		EnabledOutputs: outs,
+		OnlineChannels: OnlineChannelsModel{
+			Quota:        q.OnlineChannels.Quota,
+			MaxPreflight: q.OnlineChannels.MaxPreflight,
+		},
+	}
+}
+
 // Domain → View (pure projection, never exposes domain memory)
-func (q Quotas) View(enabledChannelsUsage int64, enabledOutputsUsage map[string]int64, onlineChannelsUsage int64) QuotasView {
+func (q Quotas) View(channelsUsage int64, enabledChannelsUsage int64, enabledOutputsUsage map[string]int64, onlineChannelsUsage int64) QuotasView {
 	outViews := make([]EnabledOutputView, len(q.EnabledOutputs))
 	for i, eo := range q.EnabledOutputs {
 		outViews[i] = eo.View(enabledOutputsUsage[eo.Ref]) // always a COPY
 	}
 
 	return QuotasView{
+		Channels:        q.Channels.View(channelsUsage),
 		EnabledChannels: q.EnabledChannels.View(enabledChannelsUsage),
 		EnabledOutputs:  outViews,
 		OnlineChannels:  q.OnlineChannels.View(onlineChannelsUsage),
@@ -51,6 +78,17 @@ func (q Quotas) View(enabledChannelsUsage int64, enabledOutputsUsage map[string]
 // DOMAIN SUBTYPES
 // ------------------------
 
+type Channels struct {
+	Quota *int64 `json:"quota"` // nullable (null: unlimited)
+}
+
+func (c Channels) View(usage int64) ChannelsView {
+	return ChannelsView{
+		Quota: cloneInt64(c.Quota),
+		Usage: usage,
+	}
+}
+
 type EnabledChannels struct {
 	Quota int64 `json:"quota"`
 }
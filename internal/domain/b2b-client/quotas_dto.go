@@ -5,11 +5,16 @@ package b2bclient
 // ----- Resources (input DTOs/API Request) -----
 
 type QuotasResource struct {
+	Channels        ChannelsResource        `json:"channels"`
 	EnabledChannels EnabledChannelsResource `json:"enabled_channels"`
 	EnabledOutputs  []EnabledOutputResource `json:"enabled_outputs"`
 	OnlineChannels  OnlineChannelsResource  `json:"online_channels"`
 }
 
+type ChannelsResource struct {
+	Quota *int64 `json:"quota"` // nullable (null: unlimited)
+}
+
 type EnabledChannelsResource struct {
 	Quota int64 `json:"quota"`
 }
@@ -27,11 +32,17 @@ type OnlineChannelsResource struct {
 // ----- Views (output DTOs/API Response) -----
 
 type QuotasView struct {
+	Channels        ChannelsView        `json:"channels"`
 	EnabledChannels EnabledChannelsView `json:"enabled_channels"`
 	EnabledOutputs  []EnabledOutputView `json:"enabled_outputs"`
 	OnlineChannels  OnlineChannelsView  `json:"online_channels"`
 }
 
+type ChannelsView struct {
+	Quota *int64 `json:"quota"`
+	Usage int64  `json:"usage"`
+}
+
 type EnabledChannelsView struct {
 	Quota int64 `json:"quota"`
 	Usage int64 `json:"usage"`
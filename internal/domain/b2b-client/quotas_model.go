@@ -3,6 +3,7 @@ package b2bclient
 // ----- model layer quotas -----
 
 type QuotasModel struct {
+	Channels        ChannelsModel        `json:"channels"`
 	EnabledChannels EnabledChannelsModel `json:"enabled_channels"`
 	EnabledOutputs  []EnabledOutputModel `json:"enabled_outputs"`
 	OnlineChannels  OnlineChannelsModel  `json:"online_channels"`
@@ -20,6 +21,9 @@ func NewQuotasModel(r *QuotasResource) QuotasModel {
 	}
 
 	return QuotasModel{
+		Channels: ChannelsModel{
+			Quota: cloneInt64(r.Channels.Quota),
+		},
 		EnabledChannels: EnabledChannelsModel{
 			Quota: r.EnabledChannels.Quota,
 		},
@@ -31,6 +35,10 @@ func NewQuotasModel(r *QuotasResource) QuotasModel {
 	}
 }
 
+type ChannelsModel struct {
+	Quota *int64 `json:"quota"` // nullable (null: unlimited; records predating the quota)
+}
+
 type EnabledChannelsModel struct {
 	Quota int64 `json:"quota"`
 }
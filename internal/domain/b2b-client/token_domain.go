@@ -0,0 +1,78 @@
+package b2bclient
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/edirooss/zmux-server/internal/domain/principal"
+)
+
+// Domain (Application Layer; Core runtime object)
+//
+// Token is a B2B client API (bearer) token. Only the SHA-256 hash of the
+// secret is kept; the secret itself is shown once, on creation.
+type Token struct {
+	ID        string
+	Name      string
+	Hash      string // hex SHA-256 of the secret
+	Scope     principal.Scope
+	CreatedAt int64 // UTC millis
+	ExpiresAt int64 // UTC millis; 0 = never
+}
+
+// HashToken returns the hex SHA-256 of a token secret.
+func HashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// Expired reports whether the token is no longer valid at now.
+func (t Token) Expired(now time.Time) bool {
+	return t.ExpiresAt != 0 && now.UnixMilli() >= t.ExpiresAt
+}
+
+// Model → Domain
+func NewToken(model *TokenModel) Token {
+	scope := principal.Scope(model.Scope)
+	if scope == "" {
+		scope = principal.ScopeReadWrite
+	}
+
+	return Token{
+		ID:        model.ID,
+		Name:      model.Name,
+		Hash:      model.Hash,
+		Scope:     scope,
+		CreatedAt: model.CreatedAt,
+		ExpiresAt: model.ExpiresAt,
+	}
+}
+
+// Domain → Model
+func (t Token) Model() TokenModel {
+	return TokenModel{
+		ID:        t.ID,
+		Name:      t.Name,
+		Hash:      t.Hash,
+		Scope:     string(t.Scope),
+		CreatedAt: t.CreatedAt,
+		ExpiresAt: t.ExpiresAt,
+	}
+}
+
+// Domain → View (never exposes the hash)
+func (t Token) View(now time.Time) TokenView {
+	v := TokenView{
+		ID:        t.ID,
+		Name:      t.Name,
+		Scope:     string(t.Scope),
+		CreatedAt: t.CreatedAt,
+		Expired:   t.Expired(now),
+	}
+	if t.ExpiresAt != 0 {
+		expiresAt := t.ExpiresAt
+		v.ExpiresAt = &expiresAt
+	}
+	return v
+}
@@ -0,0 +1,29 @@
+package b2bclient
+
+// DTO (API Layer; Request schema) — POST .../tokens
+type TokenResource struct {
+	Name      string `json:"name"`
+	Scope     string `json:"scope"`      // read | read_write (default read_write)
+	ExpiresAt *int64 `json:"expires_at"` // UTC millis; null = never
+}
+
+// DTO (API Layer; Request schema) — POST .../tokens/:token_id/rotate
+type TokenRotateResource struct {
+	OverlapSec *int64 `json:"overlap_sec"` // how long the old token stays valid (default 86400)
+}
+
+// DTO (API Layer; Response schema)
+type TokenView struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Scope     string `json:"scope"`
+	CreatedAt int64  `json:"created_at"`
+	ExpiresAt *int64 `json:"expires_at"`
+	Expired   bool   `json:"expired"`
+}
+
+// DTO (API Layer; Response schema) — the only response carrying the secret.
+type TokenCreatedView struct {
+	TokenView
+	Token string `json:"token"`
+}
@@ -0,0 +1,11 @@
+package b2bclient
+
+// DB (Persistence Layer; part of the B2B client record)
+type TokenModel struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Hash      string `json:"hash"`                 // hex SHA-256 of the secret
+	Scope     string `json:"scope"`                // read | read_write
+	CreatedAt int64  `json:"created_at"`           // UTC millis
+	ExpiresAt int64  `json:"expires_at,omitempty"` // UTC millis; 0 = never
+}
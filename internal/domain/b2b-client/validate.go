@@ -0,0 +1,88 @@
+package b2bclient
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/edirooss/zmux-server/internal/domain/principal"
+)
+
+// Validate checks a B2B client create/update request.
+func (r *B2BClientResource) Validate() error {
+	if q := r.Quotas.Channels.Quota; q != nil && *q < 0 {
+		return errors.New("quotas.channels.quota must be null or at least 0")
+	}
+	if err := r.Policy.Validate(); err != nil {
+		return fmt.Errorf("policy: %w", err)
+	}
+	if r.ChannelTemplate != nil {
+		// The template must yield a valid channel on its own (the client may
+		// only add name, input url/credentials and toggle enabled flags),
+		// within the policy.
+		ch := r.ChannelTemplate.NewChannel(0)
+		if err := ch.Validate(); err != nil {
+			return fmt.Errorf("channel_template: %w", err)
+		}
+		if err := r.Policy.Check(nil, ch); err != nil {
+			return fmt.Errorf("channel_template: %w", err)
+		}
+	}
+	return nil
+}
+
+// ValidateOutputRefs checks that every output ref the request names (quota
+// enabled_outputs, policy output_refs, channel template outputs) is in the
+// output destination catalog.
+func (r *B2BClientResource) ValidateOutputRefs(exists func(ref string) bool) error {
+	for i, o := range r.Quotas.EnabledOutputs {
+		if !exists(o.Ref) {
+			return fmt.Errorf("quotas.enabled_outputs[%d]: unknown output destination %q", i, o.Ref)
+		}
+	}
+	for i, ref := range r.Policy.OutputRefs {
+		if !exists(ref) {
+			return fmt.Errorf("policy.output_refs[%d]: unknown output destination %q", i, ref)
+		}
+	}
+	if r.ChannelTemplate != nil {
+		for i, o := range r.ChannelTemplate.Outputs {
+			if !exists(o.Ref) {
+				return fmt.Errorf("channel_template.outputs[%d]: unknown output destination %q", i, o.Ref)
+			}
+		}
+	}
+	return nil
+}
+
+// MaxTokenOverlap caps the rotation overlap window.
+const MaxTokenOverlap = 30 * 24 * time.Hour
+
+// Validate checks a token creation request and applies defaults (scope).
+func (r *TokenResource) Validate(now time.Time) error {
+	if len(r.Name) < 1 || len(r.Name) > 64 {
+		return errors.New("name must be between 1 and 64 characters")
+	}
+	if r.Scope == "" {
+		r.Scope = string(principal.ScopeReadWrite)
+	}
+	if _, err := principal.ParseScope(r.Scope); err != nil {
+		return err
+	}
+	if r.ExpiresAt != nil && *r.ExpiresAt <= now.UnixMilli() {
+		return errors.New("expires_at must be in the future")
+	}
+	return nil
+}
+
+// Overlap validates and returns the rotation overlap window (default 24h).
+func (r *TokenRotateResource) Overlap() (time.Duration, error) {
+	if r.OverlapSec == nil {
+		return 24 * time.Hour, nil
+	}
+	d := time.Duration(*r.OverlapSec) * time.Second
+	if d < 0 || d > MaxTokenOverlap {
+		return 0, errors.New("overlap_sec must be between 0 and 2592000 (30 days)")
+	}
+	return d, nil
+}
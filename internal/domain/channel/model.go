@@ -15,7 +15,32 @@ type ZmuxChannel struct {
 	Input       ZmuxChannelInput    `json:"input"`         //
 	Outputs     []ZmuxChannelOutput `json:"outputs"`       //
 	Enabled     bool                `json:"enabled"`       // (on true, input.url required)
-	RestartSec  uint                `json:"restart_sec"`   //
+	RestartSec  uint                `json:"restart_sec"`   // first restart delay
+
+	// Restart policy (see processmgr.RestartPolicy)
+	RestartMaxDelaySec    uint `json:"restart_max_delay_sec"`    // exponential backoff cap (<= restart_sec: fixed delay)
+	RestartResetSec       uint `json:"restart_reset_sec"`        // uptime that clears the failure history (0: never)
+	StartLimitBurst       uint `json:"start_limit_burst"`        // failures within start_limit_interval_sec that mark the channel failed (0: never)
+	StartLimitIntervalSec uint `json:"start_limit_interval_sec"` //
+}
+
+// Restart policy defaults.
+const (
+	DefaultRestartSec            = 3
+	DefaultRestartMaxDelaySec    = 60
+	DefaultRestartResetSec       = 60
+	DefaultStartLimitBurst       = 10
+	DefaultStartLimitIntervalSec = 600
+)
+
+// SetRestartPolicyDefaults fills the restart policy fields (not restart_sec)
+// with their defaults. Applied before decoding persisted channels, so that
+// documents written before the policy fields existed get the defaults.
+func (ch *ZmuxChannel) SetRestartPolicyDefaults() {
+	ch.RestartMaxDelaySec = DefaultRestartMaxDelaySec
+	ch.RestartResetSec = DefaultRestartResetSec
+	ch.StartLimitBurst = DefaultStartLimitBurst
+	ch.StartLimitIntervalSec = DefaultStartLimitIntervalSec
 }
 
 type ZmuxChannelInput struct {
@@ -171,6 +196,11 @@ func (ch *ZmuxChannel) Validate() error {
 		}
 	}
 
+	// start_limit_burst requires start_limit_interval_sec
+	if ch.StartLimitBurst > 0 && ch.StartLimitIntervalSec == 0 {
+		return errors.New("start_limit_burst > 0 requires start_limit_interval_sec > 0")
+	}
+
 	// Cross-field dependency check
 	if err := ch.crossDependencyCheck(); err != nil {
 		return err
@@ -9,6 +9,11 @@ type ZmuxChannelModel struct {
 	Outputs     []ZmuxChannelOutput `json:"outputs"`
 	Enabled     bool                `json:"enabled"`
 	RestartSec  uint                `json:"restart_sec"`
+
+	RestartMaxDelaySec    uint `json:"restart_max_delay_sec"`
+	RestartResetSec       uint `json:"restart_reset_sec"`
+	StartLimitBurst       uint `json:"start_limit_burst"`
+	StartLimitIntervalSec uint `json:"start_limit_interval_sec"`
 }
 
 // Model returns a deep-copied ZmuxChannelModel from the receiver.
@@ -19,6 +24,11 @@ func (ch *ZmuxChannel) Model() ZmuxChannelModel {
 		Name:        cloneString(ch.Name),
 		Enabled:     ch.Enabled,
 		RestartSec:  ch.RestartSec,
+
+		RestartMaxDelaySec:    ch.RestartMaxDelaySec,
+		RestartResetSec:       ch.RestartResetSec,
+		StartLimitBurst:       ch.StartLimitBurst,
+		StartLimitIntervalSec: ch.StartLimitIntervalSec,
 		Input: ZmuxChannelInput{
 			URL:             cloneString(ch.Input.URL),
 			Username:        cloneString(ch.Input.Username),
@@ -2,14 +2,17 @@ package channel
 
 import "github.com/edirooss/zmux-server/internal/domain/channel/views"
 
-func (ch *ZmuxChannel) B2BClientView() *views.B2BClientZmuxChannel {
+// B2BClientView returns the B2B-client-facing view of the channel; only the
+// outputs whose ref is visible (see the output destination catalog) are
+// listed.
+func (ch *ZmuxChannel) B2BClientView(visible func(ref string) bool) *views.B2BClientZmuxChannel {
 	var outputsView map[string]views.B2BClientOutput
 	for _, output := range ch.Outputs {
 		if outputsView == nil {
 			outputsView = make(map[string]views.B2BClientOutput)
 		}
 		ref := output.Ref
-		if ref == "onprem_mr01" || ref == "onprem_mz01" || ref == "pubcloud_sky320" {
+		if visible(ref) {
 			outputsView[ref] = views.B2BClientOutput{
 				Enabled: output.Enabled,
 			}
@@ -20,9 +23,9 @@ func (ch *ZmuxChannel) B2BClientView() *views.B2BClientZmuxChannel {
 		ID:   ch.ID,
 		Name: ch.Name,
 		Input: views.B2BClientInput{
-			URL:      ch.Input.URL,
-			Username: ch.Input.Username,
-			Password: ch.Input.Password,
+			URL:         ch.Input.URL,
+			Username:    ch.Input.Username,
+			PasswordSet: ch.Input.Password != nil,
 		},
 		Outputs: outputsView,
 		Enabled: ch.Enabled,
@@ -51,7 +54,7 @@ func (ch *ZmuxChannel) AdminView() *views.AdminZmuxChannel {
 		Input: views.AdminInput{
 			URL:             ch.Input.URL,
 			Username:        ch.Input.Username,
-			Password:        ch.Input.Password,
+			PasswordSet:     ch.Input.Password != nil,
 			AVIOFlags:       ch.Input.AVIOFlags,
 			Probesize:       ch.Input.Probesize,
 			Analyzeduration: ch.Input.Analyzeduration,
@@ -64,5 +67,10 @@ func (ch *ZmuxChannel) AdminView() *views.AdminZmuxChannel {
 		Outputs:    outputsView,
 		Enabled:    ch.Enabled,
 		RestartSec: ch.RestartSec,
+
+		RestartMaxDelaySec:    ch.RestartMaxDelaySec,
+		RestartResetSec:       ch.RestartResetSec,
+		StartLimitBurst:       ch.StartLimitBurst,
+		StartLimitIntervalSec: ch.StartLimitIntervalSec,
 	}
 }
@@ -9,12 +9,17 @@ type AdminZmuxChannel struct {
 	Outputs     []AdminOutput `json:"outputs"`
 	Enabled     bool          `json:"enabled"`
 	RestartSec  uint          `json:"restart_sec"`
+
+	RestartMaxDelaySec    uint `json:"restart_max_delay_sec"`
+	RestartResetSec       uint `json:"restart_reset_sec"`
+	StartLimitBurst       uint `json:"start_limit_burst"`
+	StartLimitIntervalSec uint `json:"start_limit_interval_sec"`
 }
 
 type AdminInput struct {
 	URL             *string `json:"url"`
 	Username        *string `json:"username"`
-	Password        *string `json:"password"`
+	PasswordSet     bool    `json:"password_set"` // password is write-only
 	AVIOFlags       *string `json:"avioflags"`
 	Probesize       uint    `json:"probesize"`
 	Analyzeduration uint    `json:"analyzeduration"`
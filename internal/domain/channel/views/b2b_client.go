@@ -9,9 +9,9 @@ type B2BClientZmuxChannel struct {
 }
 
 type B2BClientInput struct {
-	URL      *string `json:"url"`
-	Username *string `json:"username"`
-	Password *string `json:"password"`
+	URL         *string `json:"url"`
+	Username    *string `json:"username"`
+	PasswordSet bool    `json:"password_set"` // password is write-only
 }
 
 type B2BClientOutput struct {
@@ -0,0 +1,110 @@
+package outputdestination
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/edirooss/zmux-server/internal/domain/channel"
+)
+
+// ErrLocaladdrNotAllowed is wrapped when a channel output uses a localaddr
+// its destination does not allow.
+var ErrLocaladdrNotAllowed = errors.New("localaddr not allowed")
+
+// Domain (Application Layer; Core runtime object)
+type OutputDestination struct {
+	ID            int64
+	Ref           string
+	Name          string
+	DefaultURL    *string
+	Localaddrs    []string
+	PktSize       uint
+	StreamMapping []string
+	B2BVisible    bool
+	B2BToggleable bool
+	CreatedAt     int64
+	UpdatedAt     int64
+}
+
+// DB (Model) + ID → Domain
+func NewOutputDestination(model *OutputDestinationModel, id int64) *OutputDestination {
+	if model == nil {
+		return nil
+	}
+
+	return &OutputDestination{
+		ID:            id,
+		Ref:           model.Ref,
+		Name:          model.Name,
+		DefaultURL:    cloneString(model.DefaultURL),
+		Localaddrs:    slices.Clone(model.Localaddrs),
+		PktSize:       model.PktSize,
+		StreamMapping: slices.Clone(model.StreamMapping),
+		B2BVisible:    model.B2BVisible,
+		B2BToggleable: model.B2BToggleable,
+		CreatedAt:     model.CreatedAt,
+		UpdatedAt:     model.UpdatedAt,
+	}
+}
+
+// Domain → DB (Model)
+func (d *OutputDestination) Model() *OutputDestinationModel {
+	if d == nil {
+		return nil
+	}
+
+	return &OutputDestinationModel{
+		Ref:           d.Ref,
+		Name:          d.Name,
+		DefaultURL:    cloneString(d.DefaultURL),
+		Localaddrs:    slices.Clone(d.Localaddrs),
+		PktSize:       d.PktSize,
+		StreamMapping: slices.Clone(d.StreamMapping),
+		B2BVisible:    d.B2BVisible,
+		B2BToggleable: d.B2BToggleable,
+		CreatedAt:     d.CreatedAt,
+		UpdatedAt:     d.UpdatedAt,
+	}
+}
+
+// Domain → API Response (View)
+func (d *OutputDestination) View() *OutputDestinationView {
+	if d == nil {
+		return nil
+	}
+
+	return &OutputDestinationView{
+		ID:            d.ID,
+		Ref:           d.Ref,
+		Name:          d.Name,
+		DefaultURL:    cloneString(d.DefaultURL),
+		Localaddrs:    slices.Clone(d.Localaddrs),
+		PktSize:       d.PktSize,
+		StreamMapping: slices.Clone(d.StreamMapping),
+		B2BVisible:    d.B2BVisible,
+		B2BToggleable: d.B2BToggleable,
+		CreatedAt:     d.CreatedAt,
+		UpdatedAt:     d.UpdatedAt,
+	}
+}
+
+// DefaultOutput returns the settings of a new channel output to this
+// destination (enabled; no localaddr).
+func (d *OutputDestination) DefaultOutput() channel.ZmuxChannelOutput {
+	return channel.ZmuxChannelOutput{
+		Ref:           d.Ref,
+		URL:           cloneString(d.DefaultURL),
+		PktSize:       d.PktSize,
+		StreamMapping: slices.Clone(d.StreamMapping),
+		Enabled:       true,
+	}
+}
+
+// CheckOutput validates a channel output to this destination.
+func (d *OutputDestination) CheckOutput(o channel.ZmuxChannelOutput) error {
+	if o.Localaddr != nil && len(d.Localaddrs) > 0 && !slices.Contains(d.Localaddrs, *o.Localaddr) {
+		return fmt.Errorf("output %q: %w: %q (allowed: %v)", d.Ref, ErrLocaladdrNotAllowed, *o.Localaddr, d.Localaddrs)
+	}
+	return nil
+}
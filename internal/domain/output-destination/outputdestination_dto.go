@@ -0,0 +1,46 @@
+package outputdestination
+
+import "encoding/json"
+
+// DTO (API Layer; Request schema)
+//   - ref is immutable after create (channel outputs and quotas refer to it).
+//   - Omitted pkt_size/stream_mapping get the POST /api/channels output
+//     defaults.
+type OutputDestinationResource struct {
+	Ref           string   `json:"ref"`
+	Name          string   `json:"name"`
+	DefaultURL    *string  `json:"default_url"`    // nullable; url of new channel outputs that omit it
+	Localaddrs    []string `json:"localaddrs"`     // allowed output localaddr values (empty: any)
+	PktSize       uint     `json:"pkt_size"`       //
+	StreamMapping []string `json:"stream_mapping"` //
+	B2BVisible    bool     `json:"b2b_visible"`    // listed in B2B client channel views
+	B2BToggleable bool     `json:"b2b_toggleable"` // B2B clients may enable/disable it (requires b2b_visible)
+}
+
+func (r *OutputDestinationResource) UnmarshalJSON(b []byte) error {
+	type plain OutputDestinationResource
+	p := plain{
+		PktSize:       1316,
+		StreamMapping: []string{"video"},
+	}
+	if err := json.Unmarshal(b, &p); err != nil {
+		return err
+	}
+	*r = OutputDestinationResource(p)
+	return nil
+}
+
+// DTO (API Layer; Response schema)
+type OutputDestinationView struct {
+	ID            int64    `json:"id"`
+	Ref           string   `json:"ref"`
+	Name          string   `json:"name"`
+	DefaultURL    *string  `json:"default_url"`
+	Localaddrs    []string `json:"localaddrs"`
+	PktSize       uint     `json:"pkt_size"`
+	StreamMapping []string `json:"stream_mapping"`
+	B2BVisible    bool     `json:"b2b_visible"`
+	B2BToggleable bool     `json:"b2b_toggleable"`
+	CreatedAt     int64    `json:"created_at"`
+	UpdatedAt     int64    `json:"updated_at"`
+}
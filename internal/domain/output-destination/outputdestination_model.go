@@ -0,0 +1,45 @@
+package outputdestination
+
+import "slices"
+
+// DB (Persistence Layer; Redis record)
+type OutputDestinationModel struct {
+	Ref           string   `json:"ref"`
+	Name          string   `json:"name"`
+	DefaultURL    *string  `json:"default_url"`
+	Localaddrs    []string `json:"localaddrs"`
+	PktSize       uint     `json:"pkt_size"`
+	StreamMapping []string `json:"stream_mapping"`
+	B2BVisible    bool     `json:"b2b_visible"`
+	B2BToggleable bool     `json:"b2b_toggleable"`
+	CreatedAt     int64    `json:"created_at"` // UTC millis
+	UpdatedAt     int64    `json:"updated_at"` // UTC millis
+}
+
+// API Request (Resource) + timestamps → DB (Model)
+func NewOutputDestinationModel(r *OutputDestinationResource, createdAt, updatedAt int64) *OutputDestinationModel {
+	if r == nil {
+		return nil
+	}
+
+	return &OutputDestinationModel{
+		Ref:           r.Ref,
+		Name:          r.Name,
+		DefaultURL:    cloneString(r.DefaultURL),
+		Localaddrs:    slices.Clone(r.Localaddrs),
+		PktSize:       r.PktSize,
+		StreamMapping: slices.Clone(r.StreamMapping),
+		B2BVisible:    r.B2BVisible,
+		B2BToggleable: r.B2BToggleable,
+		CreatedAt:     createdAt,
+		UpdatedAt:     updatedAt,
+	}
+}
+
+func cloneString(p *string) *string {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
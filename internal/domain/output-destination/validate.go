@@ -0,0 +1,40 @@
+package outputdestination
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+
+	"github.com/edirooss/zmux-server/internal/domain/channel"
+)
+
+// Validate checks the request.
+func (r *OutputDestinationResource) Validate() error {
+	if len(r.Ref) < 1 || len(r.Ref) > 100 {
+		return errors.New("ref must be between 1 and 100 characters")
+	}
+	if len(r.Name) < 1 || len(r.Name) > 100 {
+		return errors.New("name must be between 1 and 100 characters")
+	}
+
+	// default_url and stream_mapping: as a channel output would need them
+	out := channel.ZmuxChannel{Outputs: []channel.ZmuxChannelOutput{{
+		Ref:           r.Ref,
+		URL:           r.DefaultURL,
+		StreamMapping: r.StreamMapping,
+	}}}
+	if err := out.Validate(); err != nil {
+		return err
+	}
+
+	for i, a := range r.Localaddrs {
+		if _, err := netip.ParseAddr(a); err != nil {
+			return fmt.Errorf("localaddrs[%d]: invalid IP address %q", i, a)
+		}
+	}
+
+	if r.B2BToggleable && !r.B2BVisible {
+		return errors.New("b2b_toggleable requires b2b_visible")
+	}
+	return nil
+}
@@ -46,6 +46,9 @@ func (k *PrincipalKind) UnmarshalJSON(data []byte) error {
 }
 
 type Principal struct {
-	ID   string        `json:"id"`   // user id for admins, client id for b2b clients
-	Kind PrincipalKind `json:"kind"` // string marshaled
+	ID      string        `json:"id"`                 // user id for admins, client id for b2b clients
+	Kind    PrincipalKind `json:"kind"`               // string marshaled
+	Role    Role          `json:"role,omitempty"`     // admins only
+	TokenID string        `json:"token_id,omitempty"` // b2b clients only; the authenticating API token
+	Scope   Scope         `json:"scope,omitempty"`    // b2b clients only; scope of that token
 }
@@ -0,0 +1,187 @@
+package principal
+
+import "fmt"
+
+// Role is the named role of an admin-kind principal (staff user).
+// B2B clients have no role; they get a fixed permission set (see Permissions).
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"   // read-only: channels, summary, logs, B2B clients
+	RoleOperator Role = "operator" // viewer + day-to-day channel operations (restart, basic edits)
+	RoleAdmin    Role = "admin"    // everything
+)
+
+// ParseRole validates a role name.
+func ParseRole(s string) (Role, error) {
+	switch r := Role(s); r {
+	case RoleViewer, RoleOperator, RoleAdmin:
+		return r, nil
+	default:
+		return "", fmt.Errorf("invalid role %q (allowed: viewer, operator, admin)", s)
+	}
+}
+
+// Scope limits what a B2B client API token may do (see Permissions).
+type Scope string
+
+const (
+	ScopeRead      Scope = "read"       // read-only: channels:read
+	ScopeReadWrite Scope = "read_write" // every B2B client permission
+)
+
+// ParseScope validates a token scope name.
+func ParseScope(s string) (Scope, error) {
+	switch sc := Scope(s); sc {
+	case ScopeRead, ScopeReadWrite:
+		return sc, nil
+	default:
+		return "", fmt.Errorf("invalid scope %q (allowed: read, read_write)", s)
+	}
+}
+
+// Permission is a single capability checked by route middleware and field-level patch checks.
+type Permission string
+
+const (
+	PermChannelsRead      Permission = "channels:read"       // list/get channels, channel status
+	PermChannelsMonitor   Permission = "channels:monitor"    // summary, logs
+	PermChannelsRestart   Permission = "channels:restart"    // restart the remux process; no config changes
+	PermChannelsControl   Permission = "channels:control"    // stop/start, reset-failed; no config changes
+	PermChannelsEdit      Permission = "channels:edit"       // PATCH basic fields (name, enabled, input url/credentials, output enabled)
+	PermChannelsConfigure Permission = "channels:configure"  // create/replace, bulk patch, advanced fields (ownership, tuning, output routing)
+	PermChannelsDelete    Permission = "channels:delete"     // delete channels
+	PermChannelsManageOwn Permission = "channels:manage_own" // create (from the channel template)/delete own channels (B2B clients)
+	PermB2BClientsRead    Permission = "b2b_clients:read"    // list/get B2B clients
+	PermB2BClientsWrite   Permission = "b2b_clients:write"   // create/update/delete B2B clients
+	PermAdminUsersManage  Permission = "admin_users:manage"  // CRUD admin users
+	PermSystemRead        Permission = "system:read"         // local addresses, output refs/destinations, metrics
+	PermOutputsManage     Permission = "outputs:manage"      // CRUD output destinations
+	PermWebhooksManage    Permission = "webhooks:manage"     // CRUD webhooks, delivery log, ping
+	PermAlertsRead        Permission = "alerts:read"         // list alerts and alert rules
+	PermAlertsSilence     Permission = "alerts:silence"      // silence/unsilence single alerts
+	PermAlertsManage      Permission = "alerts:manage"       // CRUD alert rules (incl. rule-level silence)
+	PermReportsRead       Permission = "reports:read"        // uptime/SLA reports (all channels and B2B clients)
+	PermUsageRead         Permission = "usage:read"          // B2B client usage metering and billing export
+	PermAuditRead         Permission = "audit:read"          // audit log of mutating API calls
+	PermTokensManage      Permission = "tokens:manage"       // create/rotate/revoke own API tokens (B2B clients)
+)
+
+// Permissions is an ordered permission list (JSON: array of strings).
+type Permissions []Permission
+
+// Has reports whether perm is included.
+func (ps Permissions) Has(perm Permission) bool {
+	for _, p := range ps {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// Permission matrix.
+//
+//	                     viewer  operator  admin  b2b_client
+//	channels:read          ✓        ✓        ✓        ✓ (own channels)
+//	channels:monitor       ✓        ✓        ✓
+//	channels:restart                ✓        ✓        ✓ (own channels)
+//	channels:control                ✓        ✓
+//	channels:edit                   ✓        ✓        ✓ (own channels)
+//	channels:configure                       ✓
+//	channels:delete                          ✓
+//	channels:manage_own                               ✓ (own channels)
+//	b2b_clients:read       ✓        ✓        ✓
+//	b2b_clients:write                        ✓
+//	admin_users:manage                       ✓
+//	system:read            ✓        ✓        ✓
+//	outputs:manage                           ✓
+//	webhooks:manage                          ✓
+//	alerts:read            ✓        ✓        ✓
+//	alerts:silence                  ✓        ✓
+//	alerts:manage                            ✓
+//	reports:read           ✓        ✓        ✓
+//	usage:read                               ✓
+//	audit:read                               ✓
+//	tokens:manage                                     ✓ (own tokens)
+//
+// B2B client tokens with the read scope hold channels:read only.
+var (
+	viewerPermissions = Permissions{
+		PermChannelsRead,
+		PermChannelsMonitor,
+		PermB2BClientsRead,
+		PermSystemRead,
+		PermAlertsRead,
+		PermReportsRead,
+	}
+
+	operatorPermissions = append(append(Permissions{}, viewerPermissions...),
+		PermChannelsRestart,
+		PermChannelsControl,
+		PermChannelsEdit,
+		PermAlertsSilence,
+	)
+
+	adminPermissions = append(append(Permissions{}, operatorPermissions...),
+		PermChannelsConfigure,
+		PermChannelsDelete,
+		PermB2BClientsWrite,
+		PermAdminUsersManage,
+		PermOutputsManage,
+		PermWebhooksManage,
+		PermAlertsManage,
+		PermUsageRead,
+		PermAuditRead,
+	)
+
+	b2bClientPermissions = Permissions{
+		PermChannelsRead,
+		PermChannelsRestart,
+		PermChannelsEdit,
+		PermChannelsManageOwn,
+		PermTokensManage,
+	}
+
+	b2bClientReadPermissions = Permissions{
+		PermChannelsRead,
+	}
+)
+
+// Permissions returns the effective permissions of the role (nil for unknown roles).
+func (r Role) Permissions() Permissions {
+	switch r {
+	case RoleViewer:
+		return viewerPermissions
+	case RoleOperator:
+		return operatorPermissions
+	case RoleAdmin:
+		return adminPermissions
+	default:
+		return nil
+	}
+}
+
+// Permissions returns the effective permissions of the principal.
+// Channel ownership scoping for B2B clients is enforced separately.
+func (p *Principal) Permissions() Permissions {
+	if p == nil {
+		return nil
+	}
+	switch p.Kind {
+	case Admin:
+		return p.Role.Permissions()
+	case B2BClient:
+		if p.Scope == ScopeRead {
+			return b2bClientReadPermissions
+		}
+		return b2bClientPermissions
+	default:
+		return nil
+	}
+}
+
+// Can reports whether the principal holds perm.
+func (p *Principal) Can(perm Permission) bool {
+	return p.Permissions().Has(perm)
+}
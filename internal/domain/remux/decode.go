@@ -0,0 +1,105 @@
+package remux
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Tolerant field lookup over a decoded JSON object.
+//
+// remux is a separate binary released on its own schedule, so decoding
+// never fails on unexpected shapes: each field is looked up under its
+// canonical name first, then under known aliases (ffprobe-style names,
+// nested "tags"/"format" objects); numbers may also arrive as strings.
+// Fields that cannot be read are left zero.
+
+type object map[string]any
+
+func asObject(v any) object {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func asList(v any) []any {
+	l, _ := v.([]any)
+	return l
+}
+
+// get resolves the first present key; a key may be a dotted path.
+func (o object) get(keys ...string) (any, bool) {
+	for _, key := range keys {
+		var cur any = map[string]any(o)
+		for _, part := range strings.Split(key, ".") {
+			m, ok := cur.(map[string]any)
+			if !ok {
+				cur = nil
+				break
+			}
+			cur = m[part]
+		}
+		if cur != nil {
+			return cur, true
+		}
+	}
+	return nil, false
+}
+
+func (o object) str(keys ...string) string {
+	v, _ := o.get(keys...)
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+func (o object) num(keys ...string) (float64, bool) {
+	v, ok := o.get(keys...)
+	if !ok {
+		return 0, false
+	}
+	return toNum(v)
+}
+
+func (o object) float(keys ...string) float64 {
+	f, _ := o.num(keys...)
+	return f
+}
+
+func (o object) int(keys ...string) int64 {
+	f, _ := o.num(keys...)
+	return int64(f)
+}
+
+// toNum reads a JSON number, numeric string, hex integer string ("0x100",
+// as used for ffprobe MPEG-TS stream ids), boolean (0/1) or rational string
+// ("30000/1001", as used for ffprobe frame rates).
+func toNum(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		if num, den, ok := strings.Cut(v, "/"); ok {
+			n, err1 := strconv.ParseFloat(num, 64)
+			d, err2 := strconv.ParseFloat(den, 64)
+			if err1 != nil || err2 != nil || d == 0 {
+				return 0, false
+			}
+			return n / d, true
+		}
+		if strings.HasPrefix(v, "0x") || strings.HasPrefix(v, "0X") {
+			n, err := strconv.ParseInt(v, 0, 64)
+			return float64(n), err == nil
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
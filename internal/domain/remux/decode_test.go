@@ -0,0 +1,73 @@
+package remux
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestToNum(t *testing.T) {
+	tests := []struct {
+		in   any
+		want float64
+		ok   bool
+	}{
+		{float64(42), 42, true},
+		{true, 1, true},
+		{false, 0, true},
+		{"5000000", 5000000, true},
+		{"29.97", 29.97, true},
+		{"25/1", 25, true},
+		{"30000/1001", 30000.0 / 1001, true},
+		{"0x100", 256, true},
+		{"0/0", 0, false},
+		{"25/x", 0, false},
+		{"N/A", 0, false},
+		{"", 0, false},
+		{"0xzz", 0, false},
+		{nil, 0, false},
+		{[]any{1.0}, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := toNum(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("toNum(%#v) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestObjectLookup(t *testing.T) {
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(`{
+		"canonical": 1,
+		"alias": "2",
+		"format": {"bit_rate": "3", "tags": {"deep": 4}},
+		"null": null,
+		"scalar": 5
+	}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+	o := object(doc)
+
+	tests := []struct {
+		name string
+		keys []string
+		want float64
+		ok   bool
+	}{
+		{"canonical first", []string{"canonical", "alias"}, 1, true},
+		{"alias when canonical missing", []string{"missing", "alias"}, 2, true},
+		{"nested alias", []string{"missing", "format.bit_rate"}, 3, true},
+		{"three levels", []string{"format.tags.deep"}, 4, true},
+		{"null is missing", []string{"null", "alias"}, 2, true},
+		{"path through a scalar", []string{"scalar.x"}, 0, false},
+		{"none present", []string{"missing", "format.missing"}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := o.num(tt.keys...)
+			if got != tt.want || ok != tt.ok {
+				t.Fatalf("num(%v) = %v, %v; want %v, %v", tt.keys, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package remux
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// IfmtSchemaVersion is the newest remux:<id>:ifmt layout this server knows.
+// Documents without a version are decoded as version 0 (legacy/ffprobe-like);
+// newer ones are decoded best-effort and flagged (UnknownSchema).
+const IfmtSchemaVersion = 1
+
+// Ifmt mirrors the JSON stored at remux:<id>:ifmt: the probed input format
+// of an online remuxer.
+type Ifmt struct {
+	SchemaVersion int       `json:"schema_version"`
+	UnknownSchema bool      `json:"unknown_schema,omitempty"` // schema_version is newer than IfmtSchemaVersion
+	Container     string    `json:"container"`                // demuxer name, e.g. "mpegts"
+	Bitrate       int64     `json:"bitrate,omitempty"`        // bits/s, container level
+	Programs      []Program `json:"programs"`
+	Streams       []Stream  `json:"streams"`
+}
+
+// Program is an MPEG-TS program (service).
+type Program struct {
+	ID       int64  `json:"id"` // program number
+	Name     string `json:"name,omitempty"`
+	Provider string `json:"provider,omitempty"`
+	Streams  []int  `json:"streams"` // indexes into Ifmt.Streams
+}
+
+// Stream type labels.
+const (
+	StreamVideo    = "video"
+	StreamAudio    = "audio"
+	StreamSubtitle = "subtitle"
+	StreamData     = "data"
+)
+
+// Stream is an elementary stream of the input.
+type Stream struct {
+	Index      int     `json:"index"`
+	PID        int64   `json:"pid,omitempty"`
+	Type       string  `json:"type"`  // video | audio | subtitle | data | "" (unknown)
+	Codec      string  `json:"codec"` // normalized codec name (see NormalizeCodec), e.g. "hevc"
+	Profile    string  `json:"profile,omitempty"`
+	Width      int     `json:"width,omitempty"`      // video
+	Height     int     `json:"height,omitempty"`     // video
+	FrameRate  float64 `json:"frame_rate,omitempty"` // video; frames/s
+	Channels   int     `json:"channels,omitempty"`   // audio
+	SampleRate int     `json:"sample_rate,omitempty"`
+	Language   string  `json:"language,omitempty"` // ISO 639 code as signalled
+	Bitrate    int64   `json:"bitrate,omitempty"`  // bits/s
+}
+
+// UnmarshalJSON decodes tolerantly (see decode.go); only a non-object
+// document is an error.
+func (f *Ifmt) UnmarshalJSON(b []byte) error {
+	var doc map[string]any
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+	o := object(doc)
+
+	*f = Ifmt{
+		SchemaVersion: int(o.int("schema_version", "version")),
+		Container:     o.str("container", "format_name", "format.format_name"),
+		Bitrate:       o.int("bitrate", "bit_rate", "format.bit_rate"),
+		Programs:      []Program{},
+		Streams:       []Stream{},
+	}
+	f.UnknownSchema = f.SchemaVersion > IfmtSchemaVersion
+	for _, v := range asList(doc["streams"]) {
+		if so := asObject(v); so != nil {
+			f.Streams = append(f.Streams, decodeStream(so, len(f.Streams)))
+		}
+	}
+	for _, v := range asList(doc["programs"]) {
+		if po := asObject(v); po != nil {
+			f.Programs = append(f.Programs, decodeProgram(po))
+		}
+	}
+	return nil
+}
+
+func decodeStream(o object, pos int) Stream {
+	s := Stream{
+		Index:      pos,
+		PID:        o.int("pid", "id"),
+		Type:       normalizeStreamType(o.str("type", "codec_type", "media_type")),
+		Codec:      NormalizeCodec(o.str("codec", "codec_name")),
+		Profile:    o.str("profile"),
+		Width:      int(o.int("width")),
+		Height:     int(o.int("height")),
+		FrameRate:  o.float("frame_rate", "avg_frame_rate", "r_frame_rate", "fps"),
+		Channels:   int(o.int("channels")),
+		SampleRate: int(o.int("sample_rate")),
+		Language:   o.str("language", "tags.language", "lang"),
+		Bitrate:    o.int("bitrate", "bit_rate"),
+	}
+	if i, ok := o.num("index"); ok {
+		s.Index = int(i)
+	}
+	return s
+}
+
+func decodeProgram(o object) Program {
+	p := Program{
+		ID:       o.int("id", "program_num", "program_id"),
+		Name:     o.str("name", "service_name", "tags.service_name"),
+		Provider: o.str("provider", "service_provider", "tags.service_provider"),
+		Streams:  []int{},
+	}
+	streams, _ := o.get("streams")
+	for _, v := range asList(streams) {
+		if n, ok := toNum(v); ok {
+			p.Streams = append(p.Streams, int(n)) // index list
+		} else if so := asObject(v); so != nil {
+			p.Streams = append(p.Streams, int(so.int("index"))) // nested stream objects (ffprobe)
+		}
+	}
+	return p
+}
+
+func normalizeStreamType(t string) string {
+	switch t = strings.ToLower(t); t {
+	case StreamVideo, StreamAudio, StreamSubtitle, StreamData:
+		return t
+	case "subtitles":
+		return StreamSubtitle
+	}
+	return ""
+}
+
+// codecAliases maps common alternative codec names to the ffmpeg names.
+var codecAliases = map[string]string{
+	"h265":  "hevc",
+	"h.265": "hevc",
+	"h264":  "h264",
+	"h.264": "h264",
+	"avc":   "h264",
+	"avc1":  "h264",
+	"mpeg2": "mpeg2video",
+	"ac-3":  "ac3",
+	"e-ac3": "eac3",
+	"ec-3":  "eac3",
+}
+
+// NormalizeCodec lower-cases a codec name and maps common aliases to the
+// ffmpeg name ("H.265" → "hevc", "AVC" → "h264").
+func NormalizeCodec(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := codecAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// HasCodec reports whether any stream uses codec (normalized, see
+// NormalizeCodec).
+func (f *Ifmt) HasCodec(codec string) bool {
+	if f == nil {
+		return false
+	}
+	codec = NormalizeCodec(codec)
+	for _, s := range f.Streams {
+		if s.Codec == codec {
+			return true
+		}
+	}
+	return false
+}
+
+// HasStreamType reports whether any stream is of type t.
+func (f *Ifmt) HasStreamType(t string) bool {
+	if f == nil {
+		return false
+	}
+	for _, s := range f.Streams {
+		if s.Type == t {
+			return true
+		}
+	}
+	return false
+}
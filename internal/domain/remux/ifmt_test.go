@@ -0,0 +1,141 @@
+package remux
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestIfmtUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want Ifmt
+	}{
+		{
+			name: "ffprobe shape",
+			doc: `{
+				"streams": [
+					{"index": 0, "id": "0x100", "codec_name": "h264", "codec_type": "video", "profile": "High",
+					 "width": 1920, "height": 1080, "r_frame_rate": "50/1", "avg_frame_rate": "30000/1001", "bit_rate": "5000000"},
+					{"index": 1, "id": "0x101", "codec_name": "aac", "codec_type": "audio",
+					 "channels": 2, "sample_rate": "48000", "tags": {"language": "eng"}},
+					{"index": 2, "id": "0x102", "codec_name": "dvb_subtitle", "codec_type": "subtitle"}
+				],
+				"programs": [
+					{"program_id": 1, "program_num": 10, "tags": {"service_name": "News", "service_provider": "ACME"},
+					 "streams": [{"index": 0}, {"index": 1}]}
+				],
+				"format": {"format_name": "mpegts", "bit_rate": "6000000"}
+			}`,
+			want: Ifmt{
+				Container: "mpegts",
+				Bitrate:   6000000,
+				Programs:  []Program{{ID: 10, Name: "News", Provider: "ACME", Streams: []int{0, 1}}},
+				Streams: []Stream{
+					{Index: 0, PID: 256, Type: StreamVideo, Codec: "h264", Profile: "High", Width: 1920, Height: 1080, FrameRate: 30000.0 / 1001, Bitrate: 5000000},
+					{Index: 1, PID: 257, Type: StreamAudio, Codec: "aac", Channels: 2, SampleRate: 48000, Language: "eng"},
+					{Index: 2, PID: 258, Type: StreamSubtitle, Codec: "dvb_subtitle"},
+				},
+			},
+		},
+		{
+			name: "native shape",
+			doc: `{
+				"schema_version": 1,
+				"container": "mpegts",
+				"bitrate": 8000000,
+				"streams": [
+					{"index": 0, "pid": 481, "type": "video", "codec": "H.265", "width": 3840, "height": 2160, "frame_rate": 50},
+					{"index": 1, "pid": 482, "type": "audio", "codec": "E-AC3", "channels": 6, "sample_rate": 48000, "language": "deu", "bitrate": 384000}
+				],
+				"programs": [{"id": 7, "name": "Sport", "provider": "ACME", "streams": [0, 1]}]
+			}`,
+			want: Ifmt{
+				SchemaVersion: 1,
+				Container:     "mpegts",
+				Bitrate:       8000000,
+				Programs:      []Program{{ID: 7, Name: "Sport", Provider: "ACME", Streams: []int{0, 1}}},
+				Streams: []Stream{
+					{Index: 0, PID: 481, Type: StreamVideo, Codec: "hevc", Width: 3840, Height: 2160, FrameRate: 50},
+					{Index: 1, PID: 482, Type: StreamAudio, Codec: "eac3", Channels: 6, SampleRate: 48000, Language: "deu", Bitrate: 384000},
+				},
+			},
+		},
+		{
+			name: "numbers as strings",
+			doc: `{
+				"version": "1",
+				"container": "mpegts",
+				"bitrate": "1000",
+				"streams": [{"pid": "100", "type": "Video", "codec": "avc", "width": "720", "height": "576", "fps": "25"}]
+			}`,
+			want: Ifmt{
+				SchemaVersion: 1,
+				Container:     "mpegts",
+				Bitrate:       1000,
+				Programs:      []Program{},
+				Streams:       []Stream{{Index: 0, PID: 100, Type: StreamVideo, Codec: "h264", Width: 720, Height: 576, FrameRate: 25}},
+			},
+		},
+		{
+			name: "unexpected shapes are skipped",
+			doc: `{
+				"container": 42,
+				"streams": [null, "x", {"type": "attachment", "codec_name": "ttf", "width": "N/A"}],
+				"programs": {"not": "a list"}
+			}`,
+			want: Ifmt{
+				Container: "42",
+				Programs:  []Program{},
+				Streams:   []Stream{{Index: 0, Codec: "ttf"}},
+			},
+		},
+		{
+			name: "newer schema",
+			doc:  `{"schema_version": 99, "container": "mpegts"}`,
+			want: Ifmt{SchemaVersion: 99, UnknownSchema: true, Container: "mpegts", Programs: []Program{}, Streams: []Stream{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Ifmt
+			if err := json.Unmarshal([]byte(tt.doc), &got); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got  %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIfmtUnmarshalJSONNotObject(t *testing.T) {
+	for _, doc := range []string{`[]`, `"mpegts"`, `{`} {
+		var f Ifmt
+		if err := json.Unmarshal([]byte(doc), &f); err == nil {
+			t.Errorf("Unmarshal(%s) = nil, want error", doc)
+		}
+	}
+}
+
+func TestIfmtHasCodec(t *testing.T) {
+	f := &Ifmt{Streams: []Stream{{Type: StreamVideo, Codec: "hevc"}, {Type: StreamAudio, Codec: "aac"}}}
+
+	for _, codec := range []string{"hevc", "H.265", "h265", "AAC"} {
+		if !f.HasCodec(codec) {
+			t.Errorf("HasCodec(%q) = false", codec)
+		}
+	}
+	if f.HasCodec("h264") {
+		t.Error(`HasCodec("h264") = true`)
+	}
+	if !f.HasStreamType(StreamAudio) || f.HasStreamType(StreamSubtitle) {
+		t.Error("HasStreamType mismatch")
+	}
+
+	var none *Ifmt
+	if none.HasCodec("hevc") || none.HasStreamType(StreamVideo) {
+		t.Error("nil Ifmt reports streams")
+	}
+}
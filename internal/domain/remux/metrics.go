@@ -0,0 +1,151 @@
+package remux
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+)
+
+// MetricsSchemaVersion is the newest remux:<id>:metrics layout this server
+// knows. Documents without a version are decoded as version 0; newer ones
+// are decoded best-effort and flagged (UnknownSchema).
+const MetricsSchemaVersion = 1
+
+// Metrics mirrors the JSON stored at remux:<id>:metrics: the counters of
+// an online remuxer.
+//
+// The well-known counters are typed (input totals, and per output);
+// every numeric (or boolean) leaf of the document is also kept in Counters
+// under its dotted path (object keys and array indexes, e.g.
+// "input.bitrate", "outputs.0.packets").
+type Metrics struct {
+	SchemaVersion    int             `json:"schema_version"`
+	UnknownSchema    bool            `json:"unknown_schema,omitempty"` // schema_version is newer than MetricsSchemaVersion
+	Bitrate          float64         `json:"bitrate"`                  // input bits/s
+	Packets          int64           `json:"packets"`                  // input packets read
+	Bytes            int64           `json:"bytes"`                    // input bytes read
+	ContinuityErrors int64           `json:"continuity_errors"`
+	Outputs          []OutputMetrics `json:"outputs"`
+
+	Counters map[string]float64 `json:"counters"`
+}
+
+// OutputMetrics are the counters of one output.
+type OutputMetrics struct {
+	Ref     string  `json:"ref"`     // output ref; the position in the document when remux names none
+	Bitrate float64 `json:"bitrate"` // bits/s
+	Packets int64   `json:"packets"` // packets written
+	Bytes   int64   `json:"bytes"`   // bytes written
+}
+
+// UnmarshalJSON decodes tolerantly (see decode.go); only a non-object
+// document is an error. The typed counters are read at the top level or
+// under "input".
+func (m *Metrics) UnmarshalJSON(b []byte) error {
+	var doc map[string]any
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+	o := object(doc)
+
+	lookup := func(names ...string) float64 {
+		keys := make([]string, 0, 2*len(names))
+		keys = append(keys, names...)
+		for _, name := range names {
+			keys = append(keys, "input."+name)
+		}
+		return o.float(keys...)
+	}
+
+	*m = Metrics{
+		SchemaVersion:    int(o.int("schema_version", "version")),
+		Bitrate:          lookup("bitrate", "bitrate_bps", "bps"),
+		Packets:          int64(lookup("packets", "packet_count", "pkts")),
+		Bytes:            int64(lookup("bytes", "bytes_read")),
+		ContinuityErrors: int64(lookup("continuity_errors", "cc_errors", "cc_errors_count")),
+		Outputs:          decodeOutputMetrics(doc["outputs"]),
+		Counters:         make(map[string]float64),
+	}
+	m.UnknownSchema = m.SchemaVersion > MetricsSchemaVersion
+	flatten(m.Counters, "", doc)
+	return nil
+}
+
+// decodeOutputMetrics reads "outputs" as a list of objects (named by their
+// ref, id or name field) or as an object keyed by ref. Refs are unique: a
+// repeated one is dropped.
+func decodeOutputMetrics(v any) []OutputMetrics {
+	outs := []OutputMetrics{}
+	seen := make(map[string]bool)
+	add := func(ref string, o object) {
+		if o == nil || seen[ref] {
+			return
+		}
+		seen[ref] = true
+		outs = append(outs, OutputMetrics{
+			Ref:     ref,
+			Bitrate: o.float("bitrate", "bitrate_bps", "bps"),
+			Packets: o.int("packets", "packet_count", "pkts"),
+			Bytes:   o.int("bytes", "bytes_written"),
+		})
+	}
+
+	if l := asList(v); l != nil {
+		for i, e := range l {
+			o := asObject(e)
+			ref := o.str("ref", "id", "name")
+			if ref == "" {
+				ref = strconv.Itoa(i)
+			}
+			add(ref, o)
+		}
+		return outs
+	}
+	byRef := asObject(v)
+	refs := make([]string, 0, len(byRef))
+	for ref := range byRef {
+		refs = append(refs, ref)
+	}
+	slices.Sort(refs)
+	for _, ref := range refs {
+		add(ref, asObject(byRef[ref]))
+	}
+	return outs
+}
+
+// Counter returns the counter at a dotted path.
+func (m *Metrics) Counter(path string) (float64, bool) {
+	if m == nil {
+		return 0, false
+	}
+	v, ok := m.Counters[path]
+	return v, ok
+}
+
+func flatten(out map[string]float64, path string, v any) {
+	join := func(k string) string {
+		if path == "" {
+			return k
+		}
+		return path + "." + k
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		for k, c := range v {
+			flatten(out, join(k), c)
+		}
+	case []any:
+		for i, c := range v {
+			flatten(out, join(strconv.Itoa(i)), c)
+		}
+	case float64:
+		out[path] = v
+	case bool:
+		if v {
+			out[path] = 1
+		} else {
+			out[path] = 0
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package remux
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMetricsUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		want     Metrics // Counters checked separately
+		counters map[string]float64
+	}{
+		{
+			name: "top level",
+			doc:  `{"schema_version": 1, "bitrate": 5000000.5, "packets": 1000, "bytes": 188000, "continuity_errors": 2}`,
+			want: Metrics{SchemaVersion: 1, Bitrate: 5000000.5, Packets: 1000, Bytes: 188000, ContinuityErrors: 2, Outputs: []OutputMetrics{}},
+			counters: map[string]float64{
+				"schema_version": 1, "bitrate": 5000000.5, "packets": 1000, "bytes": 188000, "continuity_errors": 2,
+			},
+		},
+		{
+			name: "under input, aliases and strings",
+			doc:  `{"version": "1", "input": {"bitrate_bps": "4000000", "packet_count": "10", "bytes_read": 1880, "cc_errors": 1, "locked": true}}`,
+			want: Metrics{SchemaVersion: 1, Bitrate: 4000000, Packets: 10, Bytes: 1880, ContinuityErrors: 1, Outputs: []OutputMetrics{}},
+			// String leaves are decoded into the typed fields but not flattened.
+			counters: map[string]float64{"input.bytes_read": 1880, "input.cc_errors": 1, "input.locked": 1},
+		},
+		{
+			name: "outputs list",
+			doc: `{"outputs": [
+				{"ref": "onprem_mr01", "bitrate": 1000, "packets": 5, "bytes": 940},
+				{"id": "pubcloud_sky320", "bps": "2000", "pkts": "6"},
+				{"bitrate": 3000},
+				{"ref": "onprem_mr01", "bitrate": 9999},
+				"junk"
+			]}`,
+			want: Metrics{Outputs: []OutputMetrics{
+				{Ref: "onprem_mr01", Bitrate: 1000, Packets: 5, Bytes: 940},
+				{Ref: "pubcloud_sky320", Bitrate: 2000, Packets: 6},
+				{Ref: "2", Bitrate: 3000},
+			}},
+			counters: map[string]float64{
+				"outputs.0.bitrate": 1000, "outputs.0.packets": 5, "outputs.0.bytes": 940,
+				"outputs.2.bitrate": 3000,
+				"outputs.3.bitrate": 9999,
+			},
+		},
+		{
+			name: "outputs by ref",
+			doc:  `{"outputs": {"b": {"bitrate": 2}, "a": {"bitrate": 1, "bytes_written": 188}, "c": 3}}`,
+			want: Metrics{Outputs: []OutputMetrics{{Ref: "a", Bitrate: 1, Bytes: 188}, {Ref: "b", Bitrate: 2}}},
+			counters: map[string]float64{
+				"outputs.a.bitrate": 1, "outputs.a.bytes_written": 188, "outputs.b.bitrate": 2, "outputs.c": 3,
+			},
+		},
+		{
+			name:     "newer schema",
+			doc:      `{"schema_version": 2, "bitrate": 1}`,
+			want:     Metrics{SchemaVersion: 2, UnknownSchema: true, Bitrate: 1, Outputs: []OutputMetrics{}},
+			counters: map[string]float64{"schema_version": 2, "bitrate": 1},
+		},
+		{
+			name:     "empty",
+			doc:      `{}`,
+			want:     Metrics{Outputs: []OutputMetrics{}},
+			counters: map[string]float64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Metrics
+			if err := json.Unmarshal([]byte(tt.doc), &got); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			counters := got.Counters
+			got.Counters = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got  %+v\nwant %+v", got, tt.want)
+			}
+			if tt.counters != nil && !reflect.DeepEqual(counters, tt.counters) {
+				t.Fatalf("counters %v\nwant     %v", counters, tt.counters)
+			}
+		})
+	}
+}
+
+func TestMetricsUnmarshalJSONNotObject(t *testing.T) {
+	for _, doc := range []string{`[]`, `1`, `null,`} {
+		var m Metrics
+		if err := json.Unmarshal([]byte(doc), &m); err == nil {
+			t.Errorf("Unmarshal(%s) = nil, want error", doc)
+		}
+	}
+}
+
+func TestMetricsCounter(t *testing.T) {
+	var m Metrics
+	if err := json.Unmarshal([]byte(`{"input": {"bitrate": 7}}`), &m); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := m.Counter("input.bitrate"); !ok || v != 7 {
+		t.Fatalf("Counter(input.bitrate) = %v, %v", v, ok)
+	}
+	if _, ok := m.Counter("input.missing"); ok {
+		t.Fatal("Counter(input.missing) found")
+	}
+	var none *Metrics
+	if _, ok := none.Counter("bitrate"); ok {
+		t.Fatal("nil Metrics has counters")
+	}
+}
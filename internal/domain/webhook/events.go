@@ -0,0 +1,41 @@
+package webhook
+
+// EventType names a webhook notification.
+type EventType string
+
+const (
+	EventChannelOnline        EventType = "channel.online"         // remux status offline → online
+	EventChannelOffline       EventType = "channel.offline"        // remux status online → offline
+	EventChannelCrashLoop     EventType = "channel.crash_loop"     // crash-loop breaker tripped (process parked as failed)
+	EventChannelQuotaExceeded EventType = "channel.quota_exceeded" // channel write rejected by a B2B client quota
+	EventB2BClientCreated     EventType = "b2b_client.created"
+	EventB2BClientUpdated     EventType = "b2b_client.updated"
+	EventB2BClientDeleted     EventType = "b2b_client.deleted"
+	EventAlertFiring          EventType = "alert.firing"   // alert rule condition held for its for_sec (see /api/alerts)
+	EventAlertResolved        EventType = "alert.resolved" // firing alert's condition cleared
+	EventPing                 EventType = "ping"           // test delivery (POST /api/webhooks/:id/ping); always sent
+)
+
+// EventAll subscribes a webhook to every event type.
+const EventAll = "*"
+
+// EventTypes lists the subscribable event types.
+var EventTypes = []EventType{
+	EventChannelOnline,
+	EventChannelOffline,
+	EventChannelCrashLoop,
+	EventChannelQuotaExceeded,
+	EventB2BClientCreated,
+	EventB2BClientUpdated,
+	EventB2BClientDeleted,
+	EventAlertFiring,
+	EventAlertResolved,
+}
+
+// Event is the JSON body POSTed to a webhook URL.
+type Event struct {
+	ID   string    `json:"id"` // delivery ID; identical across retries (X-Zmux-Delivery)
+	Type EventType `json:"type"`
+	At   int64     `json:"at"` // UTC millis when the event occurred
+	Data any       `json:"data"`
+}
@@ -0,0 +1,43 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+)
+
+// Validate checks the request.
+func (r *WebhookResource) Validate() error {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return fmt.Errorf("url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("url: scheme must be http or https")
+	}
+	if u.Host == "" {
+		return errors.New("url: host is required")
+	}
+	if u.User != nil {
+		return errors.New("url: userinfo is not allowed (requests are authenticated by signature)")
+	}
+
+	if r.Secret != nil && (len(*r.Secret) < 16 || len(*r.Secret) > 256) {
+		return errors.New("secret must be between 16 and 256 characters")
+	}
+
+	if len(r.Events) == 0 {
+		return fmt.Errorf("events: at least one event type (or %q) is required", EventAll)
+	}
+	for _, e := range r.Events {
+		if e != EventAll && !slices.Contains(EventTypes, EventType(e)) {
+			return fmt.Errorf("events: unknown event type %q", e)
+		}
+	}
+
+	if len(r.Description) > 256 {
+		return errors.New("description must be at most 256 characters")
+	}
+	return nil
+}
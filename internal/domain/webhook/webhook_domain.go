@@ -0,0 +1,80 @@
+package webhook
+
+import "slices"
+
+// Domain (Application Layer; Core runtime object)
+type Webhook struct {
+	ID          int64
+	URL         string
+	Secret      string
+	Events      []string
+	Enabled     bool
+	Description string
+	CreatedAt   int64
+	UpdatedAt   int64
+}
+
+// DB (Model) + ID → Domain
+func NewWebhook(model *WebhookModel, id int64) *Webhook {
+	if model == nil {
+		return nil
+	}
+
+	return &Webhook{
+		ID:          id,
+		URL:         model.URL,
+		Secret:      model.Secret,
+		Events:      slices.Clone(model.Events),
+		Enabled:     model.Enabled,
+		Description: model.Description,
+		CreatedAt:   model.CreatedAt,
+		UpdatedAt:   model.UpdatedAt,
+	}
+}
+
+// Domain → DB (Model)
+func (w *Webhook) Model() *WebhookModel {
+	if w == nil {
+		return nil
+	}
+
+	return &WebhookModel{
+		URL:         w.URL,
+		Secret:      w.Secret,
+		Events:      slices.Clone(w.Events),
+		Enabled:     w.Enabled,
+		Description: w.Description,
+		CreatedAt:   w.CreatedAt,
+		UpdatedAt:   w.UpdatedAt,
+	}
+}
+
+// Domain → API Response (View)
+func (w *Webhook) View() *WebhookView {
+	if w == nil {
+		return nil
+	}
+
+	return &WebhookView{
+		ID:          w.ID,
+		URL:         w.URL,
+		Events:      slices.Clone(w.Events),
+		Enabled:     w.Enabled,
+		Description: w.Description,
+		CreatedAt:   w.CreatedAt,
+		UpdatedAt:   w.UpdatedAt,
+	}
+}
+
+// Subscribed reports whether the webhook is enabled and wants events of type t.
+func (w *Webhook) Subscribed(t EventType) bool {
+	if !w.Enabled {
+		return false
+	}
+	for _, e := range w.Events {
+		if e == EventAll || e == string(t) {
+			return true
+		}
+	}
+	return false
+}
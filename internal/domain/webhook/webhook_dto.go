@@ -0,0 +1,37 @@
+package webhook
+
+// DTO (API Layer; Request schema)
+//   - POST: secret optional; generated when omitted (returned once).
+//   - PUT:  secret optional; null/omitted keeps the current secret.
+type WebhookResource struct {
+	URL         string   `json:"url"`
+	Secret      *string  `json:"secret"`
+	Events      []string `json:"events"`
+	Enabled     bool     `json:"enabled"`
+	Description string   `json:"description"`
+}
+
+// DTO (API Layer; Response schema) — the secret is only returned by create
+type WebhookView struct {
+	ID          int64    `json:"id"`
+	URL         string   `json:"url"`
+	Secret      string   `json:"secret,omitempty"`
+	Events      []string `json:"events"`
+	Enabled     bool     `json:"enabled"`
+	Description string   `json:"description"`
+	CreatedAt   int64    `json:"created_at"`
+	UpdatedAt   int64    `json:"updated_at"`
+}
+
+// DTO (API Layer; Response schema) — one delivery attempt
+type DeliveryView struct {
+	DeliveryID  string `json:"delivery_id"` // shared by all attempts of one event
+	Event       string `json:"event"`
+	Attempt     int    `json:"attempt"`                 // 1-based
+	At          int64  `json:"at"`                      // UTC millis
+	DurationMs  int64  `json:"duration_ms"`             //
+	StatusCode  int    `json:"status_code,omitempty"`   // absent when no response was received
+	Error       string `json:"error,omitempty"`         // transport error or non-2xx summary
+	Success     bool   `json:"success"`                 //
+	NextRetryAt int64  `json:"next_retry_at,omitempty"` // UTC millis; absent when no retry follows
+}
@@ -0,0 +1,12 @@
+package webhook
+
+// DB (Persistence Layer; Redis record)
+type WebhookModel struct {
+	URL         string   `json:"url"`
+	Secret      string   `json:"secret"` // HMAC-SHA256 signing key
+	Events      []string `json:"events"` // EventType values; "*" subscribes to all
+	Enabled     bool     `json:"enabled"`
+	Description string   `json:"description,omitempty"`
+	CreatedAt   int64    `json:"created_at"` // UTC millis
+	UpdatedAt   int64    `json:"updated_at"` // UTC millis
+}
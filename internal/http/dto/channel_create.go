@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/edirooss/zmux-server/internal/domain/channel"
+	outputdestination "github.com/edirooss/zmux-server/internal/domain/output-destination"
 )
 
 // ChannelCreate is the DTO for creating a new Zmux channel via
@@ -18,6 +19,11 @@ type ChannelCreate struct {
 	Outputs     W[[]W[ChannelOutputCreate]] `json:"outputs"`       //   optional; array[object]                       (default: [])
 	Enabled     W[bool]                     `json:"enabled"`       //   optional; bool                                (default: false)
 	RestartSec  W[uint]                     `json:"restart_sec"`   //   optional; uint                                (default: 3)
+
+	RestartMaxDelaySec    W[uint] `json:"restart_max_delay_sec"`    //   optional; uint  (default: 60)
+	RestartResetSec       W[uint] `json:"restart_reset_sec"`        //   optional; uint  (default: 60)
+	StartLimitBurst       W[uint] `json:"start_limit_burst"`        //   optional; uint  (default: 10)
+	StartLimitIntervalSec W[uint] `json:"start_limit_interval_sec"` //   optional; uint  (default: 600)
 }
 
 type ChannelInputCreate struct {
@@ -36,17 +42,18 @@ type ChannelInputCreate struct {
 
 type ChannelOutputCreate struct {
 	Ref           W[string]   `json:"ref"`            //                   optional; string          (default: itoa(index))
-	URL           W[string]   `json:"url"`            //                   optional; string | null   (default: null)
+	URL           W[string]   `json:"url"`            //                   optional; string | null   (default: destination default_url, else null)
 	Localaddr     W[string]   `json:"localaddr"`      //                   optional; string | null   (default: null)
-	PktSize       W[uint]     `json:"pkt_size"`       //                   optional; uint            (default: 1316)
-	StreamMapping W[[]string] `json:"stream_mapping"` //                   optional; []string        (default: ["video"])
+	PktSize       W[uint]     `json:"pkt_size"`       //                   optional; uint            (default: destination pkt_size, else 1316)
+	StreamMapping W[[]string] `json:"stream_mapping"` //                   optional; []string        (default: destination stream_mapping, else ["video"])
 	Enabled       W[bool]     `json:"enabled"`        //                   optional; bool            (default: true)
 }
 
 // ToChannel maps CreateChannel → channel.ZmuxChannel
 // Disallows explicit null assignment to non-nullable fields.
-// Fills unset fields with defaults.
-func (req *ChannelCreate) ToChannel() (*channel.ZmuxChannel, error) {
+// Fills unset fields with defaults; output defaults come from the output
+// destination of the ref when lookup knows it.
+func (req *ChannelCreate) ToChannel(lookup func(ref string) *outputdestination.OutputDestination) (*channel.ZmuxChannel, error) {
 	ch := &channel.ZmuxChannel{}
 
 	// b2bclnt_id
@@ -104,7 +111,7 @@ func (req *ChannelCreate) ToChannel() (*channel.ZmuxChannel, error) {
 			if output.Null {
 				return nil, fmt.Errorf("outputs[%d] cannot be null", i)
 			}
-			chOutput, err := output.V.ToChannelOutput(i)
+			chOutput, err := output.V.ToChannelOutput(i, lookup)
 			if err != nil {
 				return nil, fmt.Errorf("outputs[%d]: %w", i, err)
 			}
@@ -134,7 +141,28 @@ func (req *ChannelCreate) ToChannel() (*channel.ZmuxChannel, error) {
 		}
 		ch.RestartSec = req.RestartSec.V
 	} else {
-		ch.RestartSec = 3
+		ch.RestartSec = channel.DefaultRestartSec
+	}
+
+	// restart policy
+	// optional; uint (defaults: see channel.SetRestartPolicyDefaults)
+	ch.SetRestartPolicyDefaults()
+	for _, f := range []struct {
+		name string
+		req  W[uint]
+		dst  *uint
+	}{
+		{"restart_max_delay_sec", req.RestartMaxDelaySec, &ch.RestartMaxDelaySec},
+		{"restart_reset_sec", req.RestartResetSec, &ch.RestartResetSec},
+		{"start_limit_burst", req.StartLimitBurst, &ch.StartLimitBurst},
+		{"start_limit_interval_sec", req.StartLimitIntervalSec, &ch.StartLimitIntervalSec},
+	} {
+		if f.req.Set {
+			if f.req.Null {
+				return nil, fmt.Errorf("%s cannot be null", f.name)
+			}
+			*f.dst = f.req.V
+		}
 	}
 
 	return ch, nil
@@ -280,8 +308,8 @@ func (req *ChannelInputCreate) ToChannelInput() (*channel.ZmuxChannelInput, erro
 
 // ToChannelOutput maps CreateChannelOutput → channel.ZmuxChannelOutput
 // Disallows explicit null assignment to non-nullable fields.
-// Fills unset fields with defaults.
-func (req *ChannelOutputCreate) ToChannelOutput(index int) (*channel.ZmuxChannelOutput, error) {
+// Fills unset fields with defaults (of the ref's output destination, if any).
+func (req *ChannelOutputCreate) ToChannelOutput(index int, lookup func(ref string) *outputdestination.OutputDestination) (*channel.ZmuxChannelOutput, error) {
 	output := &channel.ZmuxChannelOutput{}
 
 	// ref
@@ -295,8 +323,13 @@ func (req *ChannelOutputCreate) ToChannelOutput(index int) (*channel.ZmuxChannel
 		output.Ref = strconv.Itoa(index)
 	}
 
+	defaults := channel.ZmuxChannelOutput{PktSize: 1316, StreamMapping: channel.StreamMapping{"video"}}
+	if dest := lookup(output.Ref); dest != nil {
+		defaults = dest.DefaultOutput()
+	}
+
 	// url
-	// optional; string | null (default: null)
+	// optional; string | null (default: destination default_url, else null)
 	if req.URL.Set {
 		if req.URL.Null {
 			output.URL = nil
@@ -304,7 +337,7 @@ func (req *ChannelOutputCreate) ToChannelOutput(index int) (*channel.ZmuxChannel
 			output.URL = &req.URL.V
 		}
 	} else {
-		output.URL = nil
+		output.URL = defaults.URL
 	}
 
 	// localaddr
@@ -320,25 +353,25 @@ func (req *ChannelOutputCreate) ToChannelOutput(index int) (*channel.ZmuxChannel
 	}
 
 	// pkt_size
-	// optional; uint (default: 1316)
+	// optional; uint (default: destination pkt_size, else 1316)
 	if req.PktSize.Set {
 		if req.PktSize.Null {
 			return nil, errors.New("pkt_size cannot be null")
 		}
 		output.PktSize = req.PktSize.V
 	} else {
-		output.PktSize = 1316
+		output.PktSize = defaults.PktSize
 	}
 
 	// stream_mapping
-	// optional; []string (default: ["video"])
+	// optional; []string (default: destination stream_mapping, else ["video"])
 	if req.StreamMapping.Set {
 		if req.StreamMapping.Null {
 			return nil, errors.New("stream_mapping cannot be null")
 		}
 		output.StreamMapping = req.StreamMapping.V
 	} else {
-		output.StreamMapping = channel.StreamMapping{"video"}
+		output.StreamMapping = defaults.StreamMapping
 	}
 
 	// enabled
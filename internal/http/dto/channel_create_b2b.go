@@ -0,0 +1,107 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+
+	b2bclient "github.com/edirooss/zmux-server/internal/domain/b2b-client"
+	"github.com/edirooss/zmux-server/internal/domain/channel"
+)
+
+// B2BChannelCreate is the DTO for a B2B client creating its own channel via
+// POST /api/channels. Same fields as views.B2BClientZmuxChannel:
+//   - All fields are optional.
+//   - Everything else (tuning, output routing, restart policy) comes from the
+//     client's channel template; the owner is the calling client.
+type B2BChannelCreate struct {
+	Name    W[string]                               `json:"name"`    //   optional; string | null                 (default: null)
+	Input   W[B2BChannelInputCreate]                `json:"input"`   //   optional; object                        (default: {})
+	Outputs W[map[string]W[B2BChannelOutputCreate]] `json:"outputs"` //   optional; object[string:object]         (default: {}; keys: toggleable template output refs)
+	Enabled W[bool]                                 `json:"enabled"` //   optional; bool                          (default: false)
+}
+
+type B2BChannelInputCreate struct {
+	URL      W[string] `json:"url"`      //       optional; string | null   (default: null)
+	Username W[string] `json:"username"` //       optional; string | null   (default: null)
+	Password W[string] `json:"password"` //       optional; string | null   (default: null)
+}
+
+type B2BChannelOutputCreate struct {
+	Enabled W[bool] `json:"enabled"` //                   optional; bool            (default: template output enabled)
+}
+
+// ToChannel maps B2BChannelCreate → channel.ZmuxChannel of the B2B client,
+// built on its channel template.
+// Disallows explicit null assignment to non-nullable fields.
+// Fills unset fields from the template.
+// toggleable reports the output refs the client may set (see the output
+// destination catalog); other refs wrap ErrUnauthorized.
+func (req *B2BChannelCreate) ToChannel(b2bClientID int64, tmpl *b2bclient.ChannelTemplate, toggleable func(ref string) bool) (*channel.ZmuxChannel, error) {
+	ch := tmpl.NewChannel(b2bClientID)
+
+	// name
+	// optional; string | null (default: null)
+	if req.Name.Set && !req.Name.Null {
+		ch.Name = &req.Name.V
+	}
+
+	// input
+	// optional; object (default: {})
+	if req.Input.Set {
+		if req.Input.Null {
+			return nil, errors.New("input cannot be null")
+		}
+		in := req.Input.V
+		for _, f := range []struct {
+			req W[string]
+			dst **string
+		}{
+			{in.URL, &ch.Input.URL},
+			{in.Username, &ch.Input.Username},
+			{in.Password, &ch.Input.Password},
+		} {
+			if f.req.Set && !f.req.Null {
+				v := f.req.V
+				*f.dst = &v
+			}
+		}
+	}
+
+	// outputs
+	// optional; object[string:object] (default: {})
+	if req.Outputs.Set {
+		if req.Outputs.Null {
+			return nil, errors.New("outputs cannot be null")
+		}
+		outputsByRef := ch.OutputsByRef()
+		for ref, output := range req.Outputs.V {
+			entry, ok := outputsByRef[ref]
+			if !ok {
+				return nil, fmt.Errorf("outputs ref %q does not exist", ref)
+			}
+			if output.Null {
+				return nil, fmt.Errorf("outputs[%s] cannot be null", ref)
+			}
+			if !toggleable(ref) {
+				return nil, unauthorized(fmt.Sprintf("outputs[%s]", ref))
+			}
+			if output.V.Enabled.Set {
+				if output.V.Enabled.Null {
+					return nil, fmt.Errorf("outputs[%s].enabled cannot be null", ref)
+				}
+				ch.Outputs[entry.Index].Enabled = output.V.Enabled.V
+			}
+		}
+	}
+
+	// enabled
+	// optional; bool (default: false)
+	if req.Enabled.Set {
+		if req.Enabled.Null {
+			return nil, errors.New("enabled cannot be null")
+		}
+		ch.Enabled = req.Enabled.V
+	}
+
+	return ch, nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	b2bclient "github.com/edirooss/zmux-server/internal/domain/b2b-client"
 	"github.com/edirooss/zmux-server/internal/domain/channel"
 	"github.com/edirooss/zmux-server/internal/domain/principal"
 )
@@ -19,6 +20,11 @@ type ChannelModify struct {
 	Outputs     W[json.RawMessage]    `json:"outputs"`       //   optional; array[object] | object[string:object]
 	Enabled     W[bool]               `json:"enabled"`       //   optional; bool
 	RestartSec  W[uint]               `json:"restart_sec"`   //   optional; uint
+
+	RestartMaxDelaySec    W[uint] `json:"restart_max_delay_sec"`    //   optional; uint
+	RestartResetSec       W[uint] `json:"restart_reset_sec"`        //   optional; uint
+	StartLimitBurst       W[uint] `json:"start_limit_burst"`        //   optional; uint
+	StartLimitIntervalSec W[uint] `json:"start_limit_interval_sec"` //   optional; uint
 }
 
 type ChannelInputModify struct {
@@ -47,14 +53,30 @@ type ChannelOutputModify struct {
 // MergePatch applies ModifyChannel to channel.ZmuxChannel (in-memory)
 // Disallows explicit null assignment to non-nullable fields.
 // Unset fields remain unchanged.
-// Enforce field-level authorization based on principal kind.
-func (req *ChannelModify) MergePatch(prev *channel.ZmuxChannel, pKind principal.PrincipalKind) error {
+// Enforce field-level authorization based on the principal's effective permissions:
+// basic fields require channels:edit, advanced fields require channels:configure.
+// Authorization failures wrap ErrUnauthorized.
+// toggleable reports the output refs principals without channels:configure
+// may modify (see the output destination catalog).
+// policy is the B2B client policy of the channel's owner (nil: none); patches
+// that break it wrap b2bclient.ErrPolicyViolation. Ownership changes are
+// checked against the new owner's policy by the service.
+func (req *ChannelModify) MergePatch(prev *channel.ZmuxChannel, perms principal.Permissions, toggleable func(ref string) bool, policy *b2bclient.Policy) error {
+	if !perms.Has(principal.PermChannelsEdit) && !perms.Has(principal.PermChannelsConfigure) {
+		return unauthorized("channel")
+	}
+
+	var orig *channel.ZmuxChannel
+	if policy != nil && !req.B2BClientID.Set {
+		orig = prev.DeepClone()
+	}
+
 	// b2blnt_id
 	// optional; int64 | null
-	// admin-only
+	// requires channels:configure
 	if req.B2BClientID.Set {
-		if pKind != principal.Admin {
-			return errors.New("b2blnt_id set unauthorized")
+		if !perms.Has(principal.PermChannelsConfigure) {
+			return unauthorized("b2blnt_id")
 		}
 		if req.B2BClientID.Null {
 			prev.B2BClientID = nil
@@ -79,7 +101,7 @@ func (req *ChannelModify) MergePatch(prev *channel.ZmuxChannel, pKind principal.
 		if req.Input.Null {
 			return errors.New("input cannot be null")
 		}
-		if err := req.Input.V.MergePatch(&prev.Input, pKind); err != nil {
+		if err := req.Input.V.MergePatch(&prev.Input, perms); err != nil {
 			return err
 		}
 	}
@@ -96,6 +118,10 @@ func (req *ChannelModify) MergePatch(prev *channel.ZmuxChannel, pKind principal.
 		if err := json.Unmarshal(req.Outputs.V, &outputsList); err == nil {
 			// per RFC 7396 (JSON Merge Patch), arrays treated as atomic values.
 			// i.e., not “merging” elements — replacing the whole array (PUT-like semantics)
+			// requires channels:configure (sets routing fields: url, localaddr, ...)
+			if !perms.Has(principal.PermChannelsConfigure) {
+				return unauthorized("outputs")
+			}
 			chOutputs := make([]channel.ZmuxChannelOutput, 0, len(outputsList))
 			for i, output := range outputsList {
 				if output.Null {
@@ -123,11 +149,10 @@ func (req *ChannelModify) MergePatch(prev *channel.ZmuxChannel, pKind principal.
 					if output.Null {
 						return fmt.Errorf("outputs[%s] cannot be null", ref)
 					}
-					if pKind != principal.Admin &&
-						(ref != "onprem_mr01" && ref != "onprem_mz01" && ref != "pubcloud_sky320") {
-						return fmt.Errorf("outputs[%s] set unauthorized", ref)
+					if !perms.Has(principal.PermChannelsConfigure) && !toggleable(ref) {
+						return unauthorized(fmt.Sprintf("outputs[%s]", ref))
 					}
-					if err := output.V.MergePatch(&prev.Outputs[outputEntry.Index], pKind); err != nil {
+					if err := output.V.MergePatch(&prev.Outputs[outputEntry.Index], perms); err != nil {
 						return fmt.Errorf("outputs[%s]: %w", ref, err)
 					}
 				}
@@ -149,10 +174,10 @@ func (req *ChannelModify) MergePatch(prev *channel.ZmuxChannel, pKind principal.
 
 	// restart_sec
 	// optional; uint
-	// admin-only
+	// requires channels:configure
 	if req.RestartSec.Set {
-		if pKind != principal.Admin {
-			return errors.New("restart_sec set unauthorized")
+		if !perms.Has(principal.PermChannelsConfigure) {
+			return unauthorized("restart_sec")
 		}
 		if req.RestartSec.Null {
 			return errors.New("restart_sec cannot be null")
@@ -160,14 +185,53 @@ func (req *ChannelModify) MergePatch(prev *channel.ZmuxChannel, pKind principal.
 		prev.RestartSec = req.RestartSec.V
 	}
 
+	// restart policy
+	// optional; uint
+	// requires channels:configure
+	for _, f := range []struct {
+		name string
+		req  W[uint]
+		dst  *uint
+	}{
+		{"restart_max_delay_sec", req.RestartMaxDelaySec, &prev.RestartMaxDelaySec},
+		{"restart_reset_sec", req.RestartResetSec, &prev.RestartResetSec},
+		{"start_limit_burst", req.StartLimitBurst, &prev.StartLimitBurst},
+		{"start_limit_interval_sec", req.StartLimitIntervalSec, &prev.StartLimitIntervalSec},
+	} {
+		if !f.req.Set {
+			continue
+		}
+		if !perms.Has(principal.PermChannelsConfigure) {
+			return unauthorized(f.name)
+		}
+		if f.req.Null {
+			return fmt.Errorf("%s cannot be null", f.name)
+		}
+		*f.dst = f.req.V
+	}
+
+	// policy
+	if orig != nil {
+		if err := policy.Check(orig, prev); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// ErrUnauthorized marks a patch touching a field the principal may not set.
+var ErrUnauthorized = errors.New("unauthorized")
+
+func unauthorized(field string) error {
+	return fmt.Errorf("%s set %w", field, ErrUnauthorized)
+}
+
 // MergePatch applies ModifyChannelInput to channel.ZmuxChannelInput (in-memory)
 // Disallows explicit null assignment to non-nullable fields.
 // Unset fields remain unchanged.
-// Enforce permission based on principal kind.
-func (req *ChannelInputModify) MergePatch(prev *channel.ZmuxChannelInput, pKind principal.PrincipalKind) error {
+// Enforce permission based on the principal's effective permissions.
+func (req *ChannelInputModify) MergePatch(prev *channel.ZmuxChannelInput, perms principal.Permissions) error {
 	// url
 	// optional; string | null
 	if req.URL.Set {
@@ -200,10 +264,10 @@ func (req *ChannelInputModify) MergePatch(prev *channel.ZmuxChannelInput, pKind
 
 	// avioflags
 	// optional; string | null
-	// admin-only
+	// requires channels:configure
 	if req.AVIOFlags.Set {
-		if pKind != principal.Admin {
-			return errors.New("avioflags set unauthorized")
+		if !perms.Has(principal.PermChannelsConfigure) {
+			return unauthorized("avioflags")
 		}
 		if req.AVIOFlags.Null {
 			prev.AVIOFlags = nil
@@ -214,10 +278,10 @@ func (req *ChannelInputModify) MergePatch(prev *channel.ZmuxChannelInput, pKind
 
 	// probesize
 	// optional; uint
-	// admin-only
+	// requires channels:configure
 	if req.Probesize.Set {
-		if pKind != principal.Admin {
-			return errors.New("probesize set unauthorized")
+		if !perms.Has(principal.PermChannelsConfigure) {
+			return unauthorized("probesize")
 		}
 		if req.Probesize.Null {
 			return errors.New("probesize cannot be null")
@@ -227,10 +291,10 @@ func (req *ChannelInputModify) MergePatch(prev *channel.ZmuxChannelInput, pKind
 
 	// analyzeduration
 	// optional; uint
-	// admin-only
+	// requires channels:configure
 	if req.Analyzeduration.Set {
-		if pKind != principal.Admin {
-			return errors.New("analyzeduration set unauthorized")
+		if !perms.Has(principal.PermChannelsConfigure) {
+			return unauthorized("analyzeduration")
 		}
 		if req.Analyzeduration.Null {
 			return errors.New("analyzeduration cannot be null")
@@ -240,10 +304,10 @@ func (req *ChannelInputModify) MergePatch(prev *channel.ZmuxChannelInput, pKind
 
 	// fflags
 	// optional; string | null
-	// admin-only
+	// requires channels:configure
 	if req.FFlags.Set {
-		if pKind != principal.Admin {
-			return errors.New("fflags set unauthorized")
+		if !perms.Has(principal.PermChannelsConfigure) {
+			return unauthorized("fflags")
 		}
 		if req.FFlags.Null {
 			prev.FFlags = nil
@@ -254,10 +318,10 @@ func (req *ChannelInputModify) MergePatch(prev *channel.ZmuxChannelInput, pKind
 
 	// max_delay
 	// optional; int
-	// admin-only
+	// requires channels:configure
 	if req.MaxDelay.Set {
-		if pKind != principal.Admin {
-			return errors.New("max_delay set unauthorized")
+		if !perms.Has(principal.PermChannelsConfigure) {
+			return unauthorized("max_delay")
 		}
 		if req.MaxDelay.Null {
 			return errors.New("max_delay cannot be null")
@@ -267,10 +331,10 @@ func (req *ChannelInputModify) MergePatch(prev *channel.ZmuxChannelInput, pKind
 
 	// localaddr
 	// optional; string | null
-	// admin-only
+	// requires channels:configure
 	if req.Localaddr.Set {
-		if pKind != principal.Admin {
-			return errors.New("localaddr set unauthorized")
+		if !perms.Has(principal.PermChannelsConfigure) {
+			return unauthorized("localaddr")
 		}
 		if req.Localaddr.Null {
 			prev.Localaddr = nil
@@ -281,10 +345,10 @@ func (req *ChannelInputModify) MergePatch(prev *channel.ZmuxChannelInput, pKind
 
 	// timeout
 	// optional; uint
-	// admin-only
+	// requires channels:configure
 	if req.Timeout.Set {
-		if pKind != principal.Admin {
-			return errors.New("timeout set unauthorized")
+		if !perms.Has(principal.PermChannelsConfigure) {
+			return unauthorized("timeout")
 		}
 		if req.Timeout.Null {
 			return errors.New("timeout cannot be null")
@@ -294,10 +358,10 @@ func (req *ChannelInputModify) MergePatch(prev *channel.ZmuxChannelInput, pKind
 
 	// rtsp_transport
 	// optional; string | null
-	// admin-only
+	// requires channels:configure
 	if req.RTSPTransport.Set {
-		if pKind != principal.Admin {
-			return errors.New("rtsp_transport set unauthorized")
+		if !perms.Has(principal.PermChannelsConfigure) {
+			return unauthorized("rtsp_transport")
 		}
 		if req.RTSPTransport.Null {
 			prev.RTSPTransport = nil
@@ -312,13 +376,13 @@ func (req *ChannelInputModify) MergePatch(prev *channel.ZmuxChannelInput, pKind
 // MergePatch applies ModifyChannelOutput to channel.ZmuxChannelOutput (in-memory)
 // Disallows explicit null assignment to non-nullable fields.
 // Unset fields remain unchanged.
-// Enforce permission based on principal kind.
-func (req *ChannelOutputModify) MergePatch(prev *channel.ZmuxChannelOutput, pKind principal.PrincipalKind) error {
+// Enforce permission based on the principal's effective permissions.
+func (req *ChannelOutputModify) MergePatch(prev *channel.ZmuxChannelOutput, perms principal.Permissions) error {
 	// ref
 	// optional; string
 	if req.Ref.Set {
-		if pKind != principal.Admin {
-			return errors.New("ref set unauthorized")
+		if !perms.Has(principal.PermChannelsConfigure) {
+			return unauthorized("ref")
 		}
 		if req.Ref.Null {
 			return errors.New("ref cannot be null")
@@ -330,8 +394,8 @@ func (req *ChannelOutputModify) MergePatch(prev *channel.ZmuxChannelOutput, pKin
 	// url
 	// optional; string | null
 	if req.URL.Set {
-		if pKind != principal.Admin {
-			return errors.New("url set unauthorized")
+		if !perms.Has(principal.PermChannelsConfigure) {
+			return unauthorized("url")
 		}
 		if req.URL.Null {
 			prev.URL = nil
@@ -343,8 +407,8 @@ func (req *ChannelOutputModify) MergePatch(prev *channel.ZmuxChannelOutput, pKin
 	// localaddr
 	// optional; string | null
 	if req.Localaddr.Set {
-		if pKind != principal.Admin {
-			return errors.New("localaddr set unauthorized")
+		if !perms.Has(principal.PermChannelsConfigure) {
+			return unauthorized("localaddr")
 		}
 		if req.Localaddr.Null {
 			prev.Localaddr = nil
@@ -356,8 +420,8 @@ func (req *ChannelOutputModify) MergePatch(prev *channel.ZmuxChannelOutput, pKin
 	// pkt_size
 	// optional; uint
 	if req.PktSize.Set {
-		if pKind != principal.Admin {
-			return errors.New("pkt_size set unauthorized")
+		if !perms.Has(principal.PermChannelsConfigure) {
+			return unauthorized("pkt_size")
 		}
 		if req.PktSize.Null {
 			return errors.New("pkt_size cannot be null")
@@ -368,8 +432,8 @@ func (req *ChannelOutputModify) MergePatch(prev *channel.ZmuxChannelOutput, pKin
 	// stream_mapping
 	// optional; []string
 	if req.StreamMapping.Set {
-		if pKind != principal.Admin {
-			return errors.New("stream_mapping set unauthorized")
+		if !perms.Has(principal.PermChannelsConfigure) {
+			return unauthorized("stream_mapping")
 		}
 		if req.StreamMapping.Null {
 			return errors.New("stream_mapping cannot be null")
@@ -10,7 +10,8 @@ import (
 
 // ChannelReplace is the DTO for updating a Zmux channel via
 // PUT /api/channels/{id}. Full-replacement semantics (RFC 9110):
-//   - All fields are required.
+//   - All fields are required, except the restart policy fields (added
+//     later; unset → defaults, same as on create).
 type ChannelReplace struct {
 	B2BClientID W[int64]              `json:"b2b_client_id"` //         required; int64 | null
 	Name        W[string]             `json:"name"`          //         required; string | null
@@ -18,6 +19,11 @@ type ChannelReplace struct {
 	Outputs     W[[]W[OutputReplace]] `json:"outputs"`       //         required; array
 	Enabled     W[bool]               `json:"enabled"`       //         required; bool
 	RestartSec  W[uint]               `json:"restart_sec"`   //         required; uint
+
+	RestartMaxDelaySec    W[uint] `json:"restart_max_delay_sec"`    //         optional; uint  (default: 60)
+	RestartResetSec       W[uint] `json:"restart_reset_sec"`        //         optional; uint  (default: 60)
+	StartLimitBurst       W[uint] `json:"start_limit_burst"`        //         optional; uint  (default: 10)
+	StartLimitIntervalSec W[uint] `json:"start_limit_interval_sec"` //         optional; uint  (default: 600)
 }
 
 type InputReplace struct {
@@ -133,6 +139,27 @@ func (req *ChannelReplace) ToChannel(id int64) (*channel.ZmuxChannel, error) {
 		return nil, errors.New("restart_sec is required")
 	}
 
+	// restart policy
+	// optional; uint (defaults: see channel.SetRestartPolicyDefaults)
+	ch.SetRestartPolicyDefaults()
+	for _, f := range []struct {
+		name string
+		req  W[uint]
+		dst  *uint
+	}{
+		{"restart_max_delay_sec", req.RestartMaxDelaySec, &ch.RestartMaxDelaySec},
+		{"restart_reset_sec", req.RestartResetSec, &ch.RestartResetSec},
+		{"start_limit_burst", req.StartLimitBurst, &ch.StartLimitBurst},
+		{"start_limit_interval_sec", req.StartLimitIntervalSec, &ch.StartLimitIntervalSec},
+	} {
+		if f.req.Set {
+			if f.req.Null {
+				return nil, fmt.Errorf("%s cannot be null", f.name)
+			}
+			*f.dst = f.req.V
+		}
+	}
+
 	return ch, nil
 }
 
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	adminuser "github.com/edirooss/zmux-server/internal/domain/admin-user"
+	"github.com/edirooss/zmux-server/internal/domain/principal"
+	"github.com/edirooss/zmux-server/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminUserHandler struct {
+	authsvc  *service.AuthService
+	adminsvc *service.AdminUserService
+}
+
+func NewAdminUserHandler(authsvc *service.AuthService, adminsvc *service.AdminUserService) *AdminUserHandler {
+	return &AdminUserHandler{authsvc, adminsvc}
+}
+
+func (h *AdminUserHandler) CreateAdminUser(c *gin.Context) {
+	var req adminuser.AdminUserResource
+	if err := bind(c.Request, &req); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err := req.Validate(true); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return
+	}
+
+	view, err := h.adminsvc.Create(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+
+		if errors.Is(err, service.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/api/admin-users/%d", view.ID))
+	c.JSON(http.StatusCreated, view)
+}
+
+func (h *AdminUserHandler) UpdateAdminUser(c *gin.Context) {
+	idStr := c.Param("id")
+	userID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	var req adminuser.AdminUserResource
+	if err := bind(c.Request, &req); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err := req.Validate(false); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return
+	}
+
+	view, err := h.adminsvc.Update(c.Request.Context(), userID, &req)
+	if err != nil {
+		c.Error(err)
+
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		} else if errors.Is(err, service.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+func (h *AdminUserHandler) GetAdminUser(c *gin.Context) {
+	idStr := c.Param("id")
+	userID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	view, err := h.adminsvc.GetOne(userID)
+	if err != nil {
+		c.Error(err)
+
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+func (h *AdminUserHandler) GetAllAdminUsers(c *gin.Context) {
+	views, err := h.adminsvc.GetList()
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, views)
+}
+
+func (h *AdminUserHandler) DeleteAdminUser(c *gin.Context) {
+	idStr := c.Param("id")
+	userID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	if err := h.adminsvc.Delete(c.Request.Context(), userID); err != nil {
+		c.Error(err)
+
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		} else if errors.Is(err, service.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ChangeOwnPassword lets the authenticated admin user change their own password.
+//
+//   - 403 if the principal is not an admin user
+//   - 422 if the new password fails validation
+//   - 401 if the current password does not match
+//   - 409 if the password was changed concurrently (e.g. reset by an admin)
+func (h *AdminUserHandler) ChangeOwnPassword(c *gin.Context) {
+	p := h.authsvc.WhoAmI(c)
+	if p == nil {
+		c.Status(http.StatusUnauthorized)
+		return
+	}
+	if p.Kind != principal.Admin {
+		c.JSON(http.StatusForbidden, gin.H{"message": "only admin users have a password"})
+		return
+	}
+	userID, err := strconv.ParseInt(p.ID, 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	var req adminuser.PasswordChangeResource
+	if err := bind(c.Request, &req); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err := adminuser.ValidatePassword(req.NewPassword); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return
+	}
+
+	if err := h.adminsvc.ChangePassword(c.Request.Context(), userID, req.CurrentPassword, req.NewPassword); err != nil {
+		c.Error(err)
+
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			c.JSON(http.StatusUnauthorized, gin.H{"message": "current password is incorrect"})
+		} else if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		} else if errors.Is(err, service.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
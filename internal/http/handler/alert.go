@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	alertrule "github.com/edirooss/zmux-server/internal/domain/alert-rule"
+	"github.com/edirooss/zmux-server/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AlertHandler struct {
+	alertsvc *service.AlertService
+}
+
+func NewAlertHandler(alertsvc *service.AlertService) *AlertHandler {
+	return &AlertHandler{alertsvc}
+}
+
+// CreateAlertRule adds an alert rule.
+//
+// Status Codes:
+//   - 201 Created: rule created
+//   - 400 Bad Request: malformed JSON
+//   - 422 Unprocessable Entity: validation failed (kind, severity, kind parameters)
+//   - 500 Internal Server Error
+func (h *AlertHandler) CreateAlertRule(c *gin.Context) {
+	var req alertrule.AlertRuleResource
+	if err := bind(c.Request, &req); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return
+	}
+
+	view, err := h.alertsvc.Create(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/api/alert-rules/%d", view.ID))
+	c.JSON(http.StatusCreated, view)
+}
+
+// UpdateAlertRule replaces an alert rule. Alerts already raised by the rule
+// keep their state.
+func (h *AlertHandler) UpdateAlertRule(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	var req alertrule.AlertRuleResource
+	if err := bind(c.Request, &req); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return
+	}
+
+	view, err := h.alertsvc.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		c.Error(err)
+
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+func (h *AlertHandler) GetAlertRule(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	view, err := h.alertsvc.GetOne(id)
+	if err != nil {
+		c.Error(err)
+
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+func (h *AlertHandler) GetAllAlertRules(c *gin.Context) {
+	views, err := h.alertsvc.GetList()
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, views)
+}
+
+func (h *AlertHandler) DeleteAlertRule(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	if err := h.alertsvc.Delete(c.Request.Context(), id); err != nil {
+		c.Error(err)
+
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetAlerts returns the current alerts, most recent first.
+//
+// Behavior:
+//   - ?state= filters by state (pending | firing | resolved).
+//   - Resolved alerts are listed until alerts.resolved_retention elapses.
+//
+// Status Codes:
+//   - 200 OK: JSON array of alerts
+//   - 400 Bad Request: invalid state
+func (h *AlertHandler) GetAlerts(c *gin.Context) {
+	state := alertrule.AlertState(c.Query("state"))
+	switch state {
+	case "", alertrule.AlertPending, alertrule.AlertFiring, alertrule.AlertResolved:
+	default:
+		err := fmt.Errorf("invalid state %q: must be one of pending, firing, resolved", state)
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.alertsvc.GetAlerts(state))
+}
+
+// SilenceAlert suppresses notifications of a single alert for a while.
+//
+// Behavior:
+//   - Body: {"duration_sec": N}, N in 1..2592000 (30 days).
+//   - The silence survives the alert resolving and firing again.
+//   - Rule-wide silences are set with "silenced_until" on the rule.
+//
+// Status Codes:
+//   - 200 OK: the silenced alert
+//   - 400 Bad Request: malformed JSON
+//   - 404 Not Found: unknown alert
+//   - 422 Unprocessable Entity: duration out of range
+func (h *AlertHandler) SilenceAlert(c *gin.Context) {
+	var req struct {
+		DurationSec int64 `json:"duration_sec"`
+	}
+	if err := bind(c.Request, &req); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if req.DurationSec < 1 || req.DurationSec > 30*24*3600 {
+		err := errors.New("duration_sec must be between 1 and 2592000")
+		c.Error(err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return
+	}
+
+	view, err := h.alertsvc.Silence(c.Param("id"), time.Duration(req.DurationSec)*time.Second)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+// UnsilenceAlert lifts the silence of a single alert.
+//
+// Status Codes:
+//   - 200 OK: the alert
+//   - 404 Not Found: unknown alert
+func (h *AlertHandler) UnsilenceAlert(c *gin.Context) {
+	view, err := h.alertsvc.Silence(c.Param("id"), 0)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	mw "github.com/edirooss/zmux-server/internal/http/middleware"
+	"github.com/edirooss/zmux-server/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler serves the audit log.
+//
+// Supported operations:
+//   - GET /audit → query audit records
+type AuditHandler struct {
+	auditsvc *service.AuditService
+}
+
+func NewAuditHandler(auditsvc *service.AuditService) *AuditHandler {
+	return &AuditHandler{auditsvc}
+}
+
+// GetAudit handles GET /audit.
+//
+// Behavior:
+//   - Returns up to ?limit= records (1..1000, default 100), oldest first.
+//   - ?resource= channel | b2b_client | output_destination, optionally with an ID
+//     (e.g. channel:42).
+//   - ?actor= principal ID, optionally with its kind (e.g. admin:1,
+//     b2b_client:7).
+//   - ?since= record ID (records after it) or UTC millis (records at or
+//     after it); without it, the most recent records are returned.
+//
+// Status Codes:
+//   - 200 OK → JSON array of records
+//   - 400 Bad Request → invalid limit or since
+//   - 500 Internal Server Error
+func (h *AuditHandler) GetAudit(c *gin.Context) {
+	q := service.AuditQuery{
+		Resource: c.Query("resource"),
+		Actor:    c.Query("actor"),
+		Since:    c.Query("since"),
+		Limit:    100,
+	}
+	if s := c.Query("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > 1000 {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "limit must be an integer between 1 and 1000"})
+			return
+		}
+		q.Limit = n
+	}
+	if q.Since != "" {
+		if err := service.ParseHistorySince(q.Since); err != nil {
+			c.Error(err)
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+	}
+
+	records, err := h.auditsvc.Get(c.Request.Context(), q)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, records)
+}
+
+// audit records a successful mutation of the calling principal: before and
+// after are the persisted models (nil on create and delete respectively).
+func audit(c *gin.Context, authsvc *service.AuthService, auditsvc *service.AuditService, action, resource string, id int64, before, after any) {
+	rec := service.AuditRecord{
+		RequestID:  mw.GetRequestID(c),
+		ClientIP:   c.ClientIP(),
+		Action:     action,
+		Resource:   resource,
+		ResourceID: id,
+	}
+	if p := authsvc.WhoAmI(c); p != nil {
+		rec.Actor = *p
+	}
+	auditsvc.Record(c.Request.Context(), rec, before, after)
+}
@@ -13,10 +13,13 @@ import (
 
 type B2BClientHandler struct {
 	b2bclntsvc *service.B2BClientService
+	authsvc    *service.AuthService
+	auditsvc   *service.AuditService
+	outdestsvc *service.OutputDestinationService
 }
 
-func NewB2BClientHandler(b2bclntsvc *service.B2BClientService) *B2BClientHandler {
-	return &B2BClientHandler{b2bclntsvc}
+func NewB2BClientHandler(b2bclntsvc *service.B2BClientService, authsvc *service.AuthService, auditsvc *service.AuditService, outdestsvc *service.OutputDestinationService) *B2BClientHandler {
+	return &B2BClientHandler{b2bclntsvc, authsvc, auditsvc, outdestsvc}
 }
 
 func (h *B2BClientHandler) CreateB2BClient(c *gin.Context) {
@@ -26,12 +29,23 @@ func (h *B2BClientHandler) CreateB2BClient(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return
+	}
+	if err := req.ValidateOutputRefs(h.outdestsvc.Exists); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return
+	}
 
 	if view, err := h.b2bclntsvc.Create(c.Request.Context(), &req); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	} else {
+		h.auditB2BClient(c, service.AuditCreate, view.ID, nil)
 		c.Header("Location", fmt.Sprintf("/api/b2b-client/%d", view.ID))
 		c.JSON(http.StatusCreated, view)
 	}
@@ -52,7 +66,18 @@ func (h *B2BClientHandler) UpdateB2BClient(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return
+	}
+	if err := req.ValidateOutputRefs(h.outdestsvc.Exists); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return
+	}
 
+	prev, _ := h.b2bclntsvc.GetModel(b2bClientID) // for the audit record; Update reports a missing client
 	if view, err := h.b2bclntsvc.Update(c.Request.Context(), b2bClientID, &req); err != nil {
 		c.Error(err)
 
@@ -66,6 +91,7 @@ func (h *B2BClientHandler) UpdateB2BClient(c *gin.Context) {
 
 		return
 	} else {
+		h.auditB2BClient(c, service.AuditUpdate, b2bClientID, prev)
 		c.JSON(http.StatusOK, view)
 	}
 
@@ -116,6 +142,7 @@ func (h *B2BClientHandler) DeleteB2BClient(c *gin.Context) {
 		return
 	}
 
+	prev, _ := h.b2bclntsvc.GetModel(b2bClientID)
 	if err := h.b2bclntsvc.Delete(c.Request.Context(), b2bClientID); err != nil {
 		c.Error(err)
 
@@ -127,6 +154,22 @@ func (h *B2BClientHandler) DeleteB2BClient(c *gin.Context) {
 
 		return
 	}
+	h.auditB2BClient(c, service.AuditDelete, b2bClientID, prev)
 
 	c.Status(http.StatusNoContent)
 }
+
+// auditB2BClient records a B2B client mutation: prev is the model before it
+// (nil on create); the model after it is read back (none after delete).
+func (h *B2BClientHandler) auditB2BClient(c *gin.Context, action string, id int64, prev *b2bclient.B2BClientModel) {
+	var before, after any
+	if prev != nil {
+		before = prev
+	}
+	if action != service.AuditDelete {
+		if next, err := h.b2bclntsvc.GetModel(id); err == nil {
+			after = next
+		}
+	}
+	audit(c, h.authsvc, h.auditsvc, action, service.AuditResourceB2BClient, id, before, after)
+}
@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	b2bclient "github.com/edirooss/zmux-server/internal/domain/b2b-client"
+	"github.com/edirooss/zmux-server/internal/domain/principal"
+	"github.com/edirooss/zmux-server/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// B2B client API tokens.
+//
+// Supported operations (admin; :id is the B2B client):
+//   - GET    /b2b-clients/:id/tokens                  → list tokens
+//   - POST   /b2b-clients/:id/tokens                  → create a token
+//   - POST   /b2b-clients/:id/tokens/:token_id/rotate → rotate a token
+//   - DELETE /b2b-clients/:id/tokens/:token_id        → revoke a token
+//
+// The same operations under /me/tokens act on the calling B2B client.
+//
+// Token secrets are returned once, by create and rotate; listings carry
+// metadata only.
+
+// GetTokens handles GET /b2b-clients/:id/tokens.
+//
+// Status Codes:
+//   - 200 OK → JSON array of tokens
+//   - 400 Bad Request → invalid id
+//   - 404 Not Found → unknown B2B client
+//   - 500 Internal Server Error
+func (h *B2BClientHandler) GetTokens(c *gin.Context) {
+	if id, ok := b2bClientIDParam(c); ok {
+		h.listTokens(c, id)
+	}
+}
+
+// CreateToken handles POST /b2b-clients/:id/tokens.
+//
+// Behavior:
+//   - Body: {"name": "...", "scope": "read" | "read_write", "expires_at": <UTC millis> | null}
+//     (scope defaults to read_write; expires_at to never).
+//   - Returns the token including its secret ("token"); it is not retrievable later.
+//   - Expired tokens of the client are dropped.
+//
+// Status Codes:
+//   - 201 Created → JSON token with secret
+//   - 400 Bad Request → invalid id or body
+//   - 404 Not Found → unknown B2B client
+//   - 409 Conflict → token limit reached
+//   - 422 Unprocessable Entity → validation failed
+//   - 500 Internal Server Error
+func (h *B2BClientHandler) CreateToken(c *gin.Context) {
+	if id, ok := b2bClientIDParam(c); ok {
+		h.createToken(c, id)
+	}
+}
+
+// RotateToken handles POST /b2b-clients/:id/tokens/:token_id/rotate.
+//
+// Behavior:
+//   - Body (optional): {"overlap_sec": <0..2592000>} (default 86400).
+//   - Creates a token with the same name, scope and expiry; the old one stays
+//     valid for the overlap window (0 revokes it immediately).
+//   - Returns the new token including its secret.
+//
+// Status Codes:
+//   - 201 Created → JSON token with secret
+//   - 400 Bad Request → invalid id or body
+//   - 404 Not Found → unknown B2B client or token
+//   - 422 Unprocessable Entity → invalid overlap_sec
+//   - 500 Internal Server Error
+func (h *B2BClientHandler) RotateToken(c *gin.Context) {
+	if id, ok := b2bClientIDParam(c); ok {
+		h.rotateToken(c, id)
+	}
+}
+
+// RevokeToken handles DELETE /b2b-clients/:id/tokens/:token_id.
+//
+// Status Codes:
+//   - 204 No Content → revoked
+//   - 400 Bad Request → invalid id
+//   - 404 Not Found → unknown B2B client or token
+//   - 500 Internal Server Error
+func (h *B2BClientHandler) RevokeToken(c *gin.Context) {
+	if id, ok := b2bClientIDParam(c); ok {
+		h.revokeToken(c, id)
+	}
+}
+
+// GetOwnTokens handles GET /me/tokens (see GetTokens; B2B clients only).
+func (h *B2BClientHandler) GetOwnTokens(c *gin.Context) {
+	if id, ok := h.ownB2BClientID(c); ok {
+		h.listTokens(c, id)
+	}
+}
+
+// CreateOwnToken handles POST /me/tokens (see CreateToken; B2B clients with
+// a read_write token only).
+func (h *B2BClientHandler) CreateOwnToken(c *gin.Context) {
+	if id, ok := h.ownB2BClientID(c); ok {
+		h.createToken(c, id)
+	}
+}
+
+// RotateOwnToken handles POST /me/tokens/:token_id/rotate (see RotateToken;
+// B2B clients with a read_write token only).
+func (h *B2BClientHandler) RotateOwnToken(c *gin.Context) {
+	if id, ok := h.ownB2BClientID(c); ok {
+		h.rotateToken(c, id)
+	}
+}
+
+// RevokeOwnToken handles DELETE /me/tokens/:token_id (see RevokeToken; B2B
+// clients with a read_write token only). Revoking the calling token is
+// allowed.
+func (h *B2BClientHandler) RevokeOwnToken(c *gin.Context) {
+	if id, ok := h.ownB2BClientID(c); ok {
+		h.revokeToken(c, id)
+	}
+}
+
+func (h *B2BClientHandler) listTokens(c *gin.Context, b2bClientID int64) {
+	tokens, err := h.b2bclntsvc.ListTokens(b2bClientID)
+	if err != nil {
+		writeTokenError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *B2BClientHandler) createToken(c *gin.Context, b2bClientID int64) {
+	var req b2bclient.TokenResource
+	if err := bind(c.Request, &req); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err := req.Validate(time.Now()); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return
+	}
+
+	prev, _ := h.b2bclntsvc.GetModel(b2bClientID)
+	tok, err := h.b2bclntsvc.CreateToken(c.Request.Context(), b2bClientID, &req)
+	if err != nil {
+		writeTokenError(c, err)
+		return
+	}
+	h.auditB2BClient(c, service.AuditUpdate, b2bClientID, prev)
+
+	c.JSON(http.StatusCreated, tok)
+}
+
+func (h *B2BClientHandler) rotateToken(c *gin.Context, b2bClientID int64) {
+	var req b2bclient.TokenRotateResource
+	if c.Request.ContentLength != 0 {
+		if err := bind(c.Request, &req); err != nil {
+			c.Error(err)
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+	}
+	overlap, err := req.Overlap()
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return
+	}
+
+	prev, _ := h.b2bclntsvc.GetModel(b2bClientID)
+	tok, err := h.b2bclntsvc.RotateToken(c.Request.Context(), b2bClientID, c.Param("token_id"), overlap)
+	if err != nil {
+		writeTokenError(c, err)
+		return
+	}
+	h.auditB2BClient(c, service.AuditUpdate, b2bClientID, prev)
+
+	c.JSON(http.StatusCreated, tok)
+}
+
+func (h *B2BClientHandler) revokeToken(c *gin.Context, b2bClientID int64) {
+	prev, _ := h.b2bclntsvc.GetModel(b2bClientID)
+	if err := h.b2bclntsvc.RevokeToken(c.Request.Context(), b2bClientID, c.Param("token_id")); err != nil {
+		writeTokenError(c, err)
+		return
+	}
+	h.auditB2BClient(c, service.AuditUpdate, b2bClientID, prev)
+
+	c.Status(http.StatusNoContent)
+}
+
+// ownB2BClientID returns the calling B2B client's ID, or writes 401/403.
+func (h *B2BClientHandler) ownB2BClientID(c *gin.Context) (int64, bool) {
+	p := h.authsvc.WhoAmI(c)
+	if p == nil {
+		c.Status(http.StatusUnauthorized)
+		return 0, false
+	}
+	if p.Kind != principal.B2BClient {
+		c.JSON(http.StatusForbidden, gin.H{"message": "only B2B clients have API tokens; use /api/b2b-clients/:id/tokens"})
+		return 0, false
+	}
+	id, err := strconv.ParseInt(p.ID, 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return 0, false
+	}
+	return id, true
+}
+
+// b2bClientIDParam parses :id, or writes 400.
+func b2bClientIDParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return 0, false
+	}
+	return id, true
+}
+
+func writeTokenError(c *gin.Context, err error) {
+	c.Error(err)
+
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+	case errors.Is(err, service.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
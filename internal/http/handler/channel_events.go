@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/edirooss/zmux-server/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ChannelEventsHandler streams channel events over Server-Sent Events.
+//
+// Supported operations:
+//   - GET /channels/events → status transitions, config changes and process
+//     lifecycle events of the channels visible to the principal
+type ChannelEventsHandler struct {
+	log     *zap.Logger
+	authsvc *service.AuthService
+	hub     *service.ChannelEventHub
+}
+
+// NewChannelEventsHandler constructs a ChannelEventsHandler instance.
+func NewChannelEventsHandler(log *zap.Logger, authsvc *service.AuthService, hub *service.ChannelEventHub) *ChannelEventsHandler {
+	return &ChannelEventsHandler{
+		log:     log.Named("channel-events"),
+		authsvc: authsvc,
+		hub:     hub,
+	}
+}
+
+// Stream handles GET /channels/events.
+//
+// Behavior:
+//   - Events are named "status", "config" or "process"; data is JSON.
+//   - B2B clients only receive events of their own channels.
+//   - Resume: missed events are replayed after the `Last-Event-ID` header
+//     (or `?last_event_id=` for clients that cannot set headers). When the
+//     gap cannot be filled (server restarted, backlog exceeded) a single
+//     "resync" event is sent first; clients should reload their snapshot.
+//   - A comment heartbeat is sent every 15s while idle.
+//   - The stream ends when the client disconnects, falls too far behind
+//     (reconnect with Last-Event-ID), or the server shuts down.
+//
+// Status Codes:
+//   - 200 OK → text/event-stream
+func (h *ChannelEventsHandler) Stream(c *gin.Context) {
+	p := h.authsvc.WhoAmI(c) // extract principal (already set by other middleware)
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	sub, backlog, resync := h.hub.Subscribe(p, lastEventID)
+	defer h.hub.Unsubscribe(sub)
+
+	startSSE(c)
+
+	if resync {
+		if err := writeSSE(c, "", "resync", []byte("{}")); err != nil {
+			return
+		}
+	}
+	for _, ev := range backlog {
+		if err := h.write(c, ev); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+
+		case ev, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			if err := h.write(c, ev); err != nil {
+				return
+			}
+
+		case <-heartbeat.C:
+			if err := writeSSEHeartbeat(c); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (h *ChannelEventsHandler) write(c *gin.Context, ev service.ChannelEvent) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		h.log.Error("json marshal failed", zap.String("id", ev.ID), zap.Error(err))
+		return nil // skip the event, keep the stream
+	}
+	return writeSSE(c, ev.ID, string(ev.Type), data)
+}
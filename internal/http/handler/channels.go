@@ -11,8 +11,10 @@ import (
 	"strings"
 	"time"
 
+	b2bclient "github.com/edirooss/zmux-server/internal/domain/b2b-client"
 	"github.com/edirooss/zmux-server/internal/domain/channel"
 	"github.com/edirooss/zmux-server/internal/domain/channel/views"
+	outputdestination "github.com/edirooss/zmux-server/internal/domain/output-destination"
 	"github.com/edirooss/zmux-server/internal/domain/principal"
 	"github.com/edirooss/zmux-server/internal/http/dto"
 	"github.com/edirooss/zmux-server/internal/service"
@@ -33,16 +35,20 @@ import (
 // Notes:
 //   - Standard REST semantics (RFC 9110, RFC 5789).
 type ChannelsHandler struct {
-	log        *zap.Logger
-	authsvc    *service.AuthService
-	svc        *service.ChannelService
-	b2bsvc     *service.B2BClientService
-	summarySvc *service.SummaryService
-	repo       *service.RemuxRepository
+	log            *zap.Logger
+	authsvc        *service.AuthService
+	svc            *service.ChannelService
+	b2bsvc         *service.B2BClientService
+	outdests       *service.OutputDestinationService
+	summarySvc     *service.SummaryService
+	repo           *service.RemuxRepository
+	history        *service.ChannelHistory
+	metricsHistory *service.MetricsHistoryService
+	audit          *service.AuditService
 }
 
 // NewChannelsHandler constructs a ChannelsHandler instance.
-func NewChannelsHandler(log *zap.Logger, authsvc *service.AuthService, chansvc *service.ChannelService, b2bsvc *service.B2BClientService, repo *service.RemuxRepository) (*ChannelsHandler, error) {
+func NewChannelsHandler(log *zap.Logger, authsvc *service.AuthService, chansvc *service.ChannelService, b2bsvc *service.B2BClientService, outdests *service.OutputDestinationService, repo *service.RemuxRepository, history *service.ChannelHistory, metricsHistory *service.MetricsHistoryService, audit *service.AuditService) (*ChannelsHandler, error) {
 	// Service for generating channel summaries
 	summarySvc := service.NewSummaryService(
 		log,
@@ -55,12 +61,16 @@ func NewChannelsHandler(log *zap.Logger, authsvc *service.AuthService, chansvc *
 	)
 
 	return &ChannelsHandler{
-		log:        log.Named("channels"),
-		authsvc:    authsvc,
-		svc:        chansvc,
-		b2bsvc:     b2bsvc,
-		summarySvc: summarySvc,
-		repo:       repo,
+		log:            log.Named("channels"),
+		authsvc:        authsvc,
+		svc:            chansvc,
+		b2bsvc:         b2bsvc,
+		outdests:       outdests,
+		summarySvc:     summarySvc,
+		repo:           repo,
+		history:        history,
+		metricsHistory: metricsHistory,
+		audit:          audit,
 	}, nil
 }
 
@@ -198,7 +208,7 @@ func (h *ChannelsHandler) getChannelListByPrincipal(ctx context.Context, p *prin
 		}
 		b2bClientView := make([]*views.B2BClientZmuxChannel, len(chs))
 		for i := range chs {
-			b2bClientView[i] = chs[i].B2BClientView()
+			b2bClientView[i] = chs[i].B2BClientView(h.outdests.B2BVisible)
 		}
 		return b2bClientView, len(b2bClientView), nil
 	}
@@ -210,15 +220,26 @@ func (h *ChannelsHandler) getChannelListByPrincipal(ctx context.Context, p *prin
 //
 // Behavior:
 //   - Validates request body.
-//   - Creates a new channel with defaults applied.
+//   - Creates a new channel with defaults applied; output defaults come from
+//     the output destination of the ref, if any.
+//   - B2B clients create their own channels: the body holds the B2B client
+//     channel fields only (see dto.B2BChannelCreate), the rest comes from the
+//     client's channel template, and the client's quotas apply.
 //   - Responds with resource location in `Location` header.
 //
 // Status Codes:
-//   - 201 Created → JSON of created channel
+//   - 201 Created → JSON of created channel (per principal view)
 //   - 400 Bad Request → Invalid JSON or schema
-//   - 422 Unprocessable Entity → Validation failed
+//   - 403 Forbidden → B2B client without a channel template, or setting an output it may not toggle
+//   - 409 Conflict → B2B client quota exceeded
+//   - 422 Unprocessable Entity → Validation failed, B2B client policy violated or output localaddr not allowed
 //   - 500 Internal Server Error
 func (h *ChannelsHandler) CreateChannel(c *gin.Context) {
+	if p := h.authsvc.WhoAmI(c); p != nil && p.Kind == principal.B2BClient {
+		h.createOwnChannel(c, p)
+		return
+	}
+
 	var req dto.ChannelCreate
 	if err := bind(c.Request, &req); err != nil {
 		c.Error(err)
@@ -226,27 +247,90 @@ func (h *ChannelsHandler) CreateChannel(c *gin.Context) {
 		return
 	}
 
-	ch, err := req.ToChannel()
+	ch, err := req.ToChannel(h.outdests.Lookup)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	if !h.createChannel(c, ch) {
+		return
+	}
+	c.JSON(http.StatusCreated, ch.AdminView())
+}
+
+// createOwnChannel is CreateChannel for B2B clients.
+func (h *ChannelsHandler) createOwnChannel(c *gin.Context, p *principal.Principal) {
+	b2bclntID, err := strconv.ParseInt(p.ID, 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	b2bclnt, err := h.b2bsvc.GetOne(b2bclntID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	if b2bclnt.ChannelTemplate == nil {
+		c.JSON(http.StatusForbidden, gin.H{"message": "channel creation is not enabled for this B2B client"})
+		return
+	}
+
+	var req dto.B2BChannelCreate
+	if err := bind(c.Request, &req); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	ch, err := req.ToChannel(b2bclntID, b2bclnt.ChannelTemplate, h.outdests.B2BToggleable)
 	if err != nil {
 		c.Error(err)
+		if errors.Is(err, dto.ErrUnauthorized) {
+			c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
 
+	if !h.createChannel(c, ch) {
+		return
+	}
+	c.JSON(http.StatusCreated, ch.B2BClientView(h.outdests.B2BVisible))
+}
+
+// createChannel validates and persists a new channel, or writes the error
+// response. On success it sets the `Location` header; the caller writes the
+// body.
+func (h *ChannelsHandler) createChannel(c *gin.Context, ch *channel.ZmuxChannel) bool {
 	if err := ch.Validate(); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
-		return
+		return false
 	}
 
 	if err := h.svc.Create(c.Request.Context(), ch); err != nil {
 		c.Error(err)
+		var qee *service.QuotaExceededError
+		if errors.As(err, &qee) {
+			c.JSON(http.StatusConflict, gin.H{"message": qee.Error()})
+			return false
+		}
+		if isUnprocessable(err) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+			return false
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-		return
+		return false
 	}
+	h.auditChannel(c, service.AuditCreate, ch.ID, nil, ch)
 
 	c.Header("Location", fmt.Sprintf("/api/channels/%d", ch.ID))
-	c.JSON(http.StatusCreated, ch)
+	return true
 }
 
 // GetChannel handles GET /channels/{id}.
@@ -278,9 +362,46 @@ func (h *ChannelsHandler) GetChannel(c *gin.Context) {
 	c.JSON(http.StatusOK, ch)
 }
 
+// GetChannelLogs handles GET /channels/{id}/logs.
+//
+// Behavior:
+//   - Without query params: the in-memory buffer (newest → oldest).
+//   - ?since=&until= (RFC3339 or unix millis) selects a time window; windows
+//     older than the in-memory buffer are read from the on-disk logs (when
+//     configured). until defaults to now, since to the beginning of time.
+//   - ?limit=N caps a windowed read (default 1000, max 10000).
+//
+// Status Codes:
+//   - 200 OK → JSON array of log entries
+//   - 400 Bad Request → Invalid ID or query param
+//   - 404 Not Found → Channel not found
+//   - 500 Internal Server Error
 func (h *ChannelsHandler) GetChannelLogs(c *gin.Context) {
 	id, _ := strconv.ParseInt(c.Param("id"), 10, 64) // extract :id (already validated by middleware)
-	logs, _ := h.svc.GetLogs(context.TODO(), id)     // newest → oldest, each entry already JSON-encoded
+
+	var logs []string // newest → oldest, each entry already JSON-encoded
+	var err error
+	if c.Query("since") == "" && c.Query("until") == "" {
+		logs, err = h.svc.GetLogs(context.TODO(), id)
+	} else {
+		var since, until time.Time
+		var limit int
+		if since, until, limit, err = parseLogRange(c); err != nil {
+			c.Error(err)
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+		logs, err = h.svc.GetLogsRange(context.TODO(), id, since, until, limit)
+	}
+	if err != nil {
+		c.Error(err)
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": service.ErrNotFound.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
 
 	c.Header("Content-Type", "application/json")
 	c.Status(http.StatusOK)
@@ -296,6 +417,411 @@ func (h *ChannelsHandler) GetChannelLogs(c *gin.Context) {
 	c.Writer.Write([]byte("]"))
 }
 
+// GetChannelRuntime handles GET /channels/{id}/runtime.
+//
+// Behavior:
+//   - Returns the process supervisor's view of the channel's remux process:
+//     state, OS pid, start time, last exit, restart count and next launch.
+//   - Disabled channels report state "disabled".
+//
+// Status Codes:
+//   - 200 OK → JSON of runtime state
+//   - 400 Bad Request → Invalid ID format
+//   - 404 Not Found → Channel not found
+//   - 500 Internal Server Error
+func (h *ChannelsHandler) GetChannelRuntime(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64) // extract :id (already validated by middleware)
+
+	rt, err := h.svc.GetRuntime(id)
+	if err != nil {
+		c.Error(err)
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": service.ErrNotFound.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rt)
+}
+
+// GetChannelEvents handles GET /channels/{id}/events.
+//
+// Behavior:
+//   - Returns the recorded history of the channel (status transitions,
+//     process starts/exits, config changes), oldest first, as a JSON array.
+//   - Without ?since= the most recent events are returned. ?since= takes an
+//     event id (events after it; use the last id to read on) or UTC millis
+//     (events at or after it).
+//   - ?limit= (1..1000, default 100).
+//   - B2B clients see user-facing events only (status and config).
+//   - The history is bounded (events.history_max_len per channel).
+//
+// Status Codes:
+//   - 200 OK → JSON array of events
+//   - 400 Bad Request → Invalid ID format, since or limit
+//   - 403 Forbidden → Channel not owned by the B2B client
+//   - 404 Not Found → Channel not found
+//   - 500 Internal Server Error
+func (h *ChannelsHandler) GetChannelEvents(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64) // extract :id (already validated by middleware)
+
+	since := c.Query("since")
+	if since != "" {
+		if err := service.ParseHistorySince(since); err != nil {
+			c.Error(err)
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+	}
+	limit := 100
+	if v := c.Query("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > 1000 {
+			err = fmt.Errorf("invalid limit %q: must be an integer in 1..1000", v)
+			c.Error(err)
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+	}
+
+	if _, err := h.svc.GetOne(id); err != nil {
+		c.Error(err)
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": service.ErrNotFound.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	p := h.authsvc.WhoAmI(c) // extract principal (already set by other middleware)
+	events, err := h.history.Get(c.Request.Context(), id, since, limit, p.Kind == principal.B2BClient)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// GetChannelMetricsHistory handles GET /channels/{id}/metrics/history.
+//
+// Behavior:
+//   - Returns sampled remux metrics (bitrate, packets, continuity errors) of
+//     the channel between ?from= and ?to= (UTC millis; defaults: the last
+//     10 minutes), oldest first.
+//   - ?step= sets the bucket width (e.g. "1s", "5m", "1h", or seconds).
+//     History is kept at 1s for 10 minutes, 1m for 24 hours and 1h for 30
+//     days; the step is rounded up to the resolution available for ?from=.
+//   - Only periods during which the channel was online have points.
+//
+// Status Codes:
+//   - 200 OK → JSON history
+//   - 400 Bad Request → Invalid ID format, from, to or step
+//   - 404 Not Found → Channel not found
+//   - 500 Internal Server Error
+func (h *ChannelsHandler) GetChannelMetricsHistory(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64) // extract :id (already validated by middleware)
+
+	to := time.Now().UnixMilli()
+	if v := c.Query("to"); v != "" {
+		var err error
+		if to, err = strconv.ParseInt(v, 10, 64); err != nil || to < 0 {
+			err = fmt.Errorf("invalid to %q: must be UTC millis", v)
+			c.Error(err)
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+	}
+	from := to - (10 * time.Minute).Milliseconds()
+	if v := c.Query("from"); v != "" {
+		var err error
+		if from, err = strconv.ParseInt(v, 10, 64); err != nil || from < 0 || from > to {
+			err = fmt.Errorf("invalid from %q: must be UTC millis not after to", v)
+			c.Error(err)
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+	}
+	var step time.Duration
+	if v := c.Query("step"); v != "" {
+		var err error
+		if step, err = service.ParseMetricsStep(v); err != nil {
+			c.Error(err)
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+	}
+
+	if _, err := h.svc.GetOne(id); err != nil {
+		c.Error(err)
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": service.ErrNotFound.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	hist, err := h.metricsHistory.Get(c.Request.Context(), id, from, to, step)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, hist)
+}
+
+// RestartChannel handles POST /channels/{id}/restart.
+//
+// Behavior:
+//   - Gracefully restarts the channel's remux process (SIGTERM → grace →
+//     SIGKILL, then relaunch); a channel in backoff, failed or stopped is
+//     launched immediately. The persisted channel is not touched.
+//   - B2B clients may restart their own channels.
+//
+// Status Codes:
+//   - 204 No Content → Restart initiated
+//   - 400 Bad Request → Invalid ID format
+//   - 403 Forbidden → Channel not owned by the B2B client
+//   - 404 Not Found → Channel not found
+//   - 409 Conflict → Channel is disabled (no process)
+//   - 500 Internal Server Error
+func (h *ChannelsHandler) RestartChannel(c *gin.Context) {
+	h.controlChannel(c, service.ChannelActionRestart)
+}
+
+// StopChannel handles POST /channels/{id}/stop.
+//
+// Behavior:
+//   - Stops the channel's remux process and keeps it stopped until
+//     POST /channels/{id}/start (or restart). The persisted channel is not
+//     touched: the channel stays enabled, counts against quotas and runs
+//     again after a server restart or a change of its remux command line.
+//
+// Status Codes:
+//   - 204 No Content → Stop initiated
+//   - 400 Bad Request → Invalid ID format
+//   - 404 Not Found → Channel not found
+//   - 409 Conflict → Channel is disabled (no process)
+//   - 500 Internal Server Error
+func (h *ChannelsHandler) StopChannel(c *gin.Context) {
+	h.controlChannel(c, service.ChannelActionStop)
+}
+
+// StartChannel handles POST /channels/{id}/start.
+//
+// Behavior:
+//   - Launches a channel stopped via POST /channels/{id}/stop (or parked by
+//     the crash-loop breaker). A running channel is left alone.
+//
+// Status Codes:
+//   - 204 No Content → Start initiated (or already running)
+//   - 400 Bad Request → Invalid ID format
+//   - 404 Not Found → Channel not found
+//   - 409 Conflict → Channel is disabled (no process)
+//   - 500 Internal Server Error
+func (h *ChannelsHandler) StartChannel(c *gin.Context) {
+	h.controlChannel(c, service.ChannelActionStart)
+}
+
+// controlChannel applies a runtime action to the channel of :id.
+func (h *ChannelsHandler) controlChannel(c *gin.Context, action service.ChannelAction) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64) // extract :id (already validated by middleware)
+
+	if err := h.svc.Control(id, action); err != nil {
+		c.Error(err)
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": service.ErrNotFound.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ResetFailedChannel handles POST /channels/{id}/reset-failed.
+//
+// Behavior:
+//   - Clears the crash-loop breaker of a channel in the "failed" runtime
+//     state and relaunches it immediately; the restart policy starts over.
+//
+// Status Codes:
+//   - 204 No Content → Reset
+//   - 400 Bad Request → Invalid ID format
+//   - 404 Not Found → Channel not found
+//   - 409 Conflict → Channel is not in the failed state
+//   - 500 Internal Server Error
+func (h *ChannelsHandler) ResetFailedChannel(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64) // extract :id (already validated by middleware)
+
+	if err := h.svc.ResetFailed(id); err != nil {
+		c.Error(err)
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": service.ErrNotFound.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseLogRange extracts ?since=&until=&limit= for GetChannelLogs.
+func parseLogRange(c *gin.Context) (since, until time.Time, limit int, err error) {
+	until = time.Now()
+	if v := c.Query("since"); v != "" {
+		if since, err = parseLogTime(v); err != nil {
+			return since, until, 0, fmt.Errorf("invalid since %q", v)
+		}
+	}
+	if v := c.Query("until"); v != "" {
+		if until, err = parseLogTime(v); err != nil {
+			return since, until, 0, fmt.Errorf("invalid until %q", v)
+		}
+	}
+	if until.Before(since) {
+		return since, until, 0, errors.New("until must not be before since")
+	}
+
+	limit = 1000
+	if v := c.Query("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit <= 0 || limit > 10000 {
+			return since, until, 0, fmt.Errorf("invalid limit %q (1..10000)", v)
+		}
+	}
+	return since, until, limit, nil
+}
+
+// parseLogTime accepts RFC3339 or unix milliseconds.
+func parseLogTime(v string) (time.Time, error) {
+	if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+// StreamChannelLogs handles GET /channels/{id}/logs/stream.
+//
+// Behavior:
+//   - Server-Sent Events: first the buffered backlog (oldest → newest), then
+//     each new line as the remux process writes it; one "log" event per line.
+//   - Filters (applied to backlog and live lines):
+//     ?level=warn    → minimum severity (trace|debug|info|warn|error|fatal)
+//     ?contains=text → case-sensitive substring
+//     ?tail=N        → at most the N newest backlog lines (default: all buffered)
+//   - Lines dropped because the client reads too slowly are reported as a
+//     "dropped" event with their count; the stream itself keeps going.
+//   - When the channel is deleted an "eof" event is sent and the stream ends.
+//
+// Status Codes:
+//   - 200 OK → text/event-stream
+//   - 400 Bad Request → Invalid ID or filter
+//   - 404 Not Found → Channel not found
+func (h *ChannelsHandler) StreamChannelLogs(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64) // extract :id (already validated by middleware)
+
+	filter, err := service.NewLogFilter(c.Query("level"), c.Query("contains"))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	tail := 0
+	if v := c.Query("tail"); v != "" {
+		if tail, err = strconv.Atoi(v); err != nil || tail < 0 {
+			err = fmt.Errorf("invalid tail %q", v)
+			c.Error(err)
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+	}
+
+	sub, backlog, unsubscribe, err := h.svc.SubscribeLogs(id, 256)
+	if err != nil {
+		c.Error(err)
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": service.ErrNotFound.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer unsubscribe()
+
+	startSSE(c)
+
+	matched := make([]string, 0, len(backlog))
+	for _, line := range backlog {
+		if filter.Match(line) {
+			matched = append(matched, line)
+		}
+	}
+	if tail > 0 && len(matched) > tail {
+		matched = matched[len(matched)-tail:]
+	}
+	for _, line := range matched {
+		if err := writeSSE(c, "", "log", []byte(line)); err != nil {
+			return
+		}
+	}
+
+	reportDropped := func() error {
+		if n := sub.Dropped(); n > 0 {
+			return writeSSE(c, "", "dropped", []byte(fmt.Sprintf(`{"count":%d}`, n)))
+		}
+		return nil
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+
+		case line, ok := <-sub.Lines():
+			if !ok {
+				_ = writeSSE(c, "", "eof", []byte(`{"reason":"channel deleted"}`))
+				return
+			}
+			if err := reportDropped(); err != nil {
+				return
+			}
+			if !filter.Match(line) {
+				continue
+			}
+			if err := writeSSE(c, "", "log", []byte(line)); err != nil {
+				return
+			}
+
+		case <-heartbeat.C:
+			if err := reportDropped(); err != nil {
+				return
+			}
+			if err := writeSSEHeartbeat(c); err != nil {
+				return
+			}
+		}
+	}
+}
+
 func (h *ChannelsHandler) getChannelByPrincipal(p *principal.Principal, id int64) (interface{}, error) {
 	if p == nil {
 		return nil, fmt.Errorf("nil principal")
@@ -312,7 +838,7 @@ func (h *ChannelsHandler) getChannelByPrincipal(p *principal.Principal, id int64
 		return ch.AdminView(), nil
 
 	case principal.B2BClient:
-		return ch.B2BClientView(), nil
+		return ch.B2BClientView(h.outdests.B2BVisible), nil
 	}
 
 	return nil, fmt.Errorf("unsupported principal")
@@ -327,8 +853,9 @@ func (h *ChannelsHandler) getChannelByPrincipal(p *principal.Principal, id int64
 // Status Codes:
 //   - 204 No Content → Success
 //   - 400 Bad Request → Invalid ID or payload
+//   - 403 Forbidden → Field not permitted for the principal
 //   - 404 Not Found → Channel not found
-//   - 422 Unprocessable Entity → Validation failed
+//   - 422 Unprocessable Entity → Validation failed, B2B client policy violated or output localaddr not allowed
 //   - 500 Internal Server Error
 func (h *ChannelsHandler) ModifyChannel(c *gin.Context) {
 	p := h.authsvc.WhoAmI(c) // extract principal (already set by other middleware)
@@ -354,7 +881,7 @@ func (h *ChannelsHandler) ModifyChannel(c *gin.Context) {
 	}
 	newCh := ch.DeepClone()
 
-	code, err := h.patchAndUpdate(c.Request.Context(), &req, newCh, p.Kind)
+	code, err := h.patchAndUpdate(c.Request.Context(), &req, newCh, p.Permissions())
 	if err != nil {
 		c.Error(err)
 
@@ -369,13 +896,27 @@ func (h *ChannelsHandler) ModifyChannel(c *gin.Context) {
 		c.JSON(code, gin.H{"message": err.Error()})
 		return
 	}
+	h.auditChannel(c, service.AuditUpdate, id, ch, newCh)
 
 	c.Status(code)
 }
 
-func (h *ChannelsHandler) patchAndUpdate(ctx context.Context, req *dto.ChannelModify, ch *channel.ZmuxChannel, pKind principal.PrincipalKind) (int, error) {
+func (h *ChannelsHandler) patchAndUpdate(ctx context.Context, req *dto.ChannelModify, ch *channel.ZmuxChannel, perms principal.Permissions) (int, error) {
+	var policy *b2bclient.Policy
+	if ch.B2BClientID != nil {
+		if b2bclnt, err := h.b2bsvc.GetOne(*ch.B2BClientID); err == nil {
+			policy = &b2bclnt.Policy
+		}
+	}
+
 	// Apply patch
-	if err := req.MergePatch(ch, pKind); err != nil {
+	if err := req.MergePatch(ch, perms, h.outdests.B2BToggleable, policy); err != nil {
+		if errors.Is(err, dto.ErrUnauthorized) {
+			return http.StatusForbidden, err
+		}
+		if errors.Is(err, b2bclient.ErrPolicyViolation) {
+			return http.StatusUnprocessableEntity, err
+		}
 		return http.StatusBadRequest, err
 	}
 
@@ -389,12 +930,21 @@ func (h *ChannelsHandler) patchAndUpdate(ctx context.Context, req *dto.ChannelMo
 		if errors.Is(err, service.ErrNotFound) {
 			return http.StatusNotFound, err
 		}
+		if isUnprocessable(err) {
+			return http.StatusUnprocessableEntity, err
+		}
 		return http.StatusInternalServerError, err
 	}
 
 	return http.StatusNoContent, nil
 }
 
+// isUnprocessable reports whether a channel write was rejected by the owner's
+// policy or the output destination catalog (422).
+func isUnprocessable(err error) bool {
+	return errors.Is(err, b2bclient.ErrPolicyViolation) || errors.Is(err, outputdestination.ErrLocaladdrNotAllowed)
+}
+
 // ReplaceChannel handles PUT /channels/{id}.
 //
 // Behavior:
@@ -404,13 +954,13 @@ func (h *ChannelsHandler) patchAndUpdate(ctx context.Context, req *dto.ChannelMo
 //   - 200 OK → JSON of updated channel
 //   - 400 Bad Request → Invalid ID or payload
 //   - 404 Not Found → Channel not found
-//   - 422 Unprocessable Entity → Validation failed
+//   - 422 Unprocessable Entity → Validation failed, B2B client policy violated or output localaddr not allowed
 //   - 500 Internal Server Error
 func (h *ChannelsHandler) ReplaceChannel(c *gin.Context) {
 	id, _ := strconv.ParseInt(c.Param("id"), 10, 64) // extract :id (already validated by middleware)
 
-	exists := h.svc.Exists(id)
-	if !exists {
+	prev, err := h.svc.GetOne(id)
+	if err != nil {
 		c.Status(http.StatusNotFound)
 		return
 	}
@@ -442,26 +992,35 @@ func (h *ChannelsHandler) ReplaceChannel(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"message": service.ErrNotFound.Error()})
 			return
 		}
+		if isUnprocessable(err) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
+	h.auditChannel(c, service.AuditUpdate, id, prev, ch)
 
-	c.JSON(http.StatusOK, ch)
+	c.JSON(http.StatusOK, ch.AdminView())
 }
 
 // DeleteChannel handles DELETE /channels/{id}.
 //
 // Behavior:
 //   - Removes a channel by ID.
+//   - B2B clients may delete their own channels only (enforced by route
+//     middleware).
 //
 // Status Codes:
 //   - 200 OK → JSON { "id": deletedID }
 //   - 400 Bad Request → Invalid ID
+//   - 403 Forbidden → Channel not owned by the B2B client
 //   - 404 Not Found → Channel not found
 //   - 500 Internal Server Error
 func (h *ChannelsHandler) DeleteChannel(c *gin.Context) {
 	id, _ := strconv.ParseInt(c.Param("id"), 10, 64) // extract :id (already validated by middleware)
 
+	prev, _ := h.svc.GetOne(id) // for the audit record; Delete reports a missing channel
 	if err := h.svc.Delete(c.Request.Context(), id); err != nil {
 		c.Error(err)
 		if errors.Is(err, service.ErrNotFound) {
@@ -472,6 +1031,8 @@ func (h *ChannelsHandler) DeleteChannel(c *gin.Context) {
 		return
 	}
 
+	h.auditChannel(c, service.AuditDelete, id, prev, nil)
+
 	// RA-friendly response
 	c.JSON(http.StatusOK, gin.H{"id": id})
 }
@@ -496,6 +1057,7 @@ func (h *ChannelsHandler) DeleteChannels(c *gin.Context) {
 
 	// requestedIDs appears to be a set (map[string]struct{}). If it's a slice, change `range` accordingly.
 	for _, id := range requestedIDs {
+		prev, _ := h.svc.GetOne(id)
 		if err := h.svc.Delete(c.Request.Context(), id); err != nil {
 			c.Error(err)
 
@@ -515,6 +1077,7 @@ func (h *ChannelsHandler) DeleteChannels(c *gin.Context) {
 			failed = append(failed, id)
 			continue
 		}
+		h.auditChannel(c, service.AuditDelete, id, prev, nil)
 
 		results = append(results, itemResult{
 			ID:     id,
@@ -588,7 +1151,7 @@ func (h *ChannelsHandler) ModifyChannels(c *gin.Context) {
 			continue
 		}
 		newCh := ch.DeepClone()
-		code, err := h.patchAndUpdate(c.Request.Context(), &req, newCh, p.Kind)
+		code, err := h.patchAndUpdate(c.Request.Context(), &req, newCh, p.Permissions())
 		if err != nil {
 			c.Error(err)
 			status := code
@@ -601,6 +1164,7 @@ func (h *ChannelsHandler) ModifyChannels(c *gin.Context) {
 			failed = append(failed, id)
 			continue
 		}
+		h.auditChannel(c, service.AuditUpdate, id, ch, newCh)
 
 		results = append(results, itemResult{
 			ID:     id,
@@ -634,6 +1198,18 @@ func (h *ChannelsHandler) ModifyChannels(c *gin.Context) {
 //
 // ----- Helpers -----
 
+// auditChannel records a channel mutation; before/after are nil on
+// create/delete.
+func (h *ChannelsHandler) auditChannel(c *gin.Context, action string, id int64, before, after *channel.ZmuxChannel) {
+	model := func(ch *channel.ZmuxChannel) any {
+		if ch == nil {
+			return nil
+		}
+		return ch.Model()
+	}
+	audit(c, h.authsvc, h.audit, action, service.AuditResourceChannel, id, model(before), model(after))
+}
+
 func bind(req *http.Request, obj any) error {
 	if req == nil || req.Body == nil {
 		return errors.New("invalid request")
@@ -652,6 +1228,17 @@ func decodeJSON(r io.Reader, obj any) error {
 }
 
 // ------ Summary -----
+
+// Summary handles GET /channels/summary.
+//
+// Behavior:
+//   - Returns every channel with its remux status, typed input format
+//     (ifmt) and metrics, and supervisor runtime state.
+//   - ?codec= keeps channels whose input carries a stream with that codec
+//     (aliases accepted, e.g. "h265" or "hevc"); ?stream_type= keeps
+//     channels whose input has a stream of that type (video, audio,
+//     subtitle, data). Only online channels have an ifmt to match.
+//   - ?force=1 bypasses the snapshot cache.
 func (h *ChannelsHandler) Summary(c *gin.Context) {
 	// Optional query to bypass cache for admin/diagnostics: ?force=1
 	force := c.Query("force") == "1"
@@ -674,6 +1261,17 @@ func (h *ChannelsHandler) Summary(c *gin.Context) {
 		return
 	}
 
+	if codec, streamType := c.Query("codec"), c.Query("stream_type"); codec != "" || streamType != "" {
+		filtered := res.Data[:0:0]
+		for _, sum := range res.Data {
+			if codec != "" && !sum.Ifmt.HasCodec(codec) || streamType != "" && !sum.Ifmt.HasStreamType(streamType) {
+				continue
+			}
+			filtered = append(filtered, sum)
+		}
+		res.Data = filtered
+	}
+
 	// Friendly cache headers for debugging/observability
 	c.Header("X-Cache", map[bool]string{true: "HIT", false: "MISS"}[res.CacheHit])
 	c.Header("X-Summary-Generated-At", strconv.FormatInt(res.GeneratedAt.UnixMilli(), 10))
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	b2bclient "github.com/edirooss/zmux-server/internal/domain/b2b-client"
 	"github.com/edirooss/zmux-server/internal/domain/principal"
 	"github.com/edirooss/zmux-server/internal/service"
 	"github.com/gin-gonic/gin"
@@ -49,6 +50,7 @@ func Me(authsvc *service.AuthService, b2bclntsvc *service.B2BClientService) gin.
 			res := struct {
 				Name   string `json:"name"`
 				Quotas struct {
+					Channels        b2bclient.ChannelsView `json:"channels"`
 					EnabledChannels struct {
 						Quota int64 `json:"quota"`
 						Usage int64 `json:"usage"`
@@ -62,10 +64,12 @@ func Me(authsvc *service.AuthService, b2bclntsvc *service.B2BClientService) gin.
 						Usage int64 `json:"usage"`
 					} `json:"online_channels"`
 				} `json:"quotas"`
-				ChannelIDs []int64 `json:"channel_ids"`
+				ChannelIDs  []int64               `json:"channel_ids"`
+				Permissions principal.Permissions `json:"permissions"`
 			}{
 				Name: clnt.Name,
 				Quotas: struct {
+					Channels        b2bclient.ChannelsView `json:"channels"`
 					EnabledChannels struct {
 						Quota int64 `json:"quota"`
 						Usage int64 `json:"usage"`
@@ -79,6 +83,7 @@ func Me(authsvc *service.AuthService, b2bclntsvc *service.B2BClientService) gin.
 						Usage int64 `json:"usage"`
 					} `json:"online_channels"`
 				}{
+					Channels:        clnt.Quotas.Channels,
 					EnabledChannels: clnt.Quotas.EnabledChannels,
 					EnabledOutputs:  enabledOutputs,
 					OnlineChannels: struct {
@@ -89,13 +94,17 @@ func Me(authsvc *service.AuthService, b2bclntsvc *service.B2BClientService) gin.
 						Usage: clnt.Quotas.EnabledChannels.Usage,
 					},
 				},
-				ChannelIDs: clnt.ChannelIDs,
+				ChannelIDs:  clnt.ChannelIDs,
+				Permissions: p.Permissions(),
 			}
 
 			c.JSON(http.StatusOK, res)
 			return
 		}
 
-		c.JSON(http.StatusOK, p)
+		c.JSON(http.StatusOK, struct {
+			*principal.Principal
+			Permissions principal.Permissions `json:"permissions"`
+		}{p, p.Permissions()})
 	}
 }
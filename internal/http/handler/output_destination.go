@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	outputdestination "github.com/edirooss/zmux-server/internal/domain/output-destination"
+	"github.com/edirooss/zmux-server/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Output destinations catalog.
+//
+// Supported operations:
+//   - GET    /output-destinations     → list destinations
+//   - GET    /output-destinations/:id → get a destination
+//   - POST   /output-destinations     → create a destination
+//   - PUT    /output-destinations/:id → replace a destination (ref is immutable)
+//   - DELETE /output-destinations/:id → delete a destination
+//
+// Channel outputs refer to destinations by ref. The catalog decides which
+// outputs B2B clients see and toggle, the defaults of new channel outputs and
+// the localaddrs they may use.
+type OutputDestinationHandler struct {
+	outdestsvc *service.OutputDestinationService
+	b2bclntsvc *service.B2BClientService
+	authsvc    *service.AuthService
+	auditsvc   *service.AuditService
+}
+
+func NewOutputDestinationHandler(outdestsvc *service.OutputDestinationService, b2bclntsvc *service.B2BClientService, authsvc *service.AuthService, auditsvc *service.AuditService) *OutputDestinationHandler {
+	return &OutputDestinationHandler{outdestsvc, b2bclntsvc, authsvc, auditsvc}
+}
+
+// CreateOutputDestination handles POST /output-destinations.
+//
+// Status Codes:
+//   - 201 Created → JSON destination
+//   - 400 Bad Request → invalid body
+//   - 409 Conflict → ref already exists
+//   - 422 Unprocessable Entity → validation failed
+//   - 500 Internal Server Error
+func (h *OutputDestinationHandler) CreateOutputDestination(c *gin.Context) {
+	var req outputdestination.OutputDestinationResource
+	if err := bind(c.Request, &req); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return
+	}
+
+	view, err := h.outdestsvc.Create(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+
+		if errors.Is(err, service.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+
+		return
+	}
+	h.auditOutputDestination(c, service.AuditCreate, view.ID, nil)
+
+	c.Header("Location", fmt.Sprintf("/api/output-destinations/%d", view.ID))
+	c.JSON(http.StatusCreated, view)
+}
+
+// UpdateOutputDestination handles PUT /output-destinations/:id.
+//
+// Behavior:
+//   - Existing channel outputs keep their settings; defaults apply to new
+//     outputs and localaddrs to new or changed ones.
+//
+// Status Codes:
+//   - 200 OK → JSON destination
+//   - 400 Bad Request → invalid id or body
+//   - 404 Not Found → unknown destination
+//   - 409 Conflict → ref changed
+//   - 422 Unprocessable Entity → validation failed
+//   - 500 Internal Server Error
+func (h *OutputDestinationHandler) UpdateOutputDestination(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	var req outputdestination.OutputDestinationResource
+	if err := bind(c.Request, &req); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return
+	}
+
+	prev, _ := h.outdestsvc.GetModel(id) // for the audit record; Update reports a missing destination
+	view, err := h.outdestsvc.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		c.Error(err)
+
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		} else if errors.Is(err, service.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+
+		return
+	}
+	h.auditOutputDestination(c, service.AuditUpdate, id, prev)
+
+	c.JSON(http.StatusOK, view)
+}
+
+// GetOutputDestination handles GET /output-destinations/:id.
+func (h *OutputDestinationHandler) GetOutputDestination(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	view, err := h.outdestsvc.GetOne(id)
+	if err != nil {
+		c.Error(err)
+
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+// GetAllOutputDestinations handles GET /output-destinations.
+func (h *OutputDestinationHandler) GetAllOutputDestinations(c *gin.Context) {
+	views, err := h.outdestsvc.GetList()
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, views)
+}
+
+// GetOutputRefs handles GET /channels/outputs/ref: the catalog as
+// [{"id": <ref>, "name": ...}].
+func (h *OutputDestinationHandler) GetOutputRefs(c *gin.Context) {
+	type OutputRef struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	views, err := h.outdestsvc.GetList()
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	refs := make([]OutputRef, len(views))
+	for i, v := range views {
+		refs[i] = OutputRef{ID: v.Ref, Name: v.Name}
+	}
+	c.JSON(http.StatusOK, refs)
+}
+
+// DeleteOutputDestination handles DELETE /output-destinations/:id.
+//
+// Behavior:
+//   - Channel outputs to the destination are kept (no longer visible to B2B
+//     clients).
+//
+// Status Codes:
+//   - 204 No Content → deleted
+//   - 400 Bad Request → invalid id
+//   - 404 Not Found → unknown destination
+//   - 409 Conflict → named by a B2B client's quotas, policy or channel template
+//   - 500 Internal Server Error
+func (h *OutputDestinationHandler) DeleteOutputDestination(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	prev, err := h.outdestsvc.GetModel(id)
+	if err != nil {
+		c.Error(err)
+
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+
+		return
+	}
+	if users := h.b2bclntsvc.OutputRefUsers(prev.Ref); len(users) > 0 {
+		err := fmt.Errorf("cannot delete; output destination %q is used by B2B clients %v", prev.Ref, users)
+		c.Error(err)
+		c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		return
+	}
+
+	if err := h.outdestsvc.Delete(c.Request.Context(), id); err != nil {
+		c.Error(err)
+
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+
+		return
+	}
+	h.auditOutputDestination(c, service.AuditDelete, id, prev)
+
+	c.Status(http.StatusNoContent)
+}
+
+// auditOutputDestination records a destination write; prev is the record
+// before it (nil on create).
+func (h *OutputDestinationHandler) auditOutputDestination(c *gin.Context, action string, id int64, prev *outputdestination.OutputDestinationModel) {
+	var before, after any
+	if prev != nil {
+		before = prev
+	}
+	if action != service.AuditDelete {
+		if next, err := h.outdestsvc.GetModel(id); err == nil {
+			after = next
+		}
+	}
+	audit(c, h.authsvc, h.auditsvc, action, service.AuditResourceOutputDestination, id, before, after)
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	b2bclient "github.com/edirooss/zmux-server/internal/domain/b2b-client"
+	"github.com/edirooss/zmux-server/internal/domain/channel"
+	"github.com/edirooss/zmux-server/internal/domain/principal"
+	"github.com/edirooss/zmux-server/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ReportHandler serves availability reports.
+//
+// Supported operations:
+//   - GET /reports/uptime → per-channel and per-B2B-client uptime (JSON or CSV)
+//   - GET /me/uptime      → the calling B2B client's own uptime
+type ReportHandler struct {
+	authsvc    *service.AuthService
+	uptimesvc  *service.UptimeService
+	chnlsvc    *service.ChannelService
+	b2bclntsvc *service.B2BClientService
+}
+
+func NewReportHandler(authsvc *service.AuthService, uptimesvc *service.UptimeService, chnlsvc *service.ChannelService, b2bclntsvc *service.B2BClientService) *ReportHandler {
+	return &ReportHandler{authsvc, uptimesvc, chnlsvc, b2bclntsvc}
+}
+
+// GetUptimeReport handles GET /reports/uptime.
+//
+// Behavior:
+//   - ?window= day | week | month (rolling, ending now; default day).
+//   - Reports every channel and every B2B client (rolled up over its
+//     channels). Only time a channel was enabled counts.
+//   - ?format=csv returns text/csv, one row per channel followed by one row
+//     per B2B client (column "scope": channel | b2b_client).
+//
+// Status Codes:
+//   - 200 OK → JSON report or CSV
+//   - 400 Bad Request → invalid window or format
+//   - 500 Internal Server Error
+func (h *ReportHandler) GetUptimeReport(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		err := fmt.Errorf("invalid format %q: must be json or csv", format)
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	chs, err := h.chnlsvc.GetList(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	clients, err := h.b2bclntsvc.GetList()
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	report, ok := h.report(c, chs, clients)
+	if !ok {
+		return
+	}
+
+	if format == "csv" {
+		writeUptimeCSV(c, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// GetOwnUptime handles GET /me/uptime: the uptime report of the calling B2B
+// client's channels and its rollup (see GetUptimeReport; JSON only).
+//
+// Status Codes:
+//   - 200 OK → JSON report
+//   - 400 Bad Request → invalid window
+//   - 403 Forbidden → not a B2B client
+//   - 500 Internal Server Error
+func (h *ReportHandler) GetOwnUptime(c *gin.Context) {
+	p := h.authsvc.WhoAmI(c)
+	if p == nil {
+		c.Status(http.StatusUnauthorized)
+		return
+	}
+	if p.Kind != principal.B2BClient {
+		c.JSON(http.StatusForbidden, gin.H{"message": "only B2B clients have an own uptime report; use /api/reports/uptime"})
+		return
+	}
+	clntID, err := strconv.ParseInt(p.ID, 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	clnt, err := h.b2bclntsvc.GetOne(clntID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	chs := make([]*channel.ZmuxChannel, 0, len(clnt.ChannelIDs))
+	for _, id := range clnt.ChannelIDs {
+		if ch, err := h.chnlsvc.GetOne(id); err == nil {
+			chs = append(chs, ch)
+		}
+	}
+
+	report, ok := h.report(c, chs, []*b2bclient.B2BClientView{clnt})
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// report computes the report for ?window=; on failure the response is
+// written and ok is false.
+func (h *ReportHandler) report(c *gin.Context, chs []*channel.ZmuxChannel, clients []*b2bclient.B2BClientView) (*service.UptimeReport, bool) {
+	window := c.DefaultQuery("window", "day")
+	if _, ok := service.UptimeWindows[window]; !ok {
+		err := fmt.Errorf("invalid window %q: must be one of day, week, month", window)
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return nil, false
+	}
+
+	report, err := h.uptimesvc.Report(c.Request.Context(), window, chs, clients)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return nil, false
+	}
+	return report, true
+}
+
+func writeUptimeCSV(c *gin.Context, report *service.UptimeReport) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="uptime-%s-%d.csv"`, report.Window, report.To))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"scope", "id", "name", "b2b_client_id", "window", "from", "to", "uptime_pct", "monitored_sec", "uptime_sec", "downtime_sec", "outages", "mttr_sec"})
+
+	row := func(scope string, id int64, name, owner string, s service.UptimeStats) {
+		w.Write([]string{
+			scope,
+			strconv.FormatInt(id, 10),
+			name,
+			owner,
+			report.Window,
+			strconv.FormatInt(report.From, 10),
+			strconv.FormatInt(report.To, 10),
+			csvFloatP(s.UptimePct),
+			csvFloat(s.MonitoredSec),
+			csvFloat(s.UptimeSec),
+			csvFloat(s.DowntimeSec),
+			strconv.Itoa(s.Outages),
+			csvFloatP(s.MTTRSec),
+		})
+	}
+	for _, ch := range report.Channels {
+		name, owner := "", ""
+		if ch.Name != nil {
+			name = *ch.Name
+		}
+		if ch.B2BClientID != nil {
+			owner = strconv.FormatInt(*ch.B2BClientID, 10)
+		}
+		row("channel", ch.ChannelID, name, owner, ch.UptimeStats)
+	}
+	for _, clnt := range report.B2BClients {
+		row("b2b_client", clnt.B2BClientID, clnt.Name, "", clnt.UptimeStats)
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		c.Error(fmt.Errorf("write csv: %w", err))
+	}
+}
+
+func csvFloat(f float64) string { return strconv.FormatFloat(f, 'f', 3, 64) }
+
+func csvFloatP(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return csvFloat(*f)
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sseHeartbeat is the idle interval after which a comment line is sent so
+// proxies and load balancers keep the stream open.
+const sseHeartbeat = 15 * time.Second
+
+// startSSE switches the response to a Server-Sent Events stream.
+//
+// The server-wide write timeout is lifted for this response only (streams are
+// long-lived by design) and the headers are flushed immediately.
+func startSSE(c *gin.Context) {
+	// Best effort: errors only mean the writer does not support deadlines.
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	h := c.Writer.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no") // disable Nginx response buffering
+
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+}
+
+// sseLineBreaks normalizes the line terminators recognized by EventSource.
+var sseLineBreaks = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+// writeSSE writes one event and flushes it. id and event may be empty.
+// Multi-line data is split into several "data:" lines (rejoined by the client).
+func writeSSE(c *gin.Context, id, event string, data []byte) error {
+	var b bytes.Buffer
+	if id != "" {
+		b.WriteString("id: " + id + "\n")
+	}
+	if event != "" {
+		b.WriteString("event: " + event + "\n")
+	}
+	for _, line := range strings.Split(sseLineBreaks.Replace(string(data)), "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+
+	if _, err := c.Writer.Write(b.Bytes()); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
+
+// writeSSEHeartbeat writes a comment line (ignored by EventSource clients).
+func writeSSEHeartbeat(c *gin.Context) error {
+	if _, err := c.Writer.Write([]byte(": ping\n\n")); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/edirooss/zmux-server/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// UsageHandler serves B2B client usage metering.
+//
+// Supported operations:
+//   - GET /b2b-clients/:id/usage → one client's monthly usage, per day
+//   - GET /usage                 → billing export of all clients (JSON or CSV)
+type UsageHandler struct {
+	usagesvc   *service.UsageService
+	b2bclntsvc *service.B2BClientService
+}
+
+func NewUsageHandler(usagesvc *service.UsageService, b2bclntsvc *service.B2BClientService) *UsageHandler {
+	return &UsageHandler{usagesvc, b2bclntsvc}
+}
+
+// GetB2BClientUsage handles GET /b2b-clients/:id/usage.
+//
+// Behavior:
+//   - ?month=YYYY-MM (UTC; default current month).
+//   - Returns the month totals (channel-hours enabled, channel-hours online,
+//     output-hours per output ref) and the days with usage.
+//   - Usage of the running month lags by up to usage.flush_interval.
+//
+// Status Codes:
+//   - 200 OK → usage
+//   - 400 Bad Request → invalid id or month
+//   - 404 Not Found → unknown B2B client
+//   - 500 Internal Server Error
+func (h *UsageHandler) GetB2BClientUsage(c *gin.Context) {
+	b2bClientID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	month, err := service.ParseUsageMonth(c.Query("month"))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	clnt, err := h.b2bclntsvc.GetOne(b2bClientID)
+	if err != nil {
+		c.Error(err)
+
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+
+		return
+	}
+
+	usage, err := h.usagesvc.GetB2BClientUsage(c.Request.Context(), clnt, month)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, usage)
+}
+
+// ExportUsage handles GET /usage.
+//
+// Behavior:
+//   - ?month=YYYY-MM (UTC; default current month).
+//   - Lists the month totals of every B2B client, including deleted clients
+//     with recorded usage (empty name).
+//   - ?format=csv returns text/csv with one row per client and metric
+//     (columns b2b_client_id, name, month, metric, output_ref, hours; metric:
+//     channel_hours_enabled | channel_hours_online | output_hours).
+//
+// Status Codes:
+//   - 200 OK → JSON export or CSV
+//   - 400 Bad Request → invalid month or format
+//   - 500 Internal Server Error
+func (h *UsageHandler) ExportUsage(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		err := fmt.Errorf("invalid format %q: must be json or csv", format)
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	month, err := service.ParseUsageMonth(c.Query("month"))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	clients, err := h.b2bclntsvc.GetList()
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	export, err := h.usagesvc.Export(c.Request.Context(), month, clients)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	if format == "csv" {
+		writeUsageCSV(c, export)
+		return
+	}
+	c.JSON(http.StatusOK, export)
+}
+
+func writeUsageCSV(c *gin.Context, export *service.UsageExport) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="usage-%s.csv"`, export.Month))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"b2b_client_id", "name", "month", "metric", "output_ref", "hours"})
+	for _, u := range export.B2BClients {
+		row := func(metric, ref string, hours float64) {
+			w.Write([]string{strconv.FormatInt(u.B2BClientID, 10), u.Name, u.Month, metric, ref, csvFloat(hours)})
+		}
+		row("channel_hours_enabled", "", u.ChannelHoursEnabled)
+		row("channel_hours_online", "", u.ChannelHoursOnline)
+
+		refs := make([]string, 0, len(u.OutputHours))
+		for ref := range u.OutputHours {
+			refs = append(refs, ref)
+		}
+		sort.Strings(refs)
+		for _, ref := range refs {
+			row("output_hours", ref, u.OutputHours[ref])
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		c.Error(fmt.Errorf("write csv: %w", err))
+	}
+}
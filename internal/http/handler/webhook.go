@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/edirooss/zmux-server/internal/domain/webhook"
+	"github.com/edirooss/zmux-server/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhooksvc *service.WebhookService
+}
+
+func NewWebhookHandler(webhooksvc *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhooksvc}
+}
+
+// CreateWebhook registers a webhook subscription.
+//
+// Behavior:
+//   - When "secret" is omitted a random one is generated.
+//   - The response is the only one carrying the secret.
+//
+// Status Codes:
+//   - 201 Created: webhook created
+//   - 400 Bad Request: malformed JSON
+//   - 422 Unprocessable Entity: validation failed (url, events, secret)
+//   - 500 Internal Server Error
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req webhook.WebhookResource
+	if err := bind(c.Request, &req); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return
+	}
+
+	view, err := h.webhooksvc.Create(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/api/webhooks/%d", view.ID))
+	c.JSON(http.StatusCreated, view)
+}
+
+// UpdateWebhook replaces a webhook subscription; a null/omitted "secret"
+// keeps the current one.
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	var req webhook.WebhookResource
+	if err := bind(c.Request, &req); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return
+	}
+
+	view, err := h.webhooksvc.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		c.Error(err)
+
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	view, err := h.webhooksvc.GetOne(id)
+	if err != nil {
+		c.Error(err)
+
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+func (h *WebhookHandler) GetAllWebhooks(c *gin.Context) {
+	views, err := h.webhooksvc.GetList()
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, views)
+}
+
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	if err := h.webhooksvc.Delete(c.Request.Context(), id); err != nil {
+		c.Error(err)
+
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries returns the delivery log of a webhook, newest first.
+//
+// Behavior:
+//   - One entry per attempt; retries share the delivery_id.
+//   - ?limit= (1..200, default 50).
+//
+// Status Codes:
+//   - 200 OK: JSON array of deliveries
+//   - 400 Bad Request: invalid id or limit
+//   - 404 Not Found: unknown webhook
+//   - 500 Internal Server Error
+func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	limit := 50
+	if v := c.Query("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > 200 {
+			err = fmt.Errorf("invalid limit %q: must be an integer in 1..200", v)
+			c.Error(err)
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+	}
+
+	deliveries, err := h.webhooksvc.GetDeliveries(c.Request.Context(), id, limit)
+	if err != nil {
+		c.Error(err)
+
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// PingWebhook queues a test delivery ("ping" event), sent regardless of the
+// webhook's subscriptions or enabled flag. The outcome shows up in the
+// delivery log.
+//
+// Status Codes:
+//   - 202 Accepted: {"delivery_id": "..."}
+//   - 400 Bad Request: invalid id
+//   - 404 Not Found: unknown webhook
+//   - 503 Service Unavailable: delivery queue full
+func (h *WebhookHandler) PingWebhook(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	deliveryID, err := h.webhooksvc.Ping(id)
+	if err != nil {
+		c.Error(err)
+
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		} else {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"message": err.Error()})
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"delivery_id": deliveryID})
+}
@@ -3,25 +3,21 @@ package middleware
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/edirooss/zmux-server/internal/domain/principal"
 	"github.com/edirooss/zmux-server/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
-// Authorization restricts access to the given principal kinds.
+// RequirePermission restricts access to principals holding perm
+// (see principal.Permissions for the role → permission matrix).
 //
 //   - 401 if no principal (unauthenticated)
-//   - 403 if principal kind not in allowed set (unauthorized)
+//   - 403 if the principal's effective permissions lack perm (unauthorized)
 //
-// Admins are always allowed.
-func Authorization(auth *service.AuthService, kinds ...principal.PrincipalKind) gin.HandlerFunc {
-	// precompute set for O(1) membership check
-	allowed := make(map[principal.PrincipalKind]struct{}, len(kinds))
-	for _, k := range kinds {
-		allowed[k] = struct{}{}
-	}
-
+// No principal kind or role bypasses this check.
+func RequirePermission(auth *service.AuthService, perm principal.Permission) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		p := auth.WhoAmI(c)
 		if p == nil {
@@ -29,17 +25,41 @@ func Authorization(auth *service.AuthService, kinds ...principal.PrincipalKind)
 			return
 		}
 
-		if p.Kind == principal.Admin {
-			c.Next() // Admins bypass all restrictions
+		if !p.Can(perm) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "missing permission " + string(perm)})
 			return
 		}
 
-		if _, ok := allowed[p.Kind]; !ok {
-			c.AbortWithStatus(http.StatusForbidden) // role not permitted
+		c.Next()
+	}
+}
+
+// RequireAnyPermission is RequirePermission for routes open to several
+// permissions (e.g. admins on any channel, B2B clients on their own); the
+// handler tells them apart.
+//
+//   - 401 if no principal (unauthenticated)
+//   - 403 if the principal holds none of perms (unauthorized)
+func RequireAnyPermission(auth *service.AuthService, perms ...principal.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := auth.WhoAmI(c)
+		if p == nil {
+			c.AbortWithStatus(http.StatusUnauthorized) // no session/token → stop
 			return
 		}
 
-		c.Next()
+		for _, perm := range perms {
+			if p.Can(perm) {
+				c.Next()
+				return
+			}
+		}
+
+		names := make([]string, len(perms))
+		for i, perm := range perms {
+			names[i] = string(perm)
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "missing permission " + strings.Join(names, " or ")})
 	}
 }
 
@@ -67,7 +87,8 @@ func RequireB2BClient(auth *service.AuthService) gin.HandlerFunc {
 //   - 401 if unauthenticated
 //   - 403 if not authorized for the channel
 //
-// Admins always bypass this check.
+// Admin users (any role) are not scoped to channels and bypass this check;
+// what they may do with the channel is decided by RequirePermission.
 func RequireChannelIDAccess(auth *service.AuthService, b2bclntsvc *service.B2BClientService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		p := auth.WhoAmI(c)
@@ -0,0 +1,32 @@
+// internal/http/middleware/metrics.go
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/edirooss/zmux-server/internal/infrastructure/telemetry"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics is a Gin middleware that records request counts and latency
+// (see telemetry.HTTPRequests, telemetry.HTTPDuration).
+//
+// Requests are labelled by route template (c.FullPath(), e.g.
+// "/api/channels/:id") rather than raw path to keep label cardinality
+// bounded; requests matching no route are labelled "unmatched".
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+
+		telemetry.HTTPRequests.Inc(method, route, strconv.Itoa(c.Writer.Status()))
+		telemetry.HTTPDuration.Observe(time.Since(start).Seconds(), method, route)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/edirooss/zmux-server/internal/infrastructure/telemetry"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
@@ -84,6 +85,7 @@ type DataStore struct {
 	log       *zap.Logger
 	rdb       *redis.Client // Redis used as persistent storage (system of record); values-only
 	keyPrefix string        // Redis key prefix; e.g. <store>:  → raw bytes under <prefix><id>
+	component string        // telemetry label; "datastore:<store>"
 
 	mu  sync.Mutex    // serializes all operations
 	pos map[int64]int // id -> index into ordered ids
@@ -110,6 +112,7 @@ func NewDataStore(ctx context.Context, log *zap.Logger, rdb *redis.Client, keyPr
 	s := &DataStore{
 		rdb:       rdb,
 		keyPrefix: keyPrefix,
+		component: "datastore:" + strings.TrimSuffix(keyPrefix, ":"),
 		log:       log,
 		pos:       make(map[int64]int),
 		ids:       make([]int64, 0),
@@ -133,13 +136,18 @@ func (s *DataStore) Create(ctx context.Context, value []byte) (int64, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	start := time.Now()
 	id, err := s.rdb.Incr(ctx, sequenceKey(s.keyPrefix)).Result()
+	telemetry.ObserveRedis(s.component, "incr", start, err)
 	if err != nil {
 		return 0, fmt.Errorf("generate id via INCR: %w", err)
 	}
 
 	v := bcopy(value)
-	if err := s.rdb.Set(ctx, recordKey(s.keyPrefix, id), v, 0).Err(); err != nil {
+	start = time.Now()
+	err = s.rdb.Set(ctx, recordKey(s.keyPrefix, id), v, 0).Err()
+	telemetry.ObserveRedis(s.component, "set", start, err)
+	if err != nil {
 		return 0, fmt.Errorf("set (key=%s): %w", recordKey(s.keyPrefix, id), err)
 	}
 
@@ -163,7 +171,10 @@ func (s *DataStore) Update(ctx context.Context, id int64, value []byte) error {
 	}
 
 	v := bcopy(value)
-	if err := s.rdb.Set(ctx, recordKey(s.keyPrefix, id), v, 0).Err(); err != nil {
+	start := time.Now()
+	err := s.rdb.Set(ctx, recordKey(s.keyPrefix, id), v, 0).Err()
+	telemetry.ObserveRedis(s.component, "set", start, err)
+	if err != nil {
 		return fmt.Errorf("set (key=%s): %w", recordKey(s.keyPrefix, id), err)
 	}
 
@@ -186,7 +197,9 @@ func (s *DataStore) Delete(ctx context.Context, id int64) error {
 	// Always attempt to delete from Redis; DEL is idempotent:
 	//   Key exists → (1, nil)
 	//   Key absent → (0, nil)
+	start := time.Now()
 	n, err := s.rdb.Del(ctx, recordKey(s.keyPrefix, id)).Result()
+	telemetry.ObserveRedis(s.component, "del", start, err)
 	if err != nil {
 		return fmt.Errorf("del: %w", err)
 	}
@@ -219,7 +232,9 @@ func (s *DataStore) GetOne(ctx context.Context, id int64) ([]byte, error) {
 		return nil, ErrNotFound
 	}
 
+	start := time.Now()
 	val, err := s.rdb.Get(ctx, recordKey(s.keyPrefix, id)).Bytes()
+	telemetry.ObserveRedis(s.component, "get", start, err)
 	if err != nil {
 		if err == redis.Nil {
 			s.log.Warn("get_one: auto-heal (indexed id missing in Redis)", zap.Int64("id", id))
@@ -266,7 +281,9 @@ func (s *DataStore) GetMany(ctx context.Context, ids []int64) ([][]byte, error)
 		for i := range pres {
 			keys[i] = pres[i].key
 		}
+		start := time.Now()
 		raws, err := s.rdb.MGet(ctx, keys...).Result()
+		telemetry.ObserveRedis(s.component, "mget", start, err)
 		if err != nil {
 			return nil, fmt.Errorf("redis mget: %w", err)
 		}
@@ -325,7 +342,9 @@ func (s *DataStore) GetList(ctx context.Context) ([]int64, [][]byte, error) {
 		keys[i] = recordKey(s.keyPrefix, id)
 	}
 
+	start := time.Now()
 	vals, err := s.rdb.MGet(ctx, keys...).Result()
+	telemetry.ObserveRedis(s.component, "mget", start, err)
 	if err != nil {
 		return nil, nil, fmt.Errorf("redis mget: %w", err)
 	}
@@ -0,0 +1,47 @@
+package processmgr
+
+import "time"
+
+// EventKind is a process lifecycle transition of a unit.
+type EventKind string
+
+const (
+	EventStarted     EventKind = "started"      // child spawned
+	EventStartFailed EventKind = "start_failed" // spawn failed; a retry is scheduled
+	EventAdopted     EventKind = "adopted"      // child left by a previous server instance re-attached
+	EventActive      EventKind = "active"       // PM2 only: promoted past the readiness barrier (ENTER written)
+	EventExited      EventKind = "exited"       // child gone; a restart may follow
+	EventFailed      EventKind = "failed"       // crash-loop breaker tripped; no further restarts until ResetFailed
+)
+
+// Event describes a single lifecycle transition.
+type Event struct {
+	UID    int64
+	Kind   EventKind
+	At     time.Time
+	CmdPID int // OS pid (0 when no process was spawned)
+
+	// EventExited only
+	ExitCode   *int          // nil when unknown (adopted child, or killed by a signal)
+	ExitSignal string        // e.g. "terminated"; empty when the child exited on its own
+	RestartIn  time.Duration // delay until the next launch; 0 when no restart follows
+	Restarting bool          // the unit is still registered and will be relaunched
+}
+
+// EventHandler receives lifecycle events.
+//
+// Handlers are called synchronously from supervisor goroutines, sometimes
+// while the manager lock is held: they must not block and must not call back
+// into the manager.
+type EventHandler func(Event)
+
+// emit calls h if set.
+func (h EventHandler) emit(ev Event) {
+	if h == nil {
+		return
+	}
+	if ev.At.IsZero() {
+		ev.At = time.Now()
+	}
+	h(ev)
+}
@@ -1,28 +1,72 @@
 package processmgr
 
-import "sync"
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 // logBuffer is a thread-safe circular buffer for log entries with O(1) append and O(N) read
 type logBuffer struct {
 	entries [500]string  // Fixed-size circular buffer (no heap allocations)
+	times   [500]int64   // Append time of each entry (UTC millis)
 	head    int          // Next write position (0-499)
 	size    int          // Current number of entries (0-500)
 	full    bool         // Whether buffer has wrapped around
 	mu      sync.RWMutex // Read-write mutex; Protects all fields
+
+	subs   map[*LogSubscription]struct{} // live tails (lazily allocated)
+	closed bool                          // set by LogManager.Remove; no new subscribers
+
+	sink func(at time.Time, entry string) // optional on-disk copy (LogFiles); nil when disabled
 }
 
+// LogSubscription is a live tail of a log buffer.
+//
+// Each subscriber owns a bounded queue. Append never blocks on a subscriber:
+// when the queue is full the entry is dropped for that subscriber only and
+// counted (see Dropped), so a slow reader cannot stall the pipe drain.
+type LogSubscription struct {
+	ch      chan string
+	dropped atomic.Uint64
+	closed  bool // guarded by logBuffer.mu
+}
+
+// Lines delivers new entries in append order. Closed on Unsubscribe or when
+// the buffer is removed (unit deleted).
+func (s *LogSubscription) Lines() <-chan string { return s.ch }
+
+// Dropped returns and resets the number of entries dropped since the last call.
+func (s *LogSubscription) Dropped() uint64 { return s.dropped.Swap(0) }
+
 // Append adds a log entry (overwrites oldest if full)
 // Write lock held for ~100ns (single array write + arithmetic)
 //
-// Complexity: O(1) time, O(1) space
+// Complexity: O(1) time, O(1) space (+ one non-blocking send per subscriber)
 func (b *logBuffer) Append(entry string) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
 	const capN = len(b.entries)
+	now := time.Now()
+
+	// Persist (buffered; see LogFiles)
+	if b.sink != nil && !b.closed {
+		b.sink(now, entry)
+	}
+
+	// Fan out to live tails
+	for sub := range b.subs {
+		select {
+		case sub.ch <- entry:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
 
 	// Write to circular buffer
 	b.entries[b.head] = entry
+	b.times[b.head] = now.UnixMilli()
 
 	// Advance head
 	b.head = (b.head + 1) % capN
@@ -38,6 +82,65 @@ func (b *logBuffer) Append(entry string) {
 	}
 }
 
+// Subscribe registers a live tail with a queue of queueLen entries and
+// returns it together with the current contents (oldest → newest), taken
+// atomically so no entry is missed or duplicated between the two.
+//
+// On a removed buffer the subscription is returned already closed.
+func (b *logBuffer) Subscribe(queueLen int) (*LogSubscription, []string) {
+	if queueLen <= 0 {
+		queueLen = 1
+	}
+	sub := &LogSubscription{ch: make(chan string, queueLen)}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		sub.closed = true
+		close(sub.ch)
+		return sub, nil
+	}
+	if b.subs == nil {
+		b.subs = make(map[*LogSubscription]struct{})
+	}
+	b.subs[sub] = struct{}{}
+
+	backlog := b.readUnsafe(0)
+	for i, j := 0, len(backlog)-1; i < j; i, j = i+1, j-1 {
+		backlog[i], backlog[j] = backlog[j], backlog[i]
+	}
+	return sub, backlog
+}
+
+// Unsubscribe detaches sub; safe to call more than once.
+func (b *logBuffer) Unsubscribe(sub *LogSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.dropUnsafe(sub)
+}
+
+// close detaches all subscribers and rejects new ones.
+func (b *logBuffer) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subs {
+		b.dropUnsafe(sub)
+	}
+}
+
+// dropUnsafe closes and removes sub. Caller must hold b.mu.
+func (b *logBuffer) dropUnsafe(sub *LogSubscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(b.subs, sub)
+	close(sub.ch)
+}
+
 // Read returns last N entries (newest → oldest)
 // Read lock held for duration of copy operation (~1μs per entry)
 // Returns a NEW slice (caller owns memory)
@@ -51,7 +154,53 @@ func (b *logBuffer) Append(entry string) {
 func (b *logBuffer) Read(lines int) []string {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
+	return b.readUnsafe(lines)
+}
+
+// ReadRange returns up to limit entries appended within [since, until]
+// (newest → oldest; limit <= 0 means no limit).
+//
+// covered reports whether the buffer still holds everything since `since`,
+// i.e. its oldest entry is not newer than since. Otherwise older entries
+// may exist only on disk (wrapped, or written by a previous server run).
+func (b *logBuffer) ReadRange(since, until time.Time, limit int) (entries []string, covered bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	const capN = len(b.entries)
+	if b.size == 0 {
+		return nil, false
+	}
+
+	from, to := since.UnixMilli(), until.UnixMilli()
+
+	newest := b.size - 1
+	oldest := 0
+	if b.full {
+		newest = (b.head - 1 + capN) % capN
+		oldest = b.head
+	}
+	covered = b.times[oldest] <= from
+
+	for i := 0; i < b.size; i++ {
+		idx := (newest - i + capN) % capN
+		at := b.times[idx]
+		if at > to {
+			continue
+		}
+		if at < from {
+			break
+		}
+		entries = append(entries, b.entries[idx])
+		if limit > 0 && len(entries) == limit {
+			break
+		}
+	}
+	return entries, covered
+}
 
+// readUnsafe is Read without locking. Caller must hold b.mu.
+func (b *logBuffer) readUnsafe(lines int) []string {
 	const capN = len(b.entries)
 	if b.size == 0 {
 		return nil
@@ -0,0 +1,529 @@
+package processmgr
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// LogFilesOptions configures the on-disk log sink.
+type LogFilesOptions struct {
+	Dir             string        // root directory; one sub-directory per unit
+	MaxSegmentBytes int64         // rotate the active segment at this size
+	MaxSegmentAge   time.Duration // rotate the active segment at this age
+	MaxTotalBytes   int64         // retention cap across all units (oldest segments go first)
+	Retention       time.Duration // delete segments older than this; 0 keeps them until MaxTotalBytes
+}
+
+func (o *LogFilesOptions) setDefaults() {
+	if o.MaxSegmentBytes <= 0 {
+		o.MaxSegmentBytes = 16 << 20 // 16MiB
+	}
+	if o.MaxSegmentAge <= 0 {
+		o.MaxSegmentAge = 24 * time.Hour
+	}
+	if o.MaxTotalBytes <= 0 {
+		o.MaxTotalBytes = 1 << 30 // 1GiB
+	}
+}
+
+// LogFiles is the optional on-disk copy of unit logs. Unlike the in-memory
+// ring it survives restarts and keeps history beyond the last 500 lines.
+//
+// Layout (times are UTC millis of the first/last line):
+//
+//	<dir>/<uid>/<start>.log            active segment (at most one per unit)
+//	<dir>/<uid>/<start>-<end>.log      sealed, compression pending
+//	<dir>/<uid>/<start>-<end>.log.gz   sealed and compressed
+//
+// Each line is "<millis> <entry>". Writes are buffered and flushed every
+// second, so a crash loses at most the last second of output.
+type LogFiles struct {
+	log  *zap.Logger
+	opts LogFilesOptions
+
+	mu      sync.Mutex
+	units   map[int64]*segmentWriter
+	closed  bool // set by Close; late writes are dropped
+	stop    chan struct{}
+	stopped sync.WaitGroup // background loop + compressions
+}
+
+// segmentWriter appends to the active segment of one unit.
+type segmentWriter struct {
+	mu    sync.Mutex
+	dir   string
+	f     *os.File
+	w     *bufio.Writer
+	start int64 // millis of the first line in the active segment
+	last  int64 // millis of the latest line
+	size  int64
+}
+
+// NewLogFiles prepares opts.Dir, seals segments left active by a previous
+// run and starts the flush/retention loop.
+func NewLogFiles(log *zap.Logger, opts LogFilesOptions) (*LogFiles, error) {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	opts.setDefaults()
+
+	if err := os.MkdirAll(opts.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create log dir: %w", err)
+	}
+
+	lf := &LogFiles{
+		log:   log.Named("log-files"),
+		opts:  opts,
+		units: make(map[int64]*segmentWriter),
+		stop:  make(chan struct{}),
+	}
+	lf.sealLeftovers()
+
+	lf.stopped.Add(1)
+	go lf.loop()
+	return lf, nil
+}
+
+// sink returns the append hook of uid for its logBuffer.
+func (lf *LogFiles) sink(uid int64) func(at time.Time, entry string) {
+	return func(at time.Time, entry string) {
+		lf.write(uid, at, entry)
+	}
+}
+
+func (lf *LogFiles) write(uid int64, at time.Time, entry string) {
+	lf.mu.Lock()
+	if lf.closed {
+		lf.mu.Unlock()
+		return
+	}
+	sw, ok := lf.units[uid]
+	if !ok {
+		sw = &segmentWriter{dir: filepath.Join(lf.opts.Dir, strconv.FormatInt(uid, 10))}
+		lf.units[uid] = sw
+	}
+	lf.mu.Unlock()
+
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	ms := at.UnixMilli()
+	if sw.f != nil && (sw.size >= lf.opts.MaxSegmentBytes || ms-sw.start >= lf.opts.MaxSegmentAge.Milliseconds()) {
+		lf.sealUnsafe(sw)
+	}
+	if sw.f == nil {
+		if err := lf.openUnsafe(sw, ms); err != nil {
+			lf.log.Warn("open log segment failed", zap.Int64("uid", uid), zap.Error(err))
+			return
+		}
+	}
+
+	n, err := fmt.Fprintf(sw.w, "%d %s\n", ms, entry)
+	if err != nil {
+		lf.log.Warn("write log segment failed", zap.Int64("uid", uid), zap.Error(err))
+		return
+	}
+	sw.size += int64(n)
+	sw.last = ms
+}
+
+// openUnsafe starts a new active segment. Caller must hold sw.mu.
+func (lf *LogFiles) openUnsafe(sw *segmentWriter, start int64) error {
+	if err := os.MkdirAll(sw.dir, 0o750); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(sw.dir, fmt.Sprintf("%d.log", start)), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o640)
+	if err != nil {
+		return err
+	}
+	sw.f, sw.w = f, bufio.NewWriterSize(f, 64<<10)
+	sw.start, sw.last, sw.size = start, start, 0
+	return nil
+}
+
+// sealUnsafe closes the active segment, renames it to its final name and
+// compresses it in the background. Caller must hold sw.mu.
+func (lf *LogFiles) sealUnsafe(sw *segmentWriter) {
+	if sw.f == nil {
+		return
+	}
+	_ = sw.w.Flush()
+	_ = sw.f.Close()
+
+	active := filepath.Join(sw.dir, fmt.Sprintf("%d.log", sw.start))
+	sealed := filepath.Join(sw.dir, fmt.Sprintf("%d-%d.log", sw.start, sw.last))
+	sw.f, sw.w = nil, nil
+
+	if err := os.Rename(active, sealed); err != nil {
+		lf.log.Warn("seal log segment failed", zap.String("path", active), zap.Error(err))
+		return
+	}
+	lf.compressAsync(sealed)
+}
+
+// closeUnit seals the active segment of uid (unit removed). Files are kept
+// until retention removes them.
+func (lf *LogFiles) closeUnit(uid int64) {
+	lf.mu.Lock()
+	sw, ok := lf.units[uid]
+	delete(lf.units, uid)
+	lf.mu.Unlock()
+
+	if ok {
+		sw.mu.Lock()
+		lf.sealUnsafe(sw)
+		sw.mu.Unlock()
+	}
+}
+
+// Close flushes and seals all active segments and waits for pending
+// compressions. Call once on shutdown, after the process managers.
+func (lf *LogFiles) Close() {
+	close(lf.stop)
+
+	lf.mu.Lock()
+	lf.closed = true
+	units := lf.units
+	lf.units = make(map[int64]*segmentWriter)
+	lf.mu.Unlock()
+
+	for _, sw := range units {
+		sw.mu.Lock()
+		lf.sealUnsafe(sw)
+		sw.mu.Unlock()
+	}
+	lf.stopped.Wait()
+}
+
+// Read returns up to limit entries of uid logged within [since, until]
+// (newest → oldest; limit <= 0 means no limit).
+func (lf *LogFiles) Read(uid int64, since, until time.Time, limit int) ([]string, error) {
+	lf.mu.Lock()
+	sw := lf.units[uid]
+	lf.mu.Unlock()
+	if sw != nil {
+		sw.mu.Lock()
+		if sw.w != nil {
+			_ = sw.w.Flush()
+		}
+		sw.mu.Unlock()
+	}
+
+	segs, err := lf.segments(filepath.Join(lf.opts.Dir, strconv.FormatInt(uid, 10)))
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := since.UnixMilli(), until.UnixMilli()
+	sort.Slice(segs, func(i, j int) bool { return segs[i].start > segs[j].start }) // newest first
+
+	var out []string
+	for _, seg := range segs {
+		if seg.start > to || seg.end < from {
+			continue
+		}
+		lines, err := readSegment(seg.path, from, to)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue // sealed or compressed meanwhile; the new name is picked up next time
+			}
+			return nil, fmt.Errorf("read %s: %w", filepath.Base(seg.path), err)
+		}
+		for i := len(lines) - 1; i >= 0; i-- {
+			out = append(out, lines[i])
+			if limit > 0 && len(out) == limit {
+				return out, nil
+			}
+		}
+	}
+	return out, nil
+}
+
+// ----- background work ------------------------------------------------------
+
+func (lf *LogFiles) loop() {
+	defer lf.stopped.Done()
+
+	flush := time.NewTicker(time.Second)
+	defer flush.Stop()
+	janitor := time.NewTicker(time.Minute)
+	defer janitor.Stop()
+
+	lf.enforceRetention()
+	for {
+		select {
+		case <-lf.stop:
+			return
+		case <-flush.C:
+			lf.flushAll()
+		case <-janitor.C:
+			lf.enforceRetention()
+		}
+	}
+}
+
+func (lf *LogFiles) flushAll() {
+	lf.mu.Lock()
+	units := make([]*segmentWriter, 0, len(lf.units))
+	for _, sw := range lf.units {
+		units = append(units, sw)
+	}
+	lf.mu.Unlock()
+
+	maxAge := lf.opts.MaxSegmentAge.Milliseconds()
+	now := time.Now().UnixMilli()
+	for _, sw := range units {
+		sw.mu.Lock()
+		switch {
+		case sw.f == nil:
+		case now-sw.start >= maxAge:
+			lf.sealUnsafe(sw) // idle units rotate by age too
+		default:
+			if err := sw.w.Flush(); err != nil {
+				lf.log.Warn("flush log segment failed", zap.String("dir", sw.dir), zap.Error(err))
+			}
+		}
+		sw.mu.Unlock()
+	}
+}
+
+// enforceRetention deletes sealed segments past Retention, then the oldest
+// sealed segments (across all units) until the total size fits MaxTotalBytes.
+// Active segments count toward the total but are never deleted.
+func (lf *LogFiles) enforceRetention() {
+	dirs, err := os.ReadDir(lf.opts.Dir)
+	if err != nil {
+		lf.log.Warn("read log dir failed", zap.Error(err))
+		return
+	}
+
+	var (
+		sealed []segment
+		total  int64
+	)
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		dir := filepath.Join(lf.opts.Dir, d.Name())
+		segs, err := lf.segments(dir)
+		if err != nil {
+			lf.log.Warn("list log segments failed", zap.String("dir", dir), zap.Error(err))
+			continue
+		}
+		if len(segs) == 0 {
+			_ = os.Remove(dir) // only succeeds when empty
+			continue
+		}
+		for _, seg := range segs {
+			total += seg.size
+			if !seg.active {
+				sealed = append(sealed, seg)
+			}
+		}
+	}
+
+	sort.Slice(sealed, func(i, j int) bool { return sealed[i].end < sealed[j].end }) // oldest first
+
+	var cutoff int64 = math.MinInt64
+	if lf.opts.Retention > 0 {
+		cutoff = time.Now().Add(-lf.opts.Retention).UnixMilli()
+	}
+	for _, seg := range sealed {
+		if seg.end >= cutoff && total <= lf.opts.MaxTotalBytes {
+			break
+		}
+		if err := os.Remove(seg.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			lf.log.Warn("remove log segment failed", zap.String("path", seg.path), zap.Error(err))
+			continue
+		}
+		total -= seg.size
+	}
+}
+
+// sealLeftovers seals active segments written by a previous server run.
+func (lf *LogFiles) sealLeftovers() {
+	dirs, err := os.ReadDir(lf.opts.Dir)
+	if err != nil {
+		return
+	}
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		dir := filepath.Join(lf.opts.Dir, d.Name())
+		segs, err := lf.segments(dir)
+		if err != nil {
+			continue
+		}
+		for _, seg := range segs {
+			switch {
+			case seg.active:
+				info, err := os.Stat(seg.path)
+				if err != nil {
+					continue
+				}
+				sealed := filepath.Join(dir, fmt.Sprintf("%d-%d.log", seg.start, info.ModTime().UnixMilli()))
+				if err := os.Rename(seg.path, sealed); err == nil {
+					lf.compressAsync(sealed)
+				}
+			case !strings.HasSuffix(seg.path, ".gz"):
+				lf.compressAsync(seg.path) // compression interrupted by shutdown
+			}
+		}
+	}
+}
+
+func (lf *LogFiles) compressAsync(path string) {
+	lf.stopped.Add(1)
+	go func() {
+		defer lf.stopped.Done()
+		if err := gzipFile(path); err != nil {
+			lf.log.Warn("compress log segment failed", zap.String("path", path), zap.Error(err))
+		}
+	}()
+}
+
+// ----- segments -------------------------------------------------------------
+
+type segment struct {
+	path       string
+	start, end int64 // end is MaxInt64 for the active segment
+	size       int64
+	active     bool
+}
+
+// segments lists the segments of one unit directory (missing dir → none).
+func (lf *LogFiles) segments(dir string) ([]segment, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	names := make(map[string]struct{}, len(entries))
+	for _, e := range entries {
+		names[e.Name()] = struct{}{}
+	}
+
+	segs := make([]segment, 0, len(entries))
+	for _, e := range entries {
+		if _, ok := names[e.Name()+".gz"]; ok {
+			continue // compressed copy complete; original about to be removed
+		}
+		name := strings.TrimSuffix(e.Name(), ".gz")
+		base, ok := strings.CutSuffix(name, ".log")
+		if !ok {
+			continue // temp files etc.
+		}
+		seg := segment{path: filepath.Join(dir, e.Name()), end: math.MaxInt64}
+
+		startStr, endStr, sealed := strings.Cut(base, "-")
+		if seg.start, err = strconv.ParseInt(startStr, 10, 64); err != nil {
+			continue
+		}
+		if sealed {
+			if seg.end, err = strconv.ParseInt(endStr, 10, 64); err != nil {
+				continue
+			}
+		} else {
+			seg.active = true
+		}
+		if info, err := e.Info(); err == nil {
+			seg.size = info.Size()
+		}
+		segs = append(segs, seg)
+	}
+	return segs, nil
+}
+
+// readSegment returns the entries of a (possibly gzipped) segment logged
+// within [from, to], oldest → newest.
+func readSegment(path string, from, to int64) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var out []string
+	for sc.Scan() {
+		tsStr, entry, ok := strings.Cut(sc.Text(), " ")
+		if !ok {
+			continue
+		}
+		ts, err := strconv.ParseInt(tsStr, 10, 64)
+		if err != nil || ts < from {
+			continue
+		}
+		if ts > to {
+			break // lines are in time order
+		}
+		out = append(out, entry)
+	}
+	return out, sc.Err()
+}
+
+// gzipFile compresses path to path.gz (via a temp file) and removes path.
+func gzipFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := path + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o640)
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path+".gz"); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
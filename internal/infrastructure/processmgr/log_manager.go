@@ -1,19 +1,26 @@
 package processmgr
 
-import "sync"
+import (
+	"sync"
+	"time"
+)
 
 // LogManager manages per-process log buffers.
 // - Creates buffers lazily
 // - Thread-safe access
+// - Optionally mirrors every entry to disk (LogFiles)
 type LogManager struct {
-	mu   sync.RWMutex         // guards buf map
-	bufs map[int64]*logBuffer // PID → log buffer
+	mu    sync.RWMutex         // guards buf map
+	bufs  map[int64]*logBuffer // PID → log buffer
+	files *LogFiles            // optional on-disk sink
 }
 
 // NewLogManager initializes an empty log-buffer registry.
-func NewLogManager() *LogManager {
+// files may be nil (memory only).
+func NewLogManager(files *LogFiles) *LogManager {
 	return &LogManager{
-		bufs: make(map[int64]*logBuffer),
+		bufs:  make(map[int64]*logBuffer),
+		files: files,
 	}
 }
 
@@ -28,6 +35,40 @@ func (lm *LogManager) Get(pid int64) *logBuffer {
 	}
 
 	buf := new(logBuffer)
+	if lm.files != nil {
+		buf.sink = lm.files.sink(pid)
+	}
 	lm.bufs[pid] = buf
 	return buf
 }
+
+// ReadRange returns up to limit entries of pid logged within [since, until]
+// (newest → oldest). The in-memory buffer answers when it still covers the
+// window; older windows are read from disk. Without an on-disk sink only what
+// is left in memory is returned.
+func (lm *LogManager) ReadRange(pid int64, since, until time.Time, limit int) ([]string, error) {
+	entries, covered := lm.Get(pid).ReadRange(since, until, limit)
+	if covered {
+		return entries, nil
+	}
+	if lm.files == nil {
+		return entries, nil
+	}
+	return lm.files.Read(pid, since, until, limit)
+}
+
+// Remove drops the buffer of a PID and closes its live tails.
+// A process still draining its pipes keeps writing to the detached buffer.
+func (lm *LogManager) Remove(pid int64) {
+	lm.mu.Lock()
+	buf, ok := lm.bufs[pid]
+	delete(lm.bufs, pid)
+	lm.mu.Unlock()
+
+	if ok {
+		buf.close()
+	}
+	if lm.files != nil {
+		lm.files.closeUnit(pid)
+	}
+}
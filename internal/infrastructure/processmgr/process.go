@@ -8,13 +8,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
 
+	"github.com/edirooss/zmux-server/pkg/avurl"
 	"go.uber.org/zap"
 )
 
@@ -40,6 +43,14 @@ type process struct {
 	stderr io.ReadCloser
 	stdin  io.WriteCloser
 
+	// Detachable mode (registry != nil): stdio lives in registry files instead
+	// of pipes, so the child survives the server (see Registry).
+	reg     *Registry
+	uid     int64
+	digest  string // specDigest of the launch spec; recorded for adoption
+	stdio   *unitStdio
+	adopted bool // not our child: cannot Wait(); exit is detected by polling
+
 	// One-shot readiness signal (closed only on real readiness).
 	ready     chan struct{}
 	readyOnce sync.Once
@@ -52,46 +63,150 @@ type process struct {
 	started atomic.Bool
 	cmd_pid atomic.Int64
 
+	// Runtime introspection (see state).
+	startedAt atomic.Int64 // unix nanos; 0 when unknown
+	entered   atomic.Bool  // Enter() succeeded (or adopted past readiness)
+	closing   atomic.Bool  // Close() was called
+
+	// Exit metadata; written once by reap() before Done() fires.
+	exitCode   *int
+	exitSignal string
+
 	// Protects mutable state during lifecycle transitions.
 	mu sync.Mutex
 }
 
 // newProcess constructs a process wrapper around exec.Cmd.
 //
-// It performs early pipe allocation and applies Linux-specific attributes:
+// It performs early stdio allocation and applies Linux-specific attributes:
 //   - Setpgid: isolates the child into its own process group
 //   - Pdeathsig: ensures child receives SIGKILL if the parent dies
+//     (pipe mode only; with a Registry the child may outlive the server)
+//
+// With a nil Registry stdio are anonymous pipes (legacy mode). Otherwise
+// stdout+stderr go to a registry file and stdin is a registry FIFO.
 //
-// Returns (nil, false) on invalid parameters or pipe setup errors.
-func newProcess(log *zap.Logger, logBuf *logBuffer, env, argv []string) (*process, bool) {
+// The child environment is env followed by unitEnv (later entries win).
+//
+// Returns (nil, false) on invalid parameters or stdio setup errors.
+func newProcess(log *zap.Logger, logBuf *logBuffer, env, argv, unitEnv []string, reg *Registry, uid int64) (*process, bool) {
 	if log == nil || logBuf == nil || len(argv) == 0 {
 		log.Error("NewProcess: invalid parameters")
 		return nil, false
 	}
 
 	cmd := exec.Command(argv[0], argv[1:]...)
-	stdout, stderr, stdin, err := pipes(cmd)
+	cmd.Env = append(slices.Clip(env), unitEnv...)
+
+	p := &process{
+		log:    log,
+		logBuf: logBuf,
+		cmd:    cmd,
+		reg:    reg,
+		uid:    uid,
+		ready:  make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	if reg == nil {
+		stdout, stderr, stdin, err := pipes(cmd)
+		if err != nil {
+			log.Error("pipe initialization failure", zap.Error(err))
+			return nil, false
+		}
+		p.stdout, p.stderr, p.stdin = stdout, stderr, stdin
+
+		cmd.SysProcAttr = &syscall.SysProcAttr{
+			Setpgid:   true,
+			Pdeathsig: syscall.SIGKILL,
+		}
+		return p, true
+	}
+
+	stdio, err := reg.openStdio(uid)
 	if err != nil {
-		log.Error("pipe initialization failure", zap.Error(err))
+		log.Error("stdio initialization failure", zap.Error(err))
 		return nil, false
 	}
+	p.digest = reg.specDigest(argv, unitEnv)
+	p.stdio = stdio
+	p.stdin = stdio.in
 
-	cmd.Env = env
+	cmd.Stdout = stdio.outW
+	cmd.Stderr = stdio.outW
+	cmd.Stdin = stdio.in
 	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Setpgid:   true,
-		Pdeathsig: syscall.SIGKILL,
+		Setpgid: true,
 	}
+	return p, true
+}
 
-	return &process{
-		log:    log,
-		logBuf: logBuf,
-		cmd:    cmd,
-		stdout: stdout,
-		stderr: stderr,
-		stdin:  stdin,
-		ready:  make(chan struct{}),
-		done:   make(chan struct{}),
-	}, true
+// adoptProcess attaches to a child left running by a previous server instance.
+//
+// The returned process is already started and past readiness; Done() fires
+// when the OS process disappears. Exit status is not observable (the child
+// was re-parented and is reaped by init).
+func adoptProcess(log *zap.Logger, logBuf *logBuffer, reg *Registry, rec unitRecord) (*process, bool) {
+	stdio, err := reg.reopenStdio(rec.UID)
+	if err != nil {
+		log.Warn("adoption stdio failure", zap.Error(err))
+		return nil, false
+	}
+
+	p := &process{
+		log:     log,
+		logBuf:  logBuf,
+		reg:     reg,
+		uid:     rec.UID,
+		stdio:   stdio,
+		stdin:   stdio.in,
+		adopted: true,
+		ready:   make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	p.startOnce.Do(func() {}) // Start() is a no-op for adopted processes
+	p.readyOnce.Do(func() { close(p.ready) })
+	p.started.Store(true)
+	p.cmd_pid.Store(int64(rec.PID))
+	p.entered.Store(rec.Entered)
+	if at := procStartTime(rec.StartTicks); !at.IsZero() {
+		p.startedAt.Store(at.UnixNano())
+	}
+
+	p.log.Info("process adopted", zap.Int("cmd_pid", rec.PID))
+	go p.superviseDetachable(rec)
+	return p, true
+}
+
+// evictProcess stands in for a recorded child that cannot be adopted (its
+// stdio is gone): it terminates the process group, and Done() fires once the
+// group is gone and the record removed. Close() is a no-op.
+func evictProcess(log *zap.Logger, logBuf *logBuffer, reg *Registry, rec unitRecord) *process {
+	p := &process{
+		log:     log,
+		logBuf:  logBuf,
+		reg:     reg,
+		uid:     rec.UID,
+		adopted: true,
+		ready:   make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	p.startOnce.Do(func() {})
+	p.closeOnce.Do(func() {})
+	p.started.Store(true)
+	p.closing.Store(true)
+	p.cmd_pid.Store(int64(rec.PID))
+
+	p.log.Info("terminating unadoptable process", zap.Int("cmd_pid", rec.PID))
+	go func() {
+		terminateGroup(log, rec)
+		for rec.alive() {
+			time.Sleep(100 * time.Millisecond)
+		}
+		reg.forget(rec.UID, rec.PID)
+		close(p.done)
+	}()
+	return p
 }
 
 // Start launches the command exactly once. On success:
@@ -116,9 +231,25 @@ func (p *process) Start() bool {
 		ok = true
 		p.started.Store(true)
 		p.cmd_pid.Store(int64(pid))
+		p.startedAt.Store(time.Now().UnixNano())
 
 		p.log.Info("process started", zap.Int("cmd_pid", pid))
-		go p.supervise()
+
+		if p.reg == nil {
+			go p.supervise()
+			return
+		}
+
+		// The child holds its own copy of the output fd.
+		_ = p.stdio.outW.Close()
+		p.stdio.outW = nil
+
+		rec := unitRecord{UID: p.uid, PID: pid, ArgvDigest: p.digest}
+		rec.StartTicks, _ = procStartTicks(pid)
+		if err := p.reg.save(rec); err != nil {
+			p.log.Warn("failed to record process; it will not be adoptable", zap.Error(err))
+		}
+		go p.superviseDetachable(rec)
 	})
 
 	return ok
@@ -193,9 +324,66 @@ func (p *process) supervise() {
 	}
 
 	// Reap the child once and record exit metadata.
+	p.reap()
+
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	// Final stdin cleanup.
+	if p.stdin != nil {
+		_ = p.stdin.Close()
+		p.stdin = nil
+	}
+
+	close(p.done)
+}
+
+// superviseDetachable is supervise() for registry-backed stdio:
+//
+//   - tails the output file (readiness + log buffer) until the child is gone
+//   - own children are reaped with Wait(); adopted ones are polled via /proc
+//   - removes the registry record once the child has exited
+func (p *process) superviseDetachable(rec unitRecord) {
+	stop := make(chan struct{})
+	tailDone := make(chan struct{})
+	go func() {
+		p.scanStdout(&follower{f: p.stdio.outR, stop: stop, maxSize: maxOutputFileSize})
+		close(tailDone)
+	}()
+
+	if p.adopted {
+		for rec.alive() {
+			time.Sleep(500 * time.Millisecond)
+		}
+		p.log.Info("adopted process exited")
+	} else {
+		p.reap()
+	}
+
+	close(stop)
+	<-tailDone
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.reg.forget(p.uid, rec.PID)
+	_ = p.stdio.outR.Close()
+	if p.stdin != nil {
+		_ = p.stdin.Close()
+		p.stdin = nil
+	}
+
+	close(p.done)
+}
+
+// reap waits for the child exactly once and records exit metadata.
+// Must be called without p.mu: Wait blocks for the child's lifetime, and
+// Enter needs the lock meanwhile.
+func (p *process) reap() {
+	var (
+		code   *int
+		signal string
+	)
 	if err := p.cmd.Wait(); err != nil {
 		var eerr *exec.ExitError
 		if errors.As(err, &eerr) {
@@ -204,26 +392,76 @@ func (p *process) supervise() {
 				zap.Int("exit_code", status.ExitStatus()),
 				zap.Bool("signaled", status.Signaled()),
 				zap.String("signal", status.Signal().String()))
+			if status.Signaled() {
+				signal = status.Signal().String()
+			} else {
+				c := status.ExitStatus()
+				code = &c
+			}
 		} else {
 			p.log.Error("failed to wait for process", zap.Error(err))
 		}
 	} else {
 		p.log.Info("process exited cleanly")
+		c := 0
+		code = &c
 	}
 
-	// Final stdin cleanup.
-	if p.stdin != nil {
-		_ = p.stdin.Close()
-		p.stdin = nil
+	p.mu.Lock()
+	p.exitCode, p.exitSignal = code, signal
+	p.mu.Unlock()
+}
+
+// exitStatus returns the recorded exit code/signal. Valid after Done().
+func (p *process) exitStatus() (code *int, signal string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.exitCode, p.exitSignal
+}
+
+// cmdPID returns the OS pid (0 before Start).
+func (p *process) cmdPID() int { return int(p.cmd_pid.Load()) }
+
+// startTime returns when the child was started (zero when unknown).
+func (p *process) startTime() time.Time {
+	if ns := p.startedAt.Load(); ns != 0 {
+		return time.Unix(0, ns)
 	}
+	return time.Time{}
+}
 
-	close(p.done)
+// state maps the lifecycle position of a launched process to a UnitState.
+// gated selects PM2 semantics (readiness barrier + Enter).
+func (p *process) state(gated bool) UnitState {
+	select {
+	case <-p.done:
+		return StateExiting // reaped; exit handler pending
+	default:
+	}
+	if p.closing.Load() {
+		return StateExiting
+	}
+	if !gated || p.entered.Load() {
+		return StateActive
+	}
+	select {
+	case <-p.ready:
+		return StateStarting
+	default:
+		return StatePreflight
+	}
 }
 
 // handleStdout streams stdout, detects readiness markers, and appends all
-// lines into the shared log buffer. Scanner I/O failures are logged.
+// lines into the shared log buffer with URL credentials scrubbed. Scanner I/O
+// failures are logged.
 func (p *process) handleStdout() {
-	sc := bufio.NewScanner(p.stdout)
+	p.scanStdout(p.stdout)
+}
+
+// scanStdout is handleStdout over an arbitrary reader (pipe or tailed file).
+func (p *process) scanStdout(r io.Reader) {
+	sc := bufio.NewScanner(r)
 	sc.Buffer(make([]byte, 64*1024), 1024*1024)
 
 	const readiness = "Press ENTER to continue or Ctrl+C to cancel."
@@ -235,7 +473,7 @@ func (p *process) handleStdout() {
 			p.log.Info("readiness signal received")
 			continue
 		}
-		p.logBuf.Append(line)
+		p.logBuf.Append(avurl.ScrubUserinfo(line))
 	}
 
 	if err := sc.Err(); err != nil {
@@ -243,14 +481,15 @@ func (p *process) handleStdout() {
 	}
 }
 
-// handleStderr streams stderr directly into the shared log buffer and logs
-// scanner failures. No readiness semantics are associated with stderr.
+// handleStderr streams stderr into the shared log buffer (URL credentials
+// scrubbed) and logs scanner failures. No readiness semantics are associated
+// with stderr.
 func (p *process) handleStderr() {
 	sc := bufio.NewScanner(p.stderr)
 	sc.Buffer(make([]byte, 64*1024), 1024*1024)
 
 	for sc.Scan() {
-		p.logBuf.Append(sc.Text())
+		p.logBuf.Append(avurl.ScrubUserinfo(sc.Text()))
 	}
 
 	if err := sc.Err(); err != nil {
@@ -286,6 +525,11 @@ func (p *process) Enter() error {
 	}
 
 	p.log.Info("ENTER written to stdin")
+	p.entered.Store(true)
+
+	if p.reg != nil {
+		p.reg.markEntered(p.uid, int(p.cmd_pid.Load()))
+	}
 	return nil
 }
 
@@ -300,6 +544,7 @@ func (p *process) Done() <-chan struct{}  { return p.done }
 //
 // Close() is idempotent and concurrency-safe.
 func (p *process) Close() {
+	p.closing.Store(true)
 	p.closeOnce.Do(func() {
 		go func() {
 			if !p.started.Load() {
@@ -378,3 +623,46 @@ func pipes(cmd *exec.Cmd) (io.ReadCloser, io.ReadCloser, io.WriteCloser, error)
 
 	return stdout, stderr, stdin, nil
 }
+
+// maxOutputFileSize caps a registry output file; see follower.
+const maxOutputFileSize = 8 << 20 // 8MiB
+
+// follower is an io.Reader that tails a growing file ("tail -f").
+//
+// At EOF it polls for new data until stop is closed, then drains once more
+// and reports io.EOF. Once fully consumed past maxSize, the file is truncated
+// in place; the child writes with O_APPEND and continues at the new end.
+// Lines written between the last read and the truncate are lost (rare; logs only).
+type follower struct {
+	f       *os.File
+	stop    <-chan struct{}
+	maxSize int64
+	stopped bool
+}
+
+func (r *follower) Read(b []byte) (int, error) {
+	for {
+		n, err := r.f.Read(b)
+		if n > 0 {
+			return n, nil
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		if r.stopped {
+			return 0, io.EOF
+		}
+
+		if off, err := r.f.Seek(0, io.SeekCurrent); err == nil && off >= r.maxSize {
+			if err := r.f.Truncate(0); err == nil {
+				_, _ = r.f.Seek(0, io.SeekStart)
+			}
+		}
+
+		select {
+		case <-r.stop:
+			r.stopped = true // one more pass to drain what is left
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
@@ -3,7 +3,9 @@
 package processmgr
 
 import (
+	"context"
 	"os"
+	"slices"
 	"sync"
 	"time"
 
@@ -20,7 +22,7 @@ import (
 //   - a PID is the authoritative instance of that unit
 //   - PIDs are not reused until explicitly released
 
-//   - specs[pid] describes how to launch the process (argv + restart policy)
+//   - specs[pid] describes how to launch the process (argv + env + restart policy)
 //   - ps[pid] is the live running process (if any)
 //   - sched is a priority-queue of future launch/restart events keyed by PID
 //
@@ -30,18 +32,23 @@ import (
 //     only under the lock.
 //   - Event loop wakes on expiration or explicit signals (self-pokes).
 type ProcessManager struct {
-	log    *zap.Logger
-	logmgr *LogManager // per-unit aggregated logs (fan-in sink)
-	env    []string    // global environment overlay for all units
-
-	units map[int64]int64    // UID → PID (authoritative mapping)
-	specs map[int64]execSpec // PID → execSpec (argv + restart policy)
-	ps    map[int64]*process // PID → running process
-	gen   *PIDAllocator      // monotonic PID allocator
+	log     *zap.Logger
+	logmgr  *LogManager  // per-unit aggregated logs (fan-in sink)
+	reg     *Registry    // optional; enables detachable children + adoption
+	onEvent EventHandler // optional; lifecycle notifications
+	env     []string     // global environment overlay for all units
+
+	units map[int64]int64      // UID → PID (authoritative mapping)
+	specs map[int64]execSpec   // PID → execSpec (argv + env + restart policy)
+	ps    map[int64]*process   // PID → running process
+	stats map[int64]*unitStats // PID → launch/exit history (Runtime)
+	gen   *PIDAllocator        // monotonic PID allocator
 
 	sched *scheduler    // priority queue: next processes to launch
 	sig   chan struct{} // one-deep wake-up nudge for event loop
 
+	closed bool // set by Shutdown; no further launches/restarts
+
 	mu sync.Mutex // guards all state transitions
 }
 
@@ -50,16 +57,22 @@ type ProcessManager struct {
 //
 // The event loop is intentionally detached: it reacts to timing signals
 // and launch/teardown events sent via m.sig.
-func NewProcessManager(log *zap.Logger, logmngr *LogManager) *ProcessManager {
+//
+// reg may be nil (children die with the server; no adoption).
+// onEvent may be nil (no lifecycle notifications).
+func NewProcessManager(log *zap.Logger, logmngr *LogManager, reg *Registry, onEvent EventHandler) *ProcessManager {
 	m := &ProcessManager{
-		log:    log.Named("process-manager"),
-		logmgr: logmngr,
+		log:     log.Named("process-manager"),
+		logmgr:  logmngr,
+		reg:     reg,
+		onEvent: onEvent,
 
 		env: append(os.Environ(), "ENV=prod"), // override-mode overlay
 
 		units: make(map[int64]int64),
 		specs: make(map[int64]execSpec),
 		ps:    make(map[int64]*process),
+		stats: make(map[int64]*unitStats),
 		gen:   newPIDAllocator(),
 
 		sched: newScheduler(),
@@ -82,10 +95,18 @@ func NewProcessManager(log *zap.Logger, logmngr *LogManager) *ProcessManager {
 //   - All future restarts refer strictly to the PID.
 //
 // This avoids race conditions where a unit is replaced while a restart is pending.
-func (m *ProcessManager) Add(uid int64, argv []string, cooldown time.Duration) {
+//
+// With a Registry, a child left running by a previous server instance with the
+// identical argv and env is adopted instead of launching a duplicate. Only the
+// first Add of a UID since startup adopts; after a Remove the unit always
+// launches fresh.
+func (m *ProcessManager) Add(uid int64, argv, env []string, policy RestartPolicy) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if m.closed {
+		return
+	}
 	if _, exists := m.units[uid]; exists {
 		// already known → intentionally ignored
 		return
@@ -94,15 +115,108 @@ func (m *ProcessManager) Add(uid int64, argv []string, cooldown time.Duration) {
 	pid := m.gen.alloc()
 	m.units[uid] = pid
 	m.specs[pid] = execSpec{
-		unitID:          uid,
-		argv:            argv,
-		restartCooldown: cooldown,
+		unitID: uid,
+		argv:   argv,
+		env:    env,
+		policy: policy,
+	}
+	m.stats[pid] = &unitStats{}
+
+	if m.reg != nil && m.reg.claim(uid) {
+		plog := m.log.With(zap.Int64("uid", uid), zap.Int64("pid", pid))
+		if proc, adopted := adoptOrEvict(plog, m.logmgr.Get(uid), m.reg, uid, argv, env, false); proc != nil {
+			// adopted (or evicting a stale instance) → exit handler restarts as usual
+			m.ps[pid] = proc
+			if adopted {
+				m.stats[pid].launches++
+				m.onEvent.emit(Event{UID: uid, Kind: EventAdopted, CmdPID: proc.cmdPID()})
+			} else {
+				m.stats[pid].relaunch = true // evicted, not failed
+			}
+			go m.watchExit(pid, uid, proc)
+			return
+		}
 	}
 
 	// schedule first launch immediately
 	m.scheduleUnsafe(pid, 0)
 }
 
+// Replace updates the spec of a unit.
+//
+//   - Identical argv and env → the running child is kept; only the restart policy
+//     is updated in place (used from the next exit on).
+//   - Different argv or env → the unit is swapped to a fresh PID (history
+//     reset): the running child is closed, and the new spec is launched once it
+//     has exited. A recorded child is never adopted here.
+//   - Unknown UID → added.
+//
+// It reports whether the unit was (re)launched.
+func (m *ProcessManager) Replace(uid int64, argv, env []string, policy RestartPolicy) bool {
+	m.mu.Lock()
+	pid, ok := m.units[uid]
+	if !ok {
+		m.mu.Unlock()
+		m.Add(uid, argv, env, policy)
+		return true
+	}
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return false
+	}
+	if slices.Equal(m.specs[pid].argv, argv) && slices.Equal(m.specs[pid].env, env) {
+		spec := m.specs[pid]
+		spec.policy = policy
+		m.specs[pid] = spec
+		return false
+	}
+
+	old := m.removeUnsafe(uid, pid)
+
+	pid = m.gen.alloc()
+	m.units[uid] = pid
+	m.specs[pid] = execSpec{
+		unitID: uid,
+		argv:   argv,
+		env:    env,
+		policy: policy,
+	}
+	m.stats[pid] = &unitStats{}
+
+	if old == nil {
+		m.scheduleUnsafe(pid, 0)
+		return true
+	}
+	m.ps[pid] = old // shows as exiting until it is gone
+	go m.launchAfter(pid, uid, old)
+	return true
+}
+
+// launchAfter launches PID (a unit swapped by Replace) once old, the closing
+// child of its previous registration, has exited. Stop/Start/Restart in the
+// meantime apply to PID as if old were its own child.
+func (m *ProcessManager) launchAfter(pid, uid int64, old *process) {
+	<-old.Done()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ps[pid] == old {
+		delete(m.ps, pid)
+	}
+	if current, exists := m.units[uid]; !exists || current != pid {
+		m.gen.release(pid) // removed meanwhile; never launched
+		return
+	}
+	st := m.stats[pid]
+	st.relaunch = false
+	if m.closed || st.held {
+		return
+	}
+	m.scheduleUnsafe(pid, 0)
+}
+
 // Remove unregisters a unit and tears down any running instance.
 //
 // Removal behavior:
@@ -123,8 +237,20 @@ func (m *ProcessManager) Remove(uid int64) {
 		return // unknown uid
 	}
 
+	m.removeUnsafe(uid, pid)
+
+	if m.reg != nil {
+		m.reg.unclaim(uid)
+	}
+}
+
+// removeUnsafe closes the running child of PID (if any) and drops the unit's
+// mappings and scheduled restarts. It returns the closing child (nil if none).
+// Caller must hold m.mu.
+func (m *ProcessManager) removeUnsafe(uid, pid int64) *process {
 	// if running, ask the process to shut down
-	if proc, live := m.ps[pid]; live {
+	proc, live := m.ps[pid]
+	if live {
 		proc.Close()
 	}
 
@@ -132,7 +258,131 @@ func (m *ProcessManager) Remove(uid int64) {
 	delete(m.units, uid)
 	delete(m.specs, pid)
 	delete(m.ps, pid) // if not already removed by exit handler
+	delete(m.stats, pid)
+	m.sched.remove(pid)
+	return proc
+}
+
+// Runtime returns a snapshot of the unit's supervisor state.
+// ok is false for unknown UIDs.
+func (m *ProcessManager) Runtime(uid int64) (rt UnitRuntime, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pid, ok := m.units[uid]
+	if !ok {
+		return UnitRuntime{}, false
+	}
+	next, queued := m.sched.when(pid)
+	return snapshot(uid, m.stats[pid], m.ps[pid], next, queued, false, false, m.closed), true
+}
+
+// Restart gracefully relaunches a unit: a running child is closed
+// (SIGTERM → grace → SIGKILL) and relaunched as soon as it has exited,
+// without counting as a failure; a unit in backoff, failed or stopped is
+// launched immediately. The restart policy starts over.
+// It reports false for unknown UIDs.
+func (m *ProcessManager) Restart(uid int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pid, ok := m.units[uid]
+	if !ok || m.closed {
+		return false
+	}
+	st := m.stats[pid]
+	st.held = false
+	st.resetFailed()
+
+	if proc := m.ps[pid]; proc != nil {
+		st.relaunch = true
+		proc.Close()
+		return true
+	}
+	m.scheduleUnsafe(pid, 0)
+	return true
+}
+
+// Stop closes the running child of a unit (if any) and holds the unit until
+// Start or Restart; it stays registered. It reports false for unknown UIDs.
+func (m *ProcessManager) Stop(uid int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pid, ok := m.units[uid]
+	if !ok || m.closed {
+		return false
+	}
+	st := m.stats[pid]
+	st.held, st.relaunch = true, false
 	m.sched.remove(pid)
+
+	if proc := m.ps[pid]; proc != nil {
+		proc.Close()
+	}
+	return true
+}
+
+// Start releases a unit held by Stop (or parked by the crash-loop breaker)
+// and launches it immediately. A running or queued unit is left alone.
+// It reports false for unknown UIDs.
+func (m *ProcessManager) Start(uid int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pid, ok := m.units[uid]
+	if !ok || m.closed {
+		return false
+	}
+	st := m.stats[pid]
+	if !st.held && !st.failed {
+		return true
+	}
+	st.held = false
+	st.resetFailed()
+
+	if m.ps[pid] != nil {
+		st.relaunch = true // still exiting → relaunch once it is gone
+		return true
+	}
+	m.scheduleUnsafe(pid, 0)
+	return true
+}
+
+// ResetFailed clears the crash-loop breaker of a failed unit and schedules
+// an immediate launch. It reports false for unknown or non-failed units.
+func (m *ProcessManager) ResetFailed(uid int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pid, ok := m.units[uid]
+	if !ok || m.closed || !m.stats[pid].failed {
+		return false
+	}
+	m.stats[pid].resetFailed()
+	m.scheduleUnsafe(pid, 0)
+	return true
+}
+
+// Shutdown stops scheduling and disposes of running children per mode.
+//
+//   - StopChildren:   every child is closed (SIGTERM → grace → SIGKILL);
+//     returns once all have exited or ctx is done.
+//   - DetachChildren: children are left running for the next server
+//     instance to adopt (requires a Registry).
+//
+// After Shutdown the manager ignores Add and never restarts anything.
+func (m *ProcessManager) Shutdown(ctx context.Context, mode ShutdownMode) {
+	m.mu.Lock()
+	m.closed = true
+	procs := make([]*process, 0, len(m.ps))
+	for _, proc := range m.ps {
+		procs = append(procs, proc)
+	}
+	m.mu.Unlock()
+
+	m.poke()
+	stopProcesses(ctx, procs, mode)
 }
 
 // mainloop drives the scheduling engine.
@@ -150,6 +400,10 @@ func (m *ProcessManager) mainloop() {
 
 	for {
 		m.mu.Lock()
+		if m.closed {
+			m.mu.Unlock()
+			return
+		}
 		pid, when, ok := m.sched.next()
 
 		if !ok {
@@ -201,6 +455,7 @@ func (m *ProcessManager) mainloop() {
 //     release PID back to allocator
 func (m *ProcessManager) launchProcessUnsafe(pid int64) {
 	spec := m.specs[pid] // must exist (manager invariant)
+	m.stats[pid].launches++
 
 	// pre-scoped logger
 	plog := m.log.With(
@@ -209,13 +464,13 @@ func (m *ProcessManager) launchProcessUnsafe(pid int64) {
 	)
 
 	// construct process object (pipes + watchers)
-	proc, ok := newProcess(plog, m.logmgr.Get(spec.unitID), m.env, spec.argv)
+	proc, ok := newProcess(plog, m.logmgr.Get(spec.unitID), m.env, spec.argv, spec.env, m.reg, spec.unitID)
 	if !ok {
 		// construction failed → schedule retry
 		m.log.Warn("process initialization failed; scheduling retry",
 			zap.Int64("uid", spec.unitID), zap.Int64("pid", pid))
 
-		m.scheduleUnsafe(pid, spec.restartCooldown)
+		m.retryUnsafe(pid, spec, Event{UID: spec.unitID, Kind: EventStartFailed}, time.Time{})
 		return
 	}
 	m.ps[pid] = proc // mark as running
@@ -226,45 +481,89 @@ func (m *ProcessManager) launchProcessUnsafe(pid int64) {
 			zap.Int64("uid", spec.unitID), zap.Int64("pid", pid))
 
 		delete(m.ps, pid)
-		m.scheduleUnsafe(pid, spec.restartCooldown)
+		m.retryUnsafe(pid, spec, Event{UID: spec.unitID, Kind: EventStartFailed}, time.Time{})
 		return
 	}
+	m.onEvent.emit(Event{UID: spec.unitID, Kind: EventStarted, CmdPID: proc.cmdPID()})
 
 	// attach background exit handler
-	go func(pid int64, uid int64, spec execSpec, proc *process) {
-		<-proc.Done() // wait for full shutdown
+	go m.watchExit(pid, spec.unitID, proc)
+}
 
-		m.mu.Lock()
-		defer m.mu.Unlock()
+// watchExit is the background exit handler of a launched or adopted process.
+func (m *ProcessManager) watchExit(pid int64, uid int64, proc *process) {
+	<-proc.Done() // wait for full shutdown
 
-		delete(m.ps, pid)
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-		current, exists := m.units[uid]
-		if exists && current == pid {
-			// PID is still the authoritative instance for this unit → restart it
-			m.log.Info("process exited; scheduling restart",
-				zap.Int64("uid", uid),
-				zap.Int64("pid", pid),
-			)
-			m.scheduleUnsafe(pid, spec.restartCooldown)
-			return
-		}
+	delete(m.ps, pid)
+
+	ev := Event{UID: uid, Kind: EventExited, At: time.Now(), CmdPID: proc.cmdPID()}
+	ev.ExitCode, ev.ExitSignal = proc.exitStatus()
+	if st := m.stats[pid]; st != nil {
+		st.exited(ev)
+	}
 
-		// PID no longer authoritative:
-		//   • either the unit was removed
-		//   • or the unit was removed and later re-added, producing a new PID
-		// In both cases this PID must not restart and should be released.
-		m.log.Debug("process exited; PID no longer authoritative → releasing",
-			zap.Int64("uid", uid),
-			zap.Int64("pid", pid),
-		)
-		m.gen.release(pid)
-
-	}(pid, spec.unitID, spec, proc)
+	current, exists := m.units[uid]
+	if exists && current == pid && !m.closed {
+		// PID is still the authoritative instance for this unit → restart it
+		// (the spec may have been updated in place since launch)
+		m.retryUnsafe(pid, m.specs[pid], ev, proc.startTime())
+		return
+	}
+	m.onEvent.emit(ev)
+
+	// PID no longer authoritative:
+	//   • either the unit was removed
+	//   • or the unit was removed and later re-added, producing a new PID
+	//   • or the manager is shutting down
+	// In all cases this PID must not restart and should be released.
+	m.log.Debug("process exited; PID no longer authoritative → releasing",
+		zap.Int64("uid", uid),
+		zap.Int64("pid", pid),
+	)
+	m.gen.release(pid)
 }
 
 // --- sched helper -----------------------------------------------------------
 
+// retryUnsafe decides what follows a run of PID that ended (exit or failed
+// start) and emits ev (EventExited or EventStartFailed) completed with the
+// outcome: nothing (stopped on request), an immediate relaunch (restarted on
+// request), a relaunch per the restart policy, or the crash-loop breaker
+// parks the unit.
+// Caller must hold m.mu.
+func (m *ProcessManager) retryUnsafe(pid int64, spec execSpec, ev Event, startedAt time.Time) {
+	st := m.stats[pid]
+	switch {
+	case st.held:
+		m.onEvent.emit(ev) // stopped on request
+		return
+	case st.relaunch:
+		st.relaunch = false // restarted on request → not a failure
+		m.scheduleUnsafe(pid, 0)
+		ev.Restarting = true
+		m.onEvent.emit(ev)
+		return
+	}
+
+	delay, failed := st.failure(spec.policy, startedAt)
+	if failed {
+		m.log.Warn("process keeps failing; crash-loop breaker tripped",
+			zap.Int64("uid", spec.unitID), zap.Int64("pid", pid))
+		m.onEvent.emit(ev)
+		m.onEvent.emit(Event{UID: spec.unitID, Kind: EventFailed})
+		return
+	}
+
+	m.log.Info("scheduling restart",
+		zap.Int64("uid", spec.unitID), zap.Int64("pid", pid), zap.Duration("delay", delay))
+	m.scheduleUnsafe(pid, delay)
+	ev.RestartIn, ev.Restarting = delay, true
+	m.onEvent.emit(ev)
+}
+
 // scheduleUnsafe queues PID for future launch after a given delay.
 // Caller must hold m.mu.
 //
@@ -274,7 +573,11 @@ func (m *ProcessManager) launchProcessUnsafe(pid int64) {
 // This ensures no goroutine ever blocks while attempting to wake the scheduler.
 func (m *ProcessManager) scheduleUnsafe(pid int64, after time.Duration) {
 	m.sched.push(pid, time.Now().Add(after))
+	m.poke()
+}
 
+// poke wakes the event loop without blocking.
+func (m *ProcessManager) poke() {
 	select {
 	case m.sig <- struct{}{}:
 	default:
@@ -288,9 +591,10 @@ func (m *ProcessManager) scheduleUnsafe(pid int64, after time.Duration) {
 //
 // All restarts for a PID share the same spec.
 type execSpec struct {
-	unitID          int64
-	argv            []string
-	restartCooldown time.Duration
+	unitID int64
+	argv   []string
+	env    []string // per-unit environment, appended to the manager's
+	policy RestartPolicy
 }
 
 // --- timer helper -----------------------------------------------------------
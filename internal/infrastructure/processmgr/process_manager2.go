@@ -3,7 +3,9 @@
 package processmgr
 
 import (
+	"context"
 	"os"
+	"slices"
 	"sync"
 	"time"
 
@@ -60,14 +62,17 @@ import (
 // authoritative PID semantics, and restart logic — but introduces a controlled
 // warm-up stage and active stage with enforced concurrency limits.
 type ProcessManager2 struct {
-	log    *zap.Logger
-	logmgr *LogManager
-	env    []string
+	log     *zap.Logger
+	logmgr  *LogManager
+	reg     *Registry    // optional; enables detachable children + adoption
+	onEvent EventHandler // optional; lifecycle notifications
+	env     []string
 
 	// Authoritative tables
-	units map[int64]int64    // UID → PID
-	specs map[int64]execSpec // PID → static exec spec
-	ps    map[int64]*process // PID → running process (if any)
+	units map[int64]int64      // UID → PID
+	specs map[int64]execSpec   // PID → static exec spec
+	ps    map[int64]*process   // PID → running process (if any)
+	stats map[int64]*unitStats // PID → launch/exit history (Runtime)
 	gen   *PIDAllocator
 
 	// Concurrency gates
@@ -78,6 +83,8 @@ type ProcessManager2 struct {
 	sched *scheduler
 	sig   chan struct{}
 
+	closed bool // set by Shutdown; no further launches/restarts
+
 	mu sync.Mutex
 }
 
@@ -85,20 +92,27 @@ type ProcessManager2 struct {
 //
 // maxPreflight – max warming/booting processes allowed
 // maxOnflight  – max active processes allowed
+// reg          – optional Registry (nil: children die with the server)
+// onEvent      – optional lifecycle notifications
 func NewProcessManager2(
 	log *zap.Logger,
 	logmngr *LogManager,
+	reg *Registry,
+	onEvent EventHandler,
 	maxPreflight, maxOnflight int64,
 ) *ProcessManager2 {
 
 	m := &ProcessManager2{
-		log:    log.Named("process-manager2"),
-		logmgr: logmngr,
-		env:    append(os.Environ(), "ENV=prod"),
+		log:     log.Named("process-manager2"),
+		logmgr:  logmngr,
+		reg:     reg,
+		onEvent: onEvent,
+		env:     append(os.Environ(), "ENV=prod"),
 
 		units: make(map[int64]int64),
 		specs: make(map[int64]execSpec),
 		ps:    make(map[int64]*process),
+		stats: make(map[int64]*unitStats),
 		gen:   newPIDAllocator(),
 
 		preflight: newSlotPool(maxPreflight),
@@ -114,10 +128,18 @@ func NewProcessManager2(
 
 // Add registers a new unit, allocates a PID, stores its spec, and schedules an
 // immediate launch.
-func (m *ProcessManager2) Add(uid int64, argv []string, cooldown time.Duration) {
+//
+// With a Registry, an active child left running by a previous server instance
+// with the identical argv and env is adopted straight into the onflight phase.
+// Only the first Add of a UID since startup adopts; after a Remove the unit
+// always launches fresh.
+func (m *ProcessManager2) Add(uid int64, argv, env []string, policy RestartPolicy) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if m.closed {
+		return
+	}
 	if _, exists := m.units[uid]; exists {
 		return // already registered → ignore
 	}
@@ -126,14 +148,105 @@ func (m *ProcessManager2) Add(uid int64, argv []string, cooldown time.Duration)
 
 	m.units[uid] = pid
 	m.specs[pid] = execSpec{
-		unitID:          uid,
-		argv:            argv,
-		restartCooldown: cooldown,
+		unitID: uid,
+		argv:   argv,
+		env:    env,
+		policy: policy,
+	}
+	m.stats[pid] = &unitStats{}
+
+	if m.reg != nil && m.reg.claim(uid) {
+		plog := m.log.With(zap.Int64("uid", uid), zap.Int64("pid", pid))
+		if proc, adopted := adoptOrEvict(plog, m.logmgr.Get(uid), m.reg, uid, argv, env, true); proc != nil {
+			m.ps[pid] = proc
+			if adopted {
+				m.stats[pid].launches++
+			} else {
+				m.stats[pid].relaunch = true // evicted, not failed
+			}
+			go m.superviseAdopted(pid, m.specs[pid], proc, adopted)
+			return
+		}
 	}
 
 	m.scheduleUnsafe(pid, 0)
 }
 
+// Replace updates the spec of a unit.
+//
+//   - Identical argv and env → the running child is kept; only the restart policy
+//     is updated in place (used from the next exit on).
+//   - Different argv or env → the unit is swapped to a fresh PID (history
+//     reset): the running child is closed, and the new spec is launched once it
+//     has exited. A recorded child is never adopted here.
+//   - Unknown UID → added.
+//
+// It reports whether the unit was (re)launched.
+func (m *ProcessManager2) Replace(uid int64, argv, env []string, policy RestartPolicy) bool {
+	m.mu.Lock()
+	pid, ok := m.units[uid]
+	if !ok {
+		m.mu.Unlock()
+		m.Add(uid, argv, env, policy)
+		return true
+	}
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return false
+	}
+	if slices.Equal(m.specs[pid].argv, argv) && slices.Equal(m.specs[pid].env, env) {
+		spec := m.specs[pid]
+		spec.policy = policy
+		m.specs[pid] = spec
+		return false
+	}
+
+	old := m.removeUnsafe(uid, pid)
+
+	pid = m.gen.alloc()
+	m.units[uid] = pid
+	m.specs[pid] = execSpec{
+		unitID: uid,
+		argv:   argv,
+		env:    env,
+		policy: policy,
+	}
+	m.stats[pid] = &unitStats{}
+
+	if old == nil {
+		m.scheduleUnsafe(pid, 0)
+		return true
+	}
+	m.ps[pid] = old // shows as exiting until it is gone
+	go m.launchAfter(pid, uid, old)
+	return true
+}
+
+// launchAfter launches PID (a unit swapped by Replace) once old, the closing
+// child of its previous registration, has exited. Stop/Start/Restart in the
+// meantime apply to PID as if old were its own child.
+func (m *ProcessManager2) launchAfter(pid, uid int64, old *process) {
+	<-old.Done()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ps[pid] == old {
+		delete(m.ps, pid)
+	}
+	if current, exists := m.units[uid]; !exists || current != pid {
+		m.gen.release(pid) // removed meanwhile; never launched
+		return
+	}
+	st := m.stats[pid]
+	st.relaunch = false
+	if m.closed || st.held {
+		return
+	}
+	m.scheduleUnsafe(pid, 0)
+}
+
 // Remove deletes a UID and tears down any running instance.
 func (m *ProcessManager2) Remove(uid int64) {
 	m.mu.Lock()
@@ -144,15 +257,42 @@ func (m *ProcessManager2) Remove(uid int64) {
 		return
 	}
 
+	m.removeUnsafe(uid, pid)
+
+	if m.reg != nil {
+		m.reg.unclaim(uid)
+	}
+}
+
+// removeUnsafe is ProcessManager.removeUnsafe for PM2.
+func (m *ProcessManager2) removeUnsafe(uid, pid int64) *process {
 	// kill running instance
-	if proc := m.ps[pid]; proc != nil {
+	proc := m.ps[pid]
+	if proc != nil {
 		proc.Close()
 	}
 
 	delete(m.units, uid)
 	delete(m.specs, pid)
 	delete(m.ps, pid)
+	delete(m.stats, pid)
 	m.sched.remove(pid)
+	return proc
+}
+
+// Shutdown stops scheduling and disposes of running children per mode
+// (same semantics as ProcessManager.Shutdown).
+func (m *ProcessManager2) Shutdown(ctx context.Context, mode ShutdownMode) {
+	m.mu.Lock()
+	m.closed = true
+	procs := make([]*process, 0, len(m.ps))
+	for _, proc := range m.ps {
+		procs = append(procs, proc)
+	}
+	m.mu.Unlock()
+
+	m.poke()
+	stopProcesses(ctx, procs, mode)
 }
 
 // UpdateLimits adjusts max preflight/onflight capacity at runtime.
@@ -200,16 +340,132 @@ func (m *ProcessManager2) UpdateLimits(maxPre, maxOn int64) {
 	}
 
 	// poke the scheduler
-	select {
-	case m.sig <- struct{}{}:
-	default:
+	m.poke()
+}
+
+// Restart gracefully relaunches a unit: a running child is closed
+// (SIGTERM → grace → SIGKILL) and relaunched as soon as it has exited,
+// without counting as a failure; a unit in backoff, failed or stopped is
+// launched immediately. The restart policy starts over.
+// It reports false for unknown UIDs.
+func (m *ProcessManager2) Restart(uid int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pid, ok := m.units[uid]
+	if !ok || m.closed {
+		return false
+	}
+	st := m.stats[pid]
+	st.held = false
+	st.resetFailed()
+
+	if proc := m.ps[pid]; proc != nil {
+		st.relaunch = true
+		proc.Close()
+		return true
+	}
+	m.scheduleUnsafe(pid, 0)
+	return true
+}
+
+// Stop closes the running child of a unit (if any) and holds the unit until
+// Start or Restart; it stays registered. It reports false for unknown UIDs.
+func (m *ProcessManager2) Stop(uid int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pid, ok := m.units[uid]
+	if !ok || m.closed {
+		return false
+	}
+	st := m.stats[pid]
+	st.held, st.relaunch = true, false
+	m.sched.remove(pid)
+
+	if proc := m.ps[pid]; proc != nil {
+		proc.Close()
+	}
+	return true
+}
+
+// Start releases a unit held by Stop (or parked by the crash-loop breaker)
+// and launches it immediately. A running or queued unit is left alone.
+// It reports false for unknown UIDs.
+func (m *ProcessManager2) Start(uid int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pid, ok := m.units[uid]
+	if !ok || m.closed {
+		return false
+	}
+	st := m.stats[pid]
+	if !st.held && !st.failed {
+		return true
+	}
+	st.held = false
+	st.resetFailed()
+
+	if m.ps[pid] != nil {
+		st.relaunch = true // still exiting → relaunch once it is gone
+		return true
+	}
+	m.scheduleUnsafe(pid, 0)
+	return true
+}
+
+// ResetFailed clears the crash-loop breaker of a failed unit and schedules
+// an immediate launch (subject to slot capacity). It reports false for
+// unknown or non-failed units.
+func (m *ProcessManager2) ResetFailed(uid int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pid, ok := m.units[uid]
+	if !ok || m.closed || !m.stats[pid].failed {
+		return false
+	}
+	m.stats[pid].resetFailed()
+	m.scheduleUnsafe(pid, 0)
+	return true
+}
+
+// Runtime returns a snapshot of the unit's supervisor state.
+// ok is false for unknown UIDs.
+func (m *ProcessManager2) Runtime(uid int64) (rt UnitRuntime, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pid, ok := m.units[uid]
+	if !ok {
+		return UnitRuntime{}, false
 	}
+	next, queued := m.sched.when(pid)
+	starved := m.onflight.current() >= m.onflight.capacity()
+	return snapshot(uid, m.stats[pid], m.ps[pid], next, queued, true, starved, m.closed), true
 }
 
 func (m *ProcessManager2) Onflight() int64 {
 	return m.onflight.current()
 }
 
+// SlotUsage is the occupancy of the preflight/onflight slot pools.
+type SlotUsage struct {
+	Preflight, PreflightCap int64
+	Onflight, OnflightCap   int64
+}
+
+// Slots returns the current occupancy of the slot pools.
+func (m *ProcessManager2) Slots() SlotUsage {
+	return SlotUsage{
+		Preflight:    m.preflight.current(),
+		PreflightCap: m.preflight.capacity(),
+		Onflight:     m.onflight.current(),
+		OnflightCap:  m.onflight.capacity(),
+	}
+}
+
 // ----------------------------------------------------------------------------
 // Event Loop (scheduler) — PM1 semantics extended with dual-slot gating
 // ----------------------------------------------------------------------------
@@ -229,6 +485,10 @@ func (m *ProcessManager2) mainloop() {
 		m.onflight.waitSlot()
 
 		m.mu.Lock()
+		if m.closed {
+			m.mu.Unlock()
+			return
+		}
 		pid, when, ok := m.sched.next()
 
 		if !ok {
@@ -277,14 +537,15 @@ func (m *ProcessManager2) mainloop() {
 
 func (m *ProcessManager2) launchProcessUnsafe(pid int64) {
 	spec := m.specs[pid]
+	m.stats[pid].launches++
 	plog := m.log.With(zap.Int64("uid", spec.unitID), zap.Int64("pid", pid))
 
 	// create process wrapper
-	proc, ok := newProcess(plog, m.logmgr.Get(spec.unitID), m.env, spec.argv)
+	proc, ok := newProcess(plog, m.logmgr.Get(spec.unitID), m.env, spec.argv, spec.env, m.reg, spec.unitID)
 	if !ok {
 		// construction failed — return its preflight slot and retry later
 		m.preflight.release(pid)
-		m.scheduleUnsafe(pid, spec.restartCooldown)
+		m.retryUnsafe(pid, spec, Event{UID: spec.unitID, Kind: EventStartFailed}, time.Time{})
 		return
 	}
 
@@ -294,9 +555,10 @@ func (m *ProcessManager2) launchProcessUnsafe(pid int64) {
 	if !proc.Start() {
 		delete(m.ps, pid)
 		m.preflight.release(pid)
-		m.scheduleUnsafe(pid, spec.restartCooldown)
+		m.retryUnsafe(pid, spec, Event{UID: spec.unitID, Kind: EventStartFailed}, time.Time{})
 		return
 	}
+	m.onEvent.emit(Event{UID: spec.unitID, Kind: EventStarted, CmdPID: proc.cmdPID()})
 
 	// delegate lifecycle to supervisor
 	go m.superviseInstance(pid, spec, proc)
@@ -317,7 +579,7 @@ func (m *ProcessManager2) superviseInstance(pid int64, spec execSpec, proc *proc
 	uid := spec.unitID
 
 	// cleanup + authoritative PID logic
-	defer m.handleExit(pid, uid)
+	defer m.handleExit(pid, uid, proc)
 
 	// --- Phase 1: warm-up ---
 	select {
@@ -336,6 +598,7 @@ func (m *ProcessManager2) superviseInstance(pid int64, spec execSpec, proc *proc
 			m.onflight.release(pid)
 			return
 		}
+		m.onEvent.emit(Event{UID: uid, Kind: EventActive, CmdPID: proc.cmdPID()})
 
 	case <-proc.Done():
 		// died before ready
@@ -348,35 +611,99 @@ func (m *ProcessManager2) superviseInstance(pid int64, spec execSpec, proc *proc
 	m.onflight.release(pid)
 }
 
+// superviseAdopted is superviseInstance for a child adopted from a previous
+// server instance. Usable children skip warm-up and go straight to the active
+// phase (subject to onflight capacity); stale ones are already closing and
+// hold no slot.
+func (m *ProcessManager2) superviseAdopted(pid int64, spec execSpec, proc *process, usable bool) {
+	defer m.handleExit(pid, spec.unitID, proc)
+
+	if !usable {
+		<-proc.Done()
+		return
+	}
+
+	if !m.onflight.tryAcquire(pid) {
+		proc.Close()
+		<-proc.Done()
+		return
+	}
+	m.onEvent.emit(Event{UID: spec.unitID, Kind: EventAdopted, CmdPID: proc.cmdPID()})
+
+	<-proc.Done()
+	m.onflight.release(pid)
+}
+
 // ----------------------------------------------------------------------------
 // Exit handling — identical authoritative-PID logic as PM1
 // ----------------------------------------------------------------------------
 
-func (m *ProcessManager2) handleExit(pid, uid int64) {
+func (m *ProcessManager2) handleExit(pid, uid int64, proc *process) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	delete(m.ps, pid)
 
+	ev := Event{UID: uid, Kind: EventExited, At: time.Now(), CmdPID: proc.cmdPID()}
+	ev.ExitCode, ev.ExitSignal = proc.exitStatus()
+	if st := m.stats[pid]; st != nil {
+		st.exited(ev)
+	}
+
 	current, exists := m.units[uid]
 
-	// If still authoritative, reschedule restart
-	if exists && current == pid {
-		m.scheduleUnsafe(pid, m.specs[pid].restartCooldown)
+	// If still authoritative, reschedule restart (per restart policy)
+	if exists && current == pid && !m.closed {
+		m.retryUnsafe(pid, m.specs[pid], ev, proc.startTime())
 		return
 	}
+	m.onEvent.emit(ev)
 
 	// Otherwise:
 	//   • UID was removed
 	//   • or UID was re-added → now mapped to a new PID
+	//   • or the manager is shutting down
 	m.gen.release(pid)
 }
 
 // ---- Scheduler helper ------------------------------------------------------
 
+// retryUnsafe is ProcessManager.retryUnsafe for PM2.
+func (m *ProcessManager2) retryUnsafe(pid int64, spec execSpec, ev Event, startedAt time.Time) {
+	st := m.stats[pid]
+	switch {
+	case st.held:
+		m.onEvent.emit(ev) // stopped on request
+		return
+	case st.relaunch:
+		st.relaunch = false // restarted on request → not a failure
+		m.scheduleUnsafe(pid, 0)
+		ev.Restarting = true
+		m.onEvent.emit(ev)
+		return
+	}
+
+	delay, failed := st.failure(spec.policy, startedAt)
+	if failed {
+		m.log.Warn("process keeps failing; crash-loop breaker tripped",
+			zap.Int64("uid", spec.unitID), zap.Int64("pid", pid))
+		m.onEvent.emit(ev)
+		m.onEvent.emit(Event{UID: spec.unitID, Kind: EventFailed})
+		return
+	}
+
+	m.scheduleUnsafe(pid, delay)
+	ev.RestartIn, ev.Restarting = delay, true
+	m.onEvent.emit(ev)
+}
+
 func (m *ProcessManager2) scheduleUnsafe(pid int64, after time.Duration) {
 	m.sched.push(pid, time.Now().Add(after))
+	m.poke()
+}
 
+// poke wakes the event loop without blocking.
+func (m *ProcessManager2) poke() {
 	select {
 	case m.sig <- struct{}{}:
 	default:
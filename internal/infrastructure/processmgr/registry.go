@@ -0,0 +1,399 @@
+//go:build linux
+
+package processmgr
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Registry persists the identity of running children on disk so that a
+// restarted server can re-adopt them instead of launching duplicates.
+//
+// Layout (one set of files per unit, keyed by UID):
+//
+//	<dir>/<uid>.json   unitRecord: OS pid, kernel start time, argv digest, entered flag
+//	<dir>/<uid>.out    child stdout+stderr (O_APPEND; tailed by the server)
+//	<dir>/<uid>.in     FIFO used as child stdin (readiness ENTER)
+//
+// plus <dir>/digest.key, the random secret keying spec digests (0600).
+//
+// Child stdio deliberately avoids anonymous pipes when a Registry is in use:
+// the server's end of a pipe dies with the server, and the next write would
+// SIGPIPE a detached child. Files and FIFOs outlive the server process.
+//
+// Identity check: an OS pid alone is not enough (PIDs are reused), so each
+// record also stores the kernel start time (/proc/<pid>/stat field 22).
+// A record is only adopted when both still match.
+type Registry struct {
+	log *zap.Logger
+	dir string
+	key []byte // HMAC key of specDigest
+
+	mu      sync.Mutex
+	claimed map[int64]struct{} // UIDs registered (Add) with any manager in this server instance
+	seen    map[int64]struct{} // UIDs ever claimed in this server instance (never cleared)
+}
+
+// unitRecord is the on-disk identity of a running child.
+type unitRecord struct {
+	UID        int64  `json:"uid"`
+	PID        int    `json:"pid"`         // OS pid (also the process group id; Setpgid)
+	StartTicks uint64 `json:"start_ticks"` // kernel start time in clock ticks since boot
+	ArgvDigest string `json:"argv_digest"` // specDigest of argv and unit env
+	Entered    bool   `json:"entered"`     // past the readiness barrier (or non-interactive)
+}
+
+// NewRegistry prepares dir (created if missing) for child stdio and PID records.
+func NewRegistry(log *zap.Logger, dir string) (*Registry, error) {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create state dir: %w", err)
+	}
+	key, err := loadDigestKey(filepath.Join(dir, "digest.key"))
+	if err != nil {
+		return nil, fmt.Errorf("digest key: %w", err)
+	}
+	return &Registry{
+		log:     log.Named("process-registry"),
+		dir:     dir,
+		key:     key,
+		claimed: make(map[int64]struct{}),
+		seen:    make(map[int64]struct{}),
+	}, nil
+}
+
+// ReapOrphans terminates recorded children whose unit was not registered by
+// any manager since startup (e.g. the channel was deleted or disabled while the
+// server was down) and removes their files.
+//
+// Call once, after all services have reconciled their units.
+func (r *Registry) ReapOrphans() {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		r.log.Warn("read state dir failed", zap.Error(err))
+		return
+	}
+
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok {
+			continue
+		}
+		uid, err := strconv.ParseInt(name, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		r.mu.Lock()
+		_, claimed := r.claimed[uid]
+		r.mu.Unlock()
+		if claimed {
+			continue
+		}
+
+		rec, ok := r.load(uid)
+		if ok && rec.alive() {
+			r.log.Info("terminating orphaned child", zap.Int64("uid", uid), zap.Int("cmd_pid", rec.PID))
+			go terminateGroup(r.log, rec)
+		}
+		r.removeFiles(uid)
+	}
+}
+
+// ----- unit claims ------------------------------------------------------------
+
+// claim registers uid with a manager. It reports whether this is the first
+// claim since startup: only then may a recorded child be adopted. A later
+// claim (after Remove) must launch fresh; the recorded child, if any, is the
+// removed instance on its way out.
+func (r *Registry) claim(uid int64) (first bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.claimed[uid] = struct{}{}
+	if _, ok := r.seen[uid]; ok {
+		return false
+	}
+	r.seen[uid] = struct{}{}
+	return true
+}
+
+func (r *Registry) unclaim(uid int64) {
+	r.mu.Lock()
+	delete(r.claimed, uid)
+	r.mu.Unlock()
+}
+
+// ----- records ------------------------------------------------------------------
+
+func (r *Registry) recordPath(uid int64) string {
+	return filepath.Join(r.dir, fmt.Sprintf("%d.json", uid))
+}
+func (r *Registry) outPath(uid int64) string { return filepath.Join(r.dir, fmt.Sprintf("%d.out", uid)) }
+func (r *Registry) inPath(uid int64) string  { return filepath.Join(r.dir, fmt.Sprintf("%d.in", uid)) }
+
+// save writes rec atomically (temp file + rename).
+func (r *Registry) save(rec unitRecord) error {
+	raw, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("json marshal: %w", err)
+	}
+	path := r.recordPath(rec.UID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o640); err != nil {
+		return fmt.Errorf("write record: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("rename record: %w", err)
+	}
+	return nil
+}
+
+func (r *Registry) load(uid int64) (unitRecord, bool) {
+	var rec unitRecord
+	raw, err := os.ReadFile(r.recordPath(uid))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			r.log.Warn("read record failed", zap.Int64("uid", uid), zap.Error(err))
+		}
+		return rec, false
+	}
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		r.log.Warn("corrupted record", zap.Int64("uid", uid), zap.Error(err))
+		return rec, false
+	}
+	return rec, true
+}
+
+// markEntered flips the entered flag if the record still belongs to cmdPID.
+func (r *Registry) markEntered(uid int64, cmdPID int) {
+	rec, ok := r.load(uid)
+	if !ok || rec.PID != cmdPID {
+		return
+	}
+	rec.Entered = true
+	if err := r.save(rec); err != nil {
+		r.log.Warn("update record failed", zap.Int64("uid", uid), zap.Error(err))
+	}
+}
+
+// forget removes the record (and stdio files) if it still belongs to cmdPID.
+// A newer instance of the same unit may already own the record.
+func (r *Registry) forget(uid int64, cmdPID int) {
+	rec, ok := r.load(uid)
+	if !ok || rec.PID != cmdPID {
+		return
+	}
+	r.removeFiles(uid)
+}
+
+func (r *Registry) removeFiles(uid int64) {
+	for _, p := range []string{r.recordPath(uid), r.outPath(uid), r.inPath(uid)} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			r.log.Warn("remove file failed", zap.String("path", p), zap.Error(err))
+		}
+	}
+}
+
+// ----- stdio ---------------------------------------------------------------------
+
+// unitStdio holds the file handles of a detachable child.
+type unitStdio struct {
+	outW *os.File // child stdout+stderr (write side; closed by the server after Start)
+	outR *os.File // tail reader (read+write so it can truncate)
+	in   *os.File // FIFO opened O_RDWR: child stdin + server ENTER writer
+}
+
+// openStdio creates fresh stdio for a new child of uid.
+//
+// Old files are unlinked first, so a previous instance that is still shutting
+// down keeps writing to its own (now anonymous) inode rather than ours.
+func (r *Registry) openStdio(uid int64) (*unitStdio, error) {
+	outPath, inPath := r.outPath(uid), r.inPath(uid)
+	_ = os.Remove(outPath)
+	_ = os.Remove(inPath)
+
+	outW, err := os.OpenFile(outPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("create output file: %w", err)
+	}
+	outR, err := os.OpenFile(outPath, os.O_RDWR, 0)
+	if err != nil {
+		_ = outW.Close()
+		return nil, fmt.Errorf("open output file: %w", err)
+	}
+	if err := syscall.Mkfifo(inPath, 0o600); err != nil {
+		_ = outW.Close()
+		_ = outR.Close()
+		return nil, fmt.Errorf("create stdin fifo: %w", err)
+	}
+	// O_RDWR never blocks on a FIFO and keeps a writer attached, so the child
+	// never observes EOF on stdin even after the server exits.
+	in, err := os.OpenFile(inPath, os.O_RDWR, 0)
+	if err != nil {
+		_ = outW.Close()
+		_ = outR.Close()
+		return nil, fmt.Errorf("open stdin fifo: %w", err)
+	}
+
+	return &unitStdio{outW: outW, outR: outR, in: in}, nil
+}
+
+// reopenStdio attaches to the files of an adopted child.
+// The output is read from the beginning so recent lines repopulate the log buffer.
+func (r *Registry) reopenStdio(uid int64) (*unitStdio, error) {
+	outR, err := os.OpenFile(r.outPath(uid), os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("open output file: %w", err)
+	}
+	in, err := os.OpenFile(r.inPath(uid), os.O_RDWR, 0)
+	if err != nil {
+		_ = outR.Close()
+		return nil, fmt.Errorf("open stdin fifo: %w", err)
+	}
+	return &unitStdio{outR: outR, in: in}, nil
+}
+
+// ----- helpers -------------------------------------------------------------------
+
+// specDigest fingerprints a launch spec so adoption only happens for
+// identical specs: HMAC-SHA256 over the NUL-joined argv, followed by the
+// NUL-joined unit env after a double NUL when there is one.
+//
+// The unit env carries input credentials and argv is public (ps), so a plain
+// hash in the group-readable record would allow offline guessing; the digest
+// is keyed with the registry secret instead. Records written with an unkeyed
+// digest no longer match and are replaced once.
+func (r *Registry) specDigest(argv, env []string) string {
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(strings.Join(argv, "\x00")))
+	if len(env) > 0 {
+		mac.Write([]byte("\x00\x00" + strings.Join(env, "\x00")))
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// loadDigestKey reads the registry secret at path, creating it (32 random
+// bytes, 0600) on first use.
+func loadDigestKey(path string) ([]byte, error) {
+	key, err := os.ReadFile(path)
+	if err == nil {
+		if len(key) < 32 {
+			return nil, fmt.Errorf("%s: too short (%d bytes)", path, len(key))
+		}
+		return key, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("rand read: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write(key); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(path)
+		return nil, err
+	}
+	return key, nil
+}
+
+// procStartTicks reads the kernel start time (field 22) of pid from /proc.
+func procStartTicks(pid int) (uint64, bool) {
+	raw, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, false
+	}
+	// comm (field 2) may contain spaces/parens; fields after the last ')' are fixed.
+	i := bytes.LastIndexByte(raw, ')')
+	if i < 0 {
+		return 0, false
+	}
+	fields := strings.Fields(string(raw[i+1:]))
+	// fields[0] is field 3 (state) → field 22 is fields[19]
+	if len(fields) < 20 {
+		return 0, false
+	}
+	// A zombie has exited; it is only waiting to be reaped.
+	if fields[0] == "Z" {
+		return 0, false
+	}
+	ticks, err := strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return ticks, true
+}
+
+// clockTicks is USER_HZ, the unit of /proc/<pid>/stat start times (100 on
+// every mainstream Linux architecture).
+const clockTicks = 100
+
+// procStartTime converts a start time in clock ticks since boot to wall-clock
+// time (zero when the boot time is unavailable).
+func procStartTime(ticks uint64) time.Time {
+	raw, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return time.Time{}
+	}
+	for _, line := range strings.Split(string(raw), "\n") {
+		if v, ok := strings.CutPrefix(line, "btime "); ok {
+			btime, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil {
+				return time.Time{}
+			}
+			return time.Unix(btime, 0).Add(time.Duration(ticks) * time.Second / clockTicks)
+		}
+	}
+	return time.Time{}
+}
+
+// alive reports whether the recorded process still runs (same pid, same start time).
+func (rec unitRecord) alive() bool {
+	ticks, ok := procStartTicks(rec.PID)
+	return ok && ticks == rec.StartTicks
+}
+
+// terminateGroup sends SIGTERM to the recorded process group and escalates to
+// SIGKILL after the same grace period process.Close uses.
+func terminateGroup(log *zap.Logger, rec unitRecord) {
+	_ = syscall.Kill(-rec.PID, syscall.SIGTERM)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		if !rec.alive() {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	log.Warn("grace timeout expired; sending SIGKILL", zap.Int("cmd_pid", rec.PID))
+	_ = syscall.Kill(-rec.PID, syscall.SIGKILL)
+}
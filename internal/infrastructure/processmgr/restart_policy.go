@@ -0,0 +1,83 @@
+package processmgr
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// RestartPolicy controls how a unit is relaunched after it exits or fails
+// to start.
+//
+//   - The first relaunch waits Delay; each further consecutive failure
+//     doubles the delay up to MaxDelay (±10% jitter). MaxDelay <= Delay
+//     keeps the delay fixed (no jitter).
+//   - A run lasting at least ResetAfter clears the failure history.
+//   - Burst failures within Interval trip the crash-loop breaker: the unit
+//     is parked in StateFailed until ResetFailed. Burst 0 disables it.
+//
+// The zero value restarts immediately, forever.
+type RestartPolicy struct {
+	Delay      time.Duration
+	MaxDelay   time.Duration
+	ResetAfter time.Duration // 0: never reset by uptime
+	Burst      int
+	Interval   time.Duration
+}
+
+// backoffFloor is the base of the exponential growth when Delay is 0.
+const backoffFloor = time.Second
+
+// delay returns the wait before the next launch after n consecutive failures.
+func (p RestartPolicy) delay(n int) time.Duration {
+	if p.MaxDelay <= p.Delay || n <= 1 {
+		return p.Delay
+	}
+
+	d := max(p.Delay, backoffFloor)
+	for i := 1; i < n && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, p.MaxDelay)
+
+	// ±10% jitter, spreading restarts of units that failed together
+	if spread := int64(d / 5); spread > 0 {
+		d += time.Duration(rand.Int64N(spread)) - d/10
+	}
+	return d
+}
+
+// failure records a failed run (exit or failed start) of a unit started at
+// startedAt (zero when it never started) and returns the delay before the
+// next launch; failed reports that the crash-loop breaker tripped.
+func (st *unitStats) failure(p RestartPolicy, startedAt time.Time) (delay time.Duration, failed bool) {
+	now := time.Now()
+
+	if p.ResetAfter > 0 && !startedAt.IsZero() && now.Sub(startedAt) >= p.ResetAfter {
+		st.failures = 0 // stable run
+		st.recent = st.recent[:0]
+	}
+	st.failures++
+
+	if p.Burst > 0 {
+		keep := st.recent[:0]
+		for _, at := range st.recent {
+			if now.Sub(at) < p.Interval {
+				keep = append(keep, at)
+			}
+		}
+		st.recent = append(keep, now)
+
+		if len(st.recent) >= p.Burst {
+			st.failed = true
+			return 0, true
+		}
+	}
+	return p.delay(st.failures), false
+}
+
+// resetFailed clears the breaker and the failure history.
+func (st *unitStats) resetFailed() {
+	st.failed = false
+	st.failures = 0
+	st.recent = nil
+}
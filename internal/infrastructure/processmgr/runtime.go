@@ -0,0 +1,116 @@
+package processmgr
+
+import "time"
+
+// UnitState is the supervisor's view of a unit at a point in time.
+type UnitState string
+
+const (
+	StateScheduled       UnitState = "scheduled"            // first launch queued
+	StateStarting        UnitState = "starting"             // PM2 only: ready; being promoted past the readiness barrier
+	StatePreflight       UnitState = "preflight"            // PM2 only: running, holding a preflight slot until ready
+	StateWaitingOnflight UnitState = "waiting_for_onflight" // PM2 only: launch due but every onflight slot is taken
+	StateActive          UnitState = "active"               // running (PM2: past the readiness barrier)
+	StateExiting         UnitState = "exiting"              // stop requested or child gone; exit not yet handled
+	StateBackoff         UnitState = "backoff"              // relaunch queued after an exit or a failed start
+	StateFailed          UnitState = "failed"               // crash-loop breaker tripped; parked until ResetFailed
+	StateStopped         UnitState = "stopped"              // stopped on request (Stop) or manager shut down
+)
+
+// UnitRuntime is a snapshot of a unit's supervisor state (see Runtime).
+//
+// Counters are per registration: removing and re-adding a unit (e.g. on a
+// command line change; see Replace) starts over.
+type UnitRuntime struct {
+	UID   int64
+	State UnitState
+
+	CmdPID    int       // OS pid of the current child; 0 when none
+	StartedAt time.Time // start of the current child; zero when none
+
+	LastExitAt     time.Time // zero until the first exit
+	LastExitCode   *int      // nil when unknown or signaled
+	LastExitSignal string    // e.g. "killed"; empty when the child exited on its own
+
+	Restarts     int       // launches after the first one (failed starts included)
+	Failures     int       // consecutive failed runs (reset by a stable run; see RestartPolicy)
+	NextLaunchAt time.Time // zero unless a launch is queued
+}
+
+// unitStats is the per-PID history behind UnitRuntime.
+// Guarded by the owning manager's lock.
+type unitStats struct {
+	launches       int
+	lastExitAt     time.Time
+	lastExitCode   *int
+	lastExitSignal string
+
+	// restart policy state (see RestartPolicy)
+	failures int         // consecutive failed runs
+	recent   []time.Time // failure times within the breaker interval
+	failed   bool        // breaker tripped
+
+	// manual control (Restart/Stop/Start)
+	held     bool // stopped on request; not launched until Start/Restart
+	relaunch bool // the current child is being restarted on request
+}
+
+// restarts is launches minus the first one.
+func (st *unitStats) restarts() int {
+	if st.launches <= 1 {
+		return 0
+	}
+	return st.launches - 1
+}
+
+// exited records the exit status of a reaped child.
+func (st *unitStats) exited(ev Event) {
+	st.lastExitAt = ev.At
+	st.lastExitCode, st.lastExitSignal = ev.ExitCode, ev.ExitSignal
+}
+
+// snapshot derives a UnitRuntime from the manager tables.
+//
+//   - proc: current child (nil when none)
+//   - next, queued: scheduler entry
+//   - gated: PM2 readiness barrier applies
+//   - starved: PM2 onflight pool is full
+//   - closed: manager shut down
+func snapshot(uid int64, st *unitStats, proc *process, next time.Time, queued, gated, starved, closed bool) UnitRuntime {
+	rt := UnitRuntime{
+		UID:            uid,
+		LastExitAt:     st.lastExitAt,
+		LastExitCode:   st.lastExitCode,
+		LastExitSignal: st.lastExitSignal,
+		Restarts:       st.restarts(),
+		Failures:       st.failures,
+	}
+
+	switch {
+	case proc != nil:
+		rt.CmdPID = proc.cmdPID()
+		rt.StartedAt = proc.startTime()
+		rt.State = proc.state(gated)
+
+	case st.failed:
+		rt.State = StateFailed
+
+	case st.held:
+		rt.State = StateStopped
+
+	case queued && !closed:
+		rt.NextLaunchAt = next
+		switch {
+		case gated && starved && !next.After(time.Now()):
+			rt.State = StateWaitingOnflight
+		case st.launches > 0:
+			rt.State = StateBackoff
+		default:
+			rt.State = StateScheduled
+		}
+
+	default:
+		rt.State = StateStopped
+	}
+	return rt
+}
@@ -51,6 +51,15 @@ func (s *scheduler) next() (id int64, when time.Time, ok bool) {
 	return ev.id, ev.when, true
 }
 
+// when returns the pending launch time of id, if any.
+func (s *scheduler) when(id int64) (time.Time, bool) {
+	ev, ok := s.entries[id]
+	if !ok {
+		return time.Time{}, false
+	}
+	return ev.when, true
+}
+
 // pop removes the head event unconditionally.
 func (s *scheduler) pop() {
 	if len(s.h) == 0 {
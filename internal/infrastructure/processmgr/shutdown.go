@@ -0,0 +1,84 @@
+//go:build linux
+
+package processmgr
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// ShutdownMode selects what happens to running children on server shutdown.
+type ShutdownMode int
+
+const (
+	StopChildren   ShutdownMode = iota // terminate children cleanly (SIGTERM → grace → SIGKILL)
+	DetachChildren                     // leave children running for the next server instance to adopt
+)
+
+// ParseShutdownMode maps "stop" / "detach" to a ShutdownMode.
+func ParseShutdownMode(s string) (ShutdownMode, bool) {
+	switch s {
+	case "stop":
+		return StopChildren, true
+	case "detach":
+		return DetachChildren, true
+	default:
+		return 0, false
+	}
+}
+
+// stopProcesses applies mode to procs. For StopChildren it blocks until every
+// process is done or ctx expires.
+func stopProcesses(ctx context.Context, procs []*process, mode ShutdownMode) {
+	if mode == DetachChildren {
+		return
+	}
+	for _, proc := range procs {
+		proc.Close()
+	}
+	for _, proc := range procs {
+		select {
+		case <-proc.Done():
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// adoptOrEvict inspects the registry record of uid left by a previous server
+// instance.
+//
+//   - no record / process gone        → (nil, false); caller launches normally
+//   - alive, same spec, usable state  → (proc, true)
+//   - alive but stale (spec changed, or still at the readiness barrier when
+//     requireEntered is set)          → (proc, false), proc already closing;
+//     the caller's exit handler restarts the unit once it is gone
+//   - alive but stdio cannot be reopened → (proc, false), proc a stand-in
+//     whose Done() fires once the process group has been terminated
+//
+// Either way a running child is never duplicated.
+func adoptOrEvict(log *zap.Logger, logBuf *logBuffer, reg *Registry, uid int64, argv, env []string, requireEntered bool) (*process, bool) {
+	rec, ok := reg.load(uid)
+	if !ok || !rec.alive() {
+		return nil, false
+	}
+
+	proc, ok := adoptProcess(log, logBuf, reg, rec)
+	if !ok {
+		// Cannot attach to its stdio; terminate it rather than run two copies.
+		return evictProcess(log, logBuf, reg, rec), false
+	}
+
+	switch {
+	case rec.ArgvDigest != reg.specDigest(argv, env):
+		log.Info("adopted process has a stale spec; replacing", zap.Int("cmd_pid", rec.PID))
+		proc.Close()
+		return proc, false
+	case requireEntered && !rec.Entered:
+		log.Info("adopted process never passed readiness; replacing", zap.Int("cmd_pid", rec.PID))
+		proc.Close()
+		return proc, false
+	}
+	return proc, true
+}
@@ -0,0 +1,79 @@
+// Package telemetry holds the process-wide Prometheus registry and the
+// metric families shared across layers (HTTP, Redis, process supervision).
+//
+// Scrape-time families (channel states, quotas, remux metrics) are
+// registered by their owners via Registry.Register.
+package telemetry
+
+import (
+	"errors"
+	"time"
+
+	"github.com/edirooss/zmux-server/pkg/promtext"
+	"github.com/redis/go-redis/v9"
+)
+
+// Registry is exposed at GET /metrics.
+var Registry = promtext.NewRegistry()
+
+var (
+	// HTTPRequests counts served requests by method, route template and status.
+	HTTPRequests = Registry.NewCounterVec(
+		"zmux_http_requests_total",
+		"HTTP requests served, by method, route and status code.",
+		"method", "route", "status",
+	)
+
+	// HTTPDuration observes request latency by method and route template.
+	HTTPDuration = Registry.NewHistogramVec(
+		"zmux_http_request_duration_seconds",
+		"HTTP request latency in seconds, by method and route.",
+		nil, "method", "route",
+	)
+
+	// RedisDuration observes Redis round trips by component and operation.
+	RedisDuration = Registry.NewHistogramVec(
+		"zmux_redis_call_duration_seconds",
+		"Redis call latency in seconds, by component and operation.",
+		nil, "component", "op",
+	)
+
+	// RedisErrors counts failed Redis calls (redis.Nil excluded).
+	RedisErrors = Registry.NewCounterVec(
+		"zmux_redis_call_errors_total",
+		"Failed Redis calls, by component and operation.",
+		"component", "op",
+	)
+
+	// ProcessEvents counts process lifecycle events by B2B client ("" for
+	// non-B2B channels) and event kind.
+	ProcessEvents = Registry.NewCounterVec(
+		"zmux_process_events_total",
+		"Remux process lifecycle events, by B2B client and kind (started, start_failed, adopted, active, exited, failed).",
+		"b2b_client", "kind",
+	)
+
+	// ProcessRestarts counts relaunches scheduled after an exit.
+	ProcessRestarts = Registry.NewCounterVec(
+		"zmux_process_restarts_total",
+		"Remux process restarts scheduled after an exit, by B2B client.",
+		"b2b_client",
+	)
+
+	// ProcessExits counts exits by B2B client and code: the exit status,
+	// "signal:<name>" for signaled children, or "unknown" (adopted children).
+	ProcessExits = Registry.NewCounterVec(
+		"zmux_process_exits_total",
+		"Remux process exits, by B2B client and exit code.",
+		"b2b_client", "code",
+	)
+)
+
+// ObserveRedis records a Redis call started at start; err is the call's
+// error, if any (redis.Nil is not a failure).
+func ObserveRedis(component, op string, start time.Time, err error) {
+	RedisDuration.Observe(time.Since(start).Seconds(), component, op)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		RedisErrors.Inc(component, op)
+	}
+}
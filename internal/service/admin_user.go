@@ -0,0 +1,440 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	adminuser "github.com/edirooss/zmux-server/internal/domain/admin-user"
+	"github.com/edirooss/zmux-server/internal/domain/principal"
+	"github.com/edirooss/zmux-server/internal/infrastructure/datastore"
+	"github.com/edirooss/zmux-server/internal/infrastructure/objectstore"
+	"github.com/edirooss/zmux-server/pkg/passhash"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	adminUserKeyPrefix = "zmux:admin_user:" // zmux:admin_user:<id> → JSON(AdminUserModel)
+)
+
+var (
+	// ErrInvalidCredentials means a supplied password did not match.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// AdminUserOptions configures first-boot provisioning.
+//
+// When the store holds no admin users at startup and BootstrapPassword is set,
+// a single enabled admin is created with these credentials. Once any admin
+// exists, the bootstrap values are ignored.
+type AdminUserOptions struct {
+	BootstrapUsername string
+	BootstrapPassword string
+}
+
+// AdminUserService manages admin (operator) accounts persisted in Redis.
+//
+// Passwords are stored only as PHC-encoded hashes (see pkg/passhash).
+// Domain objects are immutable once published; every write replaces the
+// object in the in-memory store and the username index.
+type AdminUserService struct {
+	log *zap.Logger
+
+	mu         sync.RWMutex
+	ds         *datastore.DataStore            // Redis-based persistent store
+	objs       *objectstore.ObjectStore        // in-memory object store of AdminUser domain objects
+	byUsername map[string]*adminuser.AdminUser // in-memory username-based index
+
+	dummyHash string // verified against on unknown usernames to equalize timing
+}
+
+func NewAdminUserService(ctx context.Context, log *zap.Logger, rdb *redis.Client, opts AdminUserOptions) (*AdminUserService, error) {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	log = log.Named("admin-user-service")
+
+	ds, err := datastore.NewDataStore(ctx, log, rdb, adminUserKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("datastore: %w", err)
+	}
+
+	dummyHash, err := passhash.Hash("zmux-dummy-password")
+	if err != nil {
+		return nil, fmt.Errorf("dummy hash: %w", err)
+	}
+
+	s := &AdminUserService{
+		log:        log,
+		ds:         ds,
+		objs:       objectstore.NewObjectStore(log),
+		byUsername: make(map[string]*adminuser.AdminUser),
+		dummyHash:  dummyHash,
+	}
+
+	if err := s.reconcile(ctx); err != nil {
+		return nil, fmt.Errorf("reconcile: %w", err)
+	}
+
+	if err := s.bootstrap(ctx, opts); err != nil {
+		return nil, fmt.Errorf("bootstrap: %w", err)
+	}
+
+	return s, nil
+}
+
+// Create adds a new admin user. r.Password is required (validated by the caller).
+func (s *AdminUserService) Create(ctx context.Context, r *adminuser.AdminUserResource) (*adminuser.AdminUserView, error) {
+	if r.Password == nil {
+		return nil, errors.New("password is required")
+	}
+	hash, err := passhash.Hash(*r.Password)
+	if err != nil {
+		return nil, fmt.Errorf("hash password: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, taken := s.byUsername[r.Username]; taken {
+		return nil, fmt.Errorf("%w: username %q already exists", ErrConflict, r.Username)
+	}
+
+	now := time.Now().UnixMilli()
+	model := &adminuser.AdminUserModel{
+		Username:          r.Username,
+		PasswordHash:      hash,
+		Enabled:           r.Enabled,
+		Role:              r.Role,
+		CreatedAt:         now,
+		PasswordChangedAt: now,
+	}
+
+	id, err := s.createUnsafe(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	val, _ := s.objs.GetOne(id)
+	return val.(*adminuser.AdminUser).View(), nil
+}
+
+// Update replaces username/enabled/role and, when r.Password is non-nil, the password.
+// Disabling or demoting the last enabled admin is rejected with ErrConflict.
+func (s *AdminUserService) Update(ctx context.Context, id int64, r *adminuser.AdminUserResource) (*adminuser.AdminUserView, error) {
+	var hash string
+	if r.Password != nil {
+		var err error
+		if hash, err = passhash.Hash(*r.Password); err != nil {
+			return nil, fmt.Errorf("hash password: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, ok := s.objs.GetOne(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	cur := val.(*adminuser.AdminUser)
+
+	if other, taken := s.byUsername[r.Username]; taken && other.ID != id {
+		return nil, fmt.Errorf("%w: username %q already exists", ErrConflict, r.Username)
+	}
+	next := *cur
+	next.Username = r.Username
+	next.Enabled = r.Enabled
+	next.Role = principal.Role(r.Role)
+
+	if isActiveAdmin(cur) && !isActiveAdmin(&next) && s.countActiveAdminsUnsafe() == 1 {
+		return nil, fmt.Errorf("%w: cannot disable or demote the last enabled admin user", ErrConflict)
+	}
+	if r.Password != nil {
+		next.PasswordHash = hash
+		next.PasswordChangedAt = time.Now().UnixMilli()
+	}
+
+	if err := s.updateUnsafe(ctx, cur, &next); err != nil {
+		return nil, err
+	}
+	return next.View(), nil
+}
+
+// ChangePassword is the self-service flow: the current password must match.
+func (s *AdminUserService) ChangePassword(ctx context.Context, id int64, currentPassword, newPassword string) error {
+	s.mu.RLock()
+	val, ok := s.objs.GetOne(id)
+	s.mu.RUnlock()
+	if !ok {
+		return ErrNotFound
+	}
+
+	checked := val.(*adminuser.AdminUser).PasswordHash
+	match, err := passhash.Verify(currentPassword, checked)
+	if err != nil {
+		return fmt.Errorf("verify password: %w", err)
+	}
+	if !match {
+		return ErrInvalidCredentials
+	}
+
+	hash, err := passhash.Hash(newPassword)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Re-read under the write lock; the user may have changed meanwhile.
+	val, ok = s.objs.GetOne(id)
+	if !ok {
+		return ErrNotFound
+	}
+	cur := val.(*adminuser.AdminUser)
+	if cur.PasswordHash != checked {
+		// The password was changed (e.g. reset by an admin) after the check;
+		// the old password must not override it.
+		return fmt.Errorf("%w: password changed concurrently", ErrConflict)
+	}
+
+	next := *cur
+	next.PasswordHash = hash
+	next.PasswordChangedAt = time.Now().UnixMilli()
+	return s.updateUnsafe(ctx, cur, &next)
+}
+
+// Delete removes an admin user. Deleting the last enabled admin (role) is rejected with ErrConflict.
+func (s *AdminUserService) Delete(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, ok := s.objs.GetOne(id)
+	if !ok {
+		return ErrNotFound
+	}
+	u := val.(*adminuser.AdminUser)
+
+	if isActiveAdmin(u) && s.countActiveAdminsUnsafe() == 1 {
+		return fmt.Errorf("%w: cannot delete the last enabled admin user", ErrConflict)
+	}
+
+	if err := s.ds.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+	s.objs.Delete(id)
+	delete(s.byUsername, u.Username)
+
+	return nil
+}
+
+func (s *AdminUserService) GetOne(id int64) (*adminuser.AdminUserView, error) {
+	val, ok := s.objs.GetOne(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return val.(*adminuser.AdminUser).View(), nil
+}
+
+func (s *AdminUserService) GetList() ([]*adminuser.AdminUserView, error) {
+	_, vals := s.objs.GetList()
+
+	views := make([]*adminuser.AdminUserView, 0, len(vals))
+	for _, val := range vals {
+		views = append(views, val.(*adminuser.AdminUser).View())
+	}
+	return views, nil
+}
+
+// Authenticate verifies username/password and returns the user when it exists and is enabled.
+//
+// Unknown usernames are verified against a dummy hash so response timing does
+// not reveal which usernames exist. Hashes produced with outdated parameters
+// are upgraded in place on success (best-effort).
+func (s *AdminUserService) Authenticate(ctx context.Context, username, password string) (*adminuser.AdminUser, bool) {
+	s.mu.RLock()
+	u, ok := s.byUsername[username]
+	s.mu.RUnlock()
+
+	if !ok {
+		_, _ = passhash.Verify(password, s.dummyHash)
+		return nil, false
+	}
+
+	match, err := passhash.Verify(password, u.PasswordHash)
+	if err != nil {
+		s.log.Error("password hash unreadable", zap.Int64("id", u.ID), zap.Error(err))
+		return nil, false
+	}
+	if !match || !u.Enabled {
+		return nil, false
+	}
+
+	if passhash.NeedsRehash(u.PasswordHash) {
+		s.rehash(ctx, u.ID, u.PasswordHash, password)
+	}
+
+	return u, true
+}
+
+// LookupEnabled returns the user by ID if it still exists and is enabled.
+// Used to re-validate session-based principals on every request.
+func (s *AdminUserService) LookupEnabled(id int64) (*adminuser.AdminUser, bool) {
+	val, ok := s.objs.GetOne(id)
+	if !ok {
+		return nil, false
+	}
+	u := val.(*adminuser.AdminUser)
+	if !u.Enabled {
+		return nil, false
+	}
+	return u, true
+}
+
+// ----- internals ------------------------------------------------------------
+
+// reconcile loads persisted records into domain objects and rebuilds in-memory indices.
+//
+//   - DB (Persistence Layer) → Domain (in-memory)
+func (s *AdminUserService) reconcile(ctx context.Context) error {
+	ids, vals, err := s.ds.GetList(ctx)
+	if err != nil {
+		return fmt.Errorf("get list: %w", err)
+	}
+
+	for i, id := range ids {
+		var model adminuser.AdminUserModel
+		if err := json.Unmarshal(vals[i], &model); err != nil {
+			// Data corruption detected - should never happen in normal operation.
+			s.log.Error("corrupted data detected",
+				zap.Int64("id", id),
+				zap.Error(err))
+			return fmt.Errorf("json unmarshal: %w", err)
+		}
+
+		u := adminuser.NewAdminUser(&model, id)
+		s.objs.Upsert(id, u)
+		s.byUsername[u.Username] = u
+	}
+
+	return nil
+}
+
+// bootstrap provisions the first admin user on an empty store.
+func (s *AdminUserService) bootstrap(ctx context.Context, opts AdminUserOptions) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.byUsername) > 0 {
+		return nil
+	}
+	if opts.BootstrapPassword == "" {
+		s.log.Warn("no admin users exist and no bootstrap password configured; admin login is impossible")
+		return nil
+	}
+
+	hash, err := passhash.Hash(opts.BootstrapPassword)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+
+	now := time.Now().UnixMilli()
+	id, err := s.createUnsafe(ctx, &adminuser.AdminUserModel{
+		Username:          opts.BootstrapUsername,
+		PasswordHash:      hash,
+		Enabled:           true,
+		Role:              string(principal.RoleAdmin),
+		CreatedAt:         now,
+		PasswordChangedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.log.Info("bootstrap admin user created", zap.Int64("id", id), zap.String("username", opts.BootstrapUsername))
+	return nil
+}
+
+// rehash upgrades a stored hash to current parameters; failures are logged only.
+// checked is the hash password was verified against: when the stored hash no
+// longer equals it (the password changed meanwhile) nothing is written.
+func (s *AdminUserService) rehash(ctx context.Context, id int64, checked, password string) {
+	hash, err := passhash.Hash(password)
+	if err != nil {
+		s.log.Warn("rehash failed", zap.Int64("id", id), zap.Error(err))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, ok := s.objs.GetOne(id)
+	if !ok {
+		return
+	}
+	cur := val.(*adminuser.AdminUser)
+	if cur.PasswordHash != checked {
+		return
+	}
+
+	next := *cur
+	next.PasswordHash = hash
+	if err := s.updateUnsafe(ctx, cur, &next); err != nil {
+		s.log.Warn("rehash failed", zap.Int64("id", id), zap.Error(err))
+	}
+}
+
+// createUnsafe persists model and publishes the domain object; must be locked.
+func (s *AdminUserService) createUnsafe(ctx context.Context, model *adminuser.AdminUserModel) (int64, error) {
+	raw, err := json.Marshal(model)
+	if err != nil {
+		return 0, fmt.Errorf("json marshal: %w", err)
+	}
+
+	id, err := s.ds.Create(ctx, raw)
+	if err != nil {
+		return 0, fmt.Errorf("create: %w", err)
+	}
+
+	u := adminuser.NewAdminUser(model, id)
+	s.objs.Upsert(id, u)
+	s.byUsername[u.Username] = u
+	return id, nil
+}
+
+// updateUnsafe persists next and swaps it in for cur; must be locked.
+func (s *AdminUserService) updateUnsafe(ctx context.Context, cur, next *adminuser.AdminUser) error {
+	raw, err := json.Marshal(next.Model())
+	if err != nil {
+		return fmt.Errorf("json marshal: %w", err)
+	}
+	if err := s.ds.Update(ctx, next.ID, raw); err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+
+	s.objs.Upsert(next.ID, next)
+	delete(s.byUsername, cur.Username)
+	s.byUsername[next.Username] = next
+	return nil
+}
+
+// countActiveAdminsUnsafe returns the number of enabled users with the admin role; must be locked.
+func (s *AdminUserService) countActiveAdminsUnsafe() int {
+	n := 0
+	for _, u := range s.byUsername {
+		if isActiveAdmin(u) {
+			n++
+		}
+	}
+	return n
+}
+
+// isActiveAdmin reports whether u can manage admin users (enabled + admin role).
+func isActiveAdmin(u *adminuser.AdminUser) bool {
+	return u.Enabled && u.Role == principal.RoleAdmin
+}
@@ -0,0 +1,614 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	alertrule "github.com/edirooss/zmux-server/internal/domain/alert-rule"
+	"github.com/edirooss/zmux-server/internal/domain/channel"
+	"github.com/edirooss/zmux-server/internal/domain/webhook"
+	"github.com/edirooss/zmux-server/internal/infrastructure/datastore"
+	"github.com/edirooss/zmux-server/internal/infrastructure/objectstore"
+	"github.com/edirooss/zmux-server/internal/infrastructure/processmgr"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	alertRuleKeyPrefix = "zmux:alert_rule:" // zmux:alert_rule:<id> → JSON(AlertRuleModel)
+)
+
+// AlertOptions tunes rule evaluation.
+type AlertOptions struct {
+	// EvalInterval is the rule evaluation period.
+	EvalInterval time.Duration
+	// ResolvedRetention is how long resolved alerts stay listed.
+	ResolvedRetention time.Duration
+}
+
+func (o *AlertOptions) setDefaults() {
+	if o.EvalInterval <= 0 {
+		o.EvalInterval = 5 * time.Second
+	}
+	if o.ResolvedRetention <= 0 {
+		o.ResolvedRetention = 24 * time.Hour
+	}
+}
+
+// AlertService manages alert rules persisted in Redis and evaluates them
+// against remux summaries, process restarts and B2B client quota usage.
+//
+// Alerts:
+//   - One alert per (rule, subject), identified by a fingerprint; repeated
+//     evaluations update it rather than raising duplicates.
+//   - A holding condition starts a pending alert, which fires once the
+//     condition has held for the rule's for_sec, and resolves when it clears
+//     (a pending alert whose condition clears is dropped).
+//   - Firing and resolution are notified via webhooks (alert.firing,
+//     alert.resolved), unless the rule or the alert is silenced. An alert
+//     that fired while silenced is notified once the silence ends; a
+//     resolution is only notified for an alert whose firing was.
+//
+// Alerts live in memory only: after a restart, conditions still holding go
+// through pending again.
+type AlertService struct {
+	log      *zap.Logger
+	opts     AlertOptions
+	webhooks *WebhookService
+
+	mu   sync.RWMutex
+	ds   *datastore.DataStore     // Redis-based persistent store
+	objs *objectstore.ObjectStore // in-memory object store of AlertRule domain objects
+
+	alertsMu sync.Mutex
+	alerts   map[string]*alertEntry // by fingerprint
+
+	restartsMu sync.Mutex
+	restarts   map[int64][]int64 // channel ID → restart times (UTC millis), oldest first
+}
+
+// alertEntry is the evaluation state of a single alert.
+type alertEntry struct {
+	view     alertrule.AlertView
+	notified bool // alert.firing was sent
+}
+
+// alertCond is a rule condition found holding during an evaluation.
+type alertCond struct {
+	rule        *alertrule.AlertRule
+	channelID   *int64
+	b2bClientID *int64
+	outputRef   string
+	value       float64
+	threshold   float64
+	message     string
+	elapsed     bool // value is the time the condition has held (seconds)
+}
+
+func NewAlertService(ctx context.Context, log *zap.Logger, rdb *redis.Client, webhooks *WebhookService, opts AlertOptions) (*AlertService, error) {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	log = log.Named("alert-service")
+	opts.setDefaults()
+
+	ds, err := datastore.NewDataStore(ctx, log, rdb, alertRuleKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("datastore: %w", err)
+	}
+
+	s := &AlertService{
+		log:      log,
+		opts:     opts,
+		webhooks: webhooks,
+		ds:       ds,
+		objs:     objectstore.NewObjectStore(log),
+		alerts:   make(map[string]*alertEntry),
+		restarts: make(map[int64][]int64),
+	}
+
+	if err := s.reconcile(ctx); err != nil {
+		return nil, fmt.Errorf("reconcile: %w", err)
+	}
+	return s, nil
+}
+
+// ----- rules ----------------------------------------------------------------
+
+func (s *AlertService) Create(ctx context.Context, r *alertrule.AlertRuleResource) (*alertrule.AlertRuleView, error) {
+	now := time.Now().UnixMilli()
+	model := alertrule.NewAlertRuleModel(r, now, now)
+	raw, err := json.Marshal(model)
+	if err != nil {
+		return nil, fmt.Errorf("json marshal: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, err := s.ds.Create(ctx, raw)
+	if err != nil {
+		return nil, fmt.Errorf("create: %w", err)
+	}
+	rule := alertrule.NewAlertRule(model, id)
+	s.objs.Upsert(id, rule)
+
+	return rule.View(), nil
+}
+
+// Update replaces the rule. Alerts of the rule keep their state; the new
+// parameters apply from the next evaluation.
+func (s *AlertService) Update(ctx context.Context, id int64, r *alertrule.AlertRuleResource) (*alertrule.AlertRuleView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, ok := s.objs.GetOne(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	cur := val.(*alertrule.AlertRule)
+
+	model := alertrule.NewAlertRuleModel(r, cur.CreatedAt, time.Now().UnixMilli())
+	raw, err := json.Marshal(model)
+	if err != nil {
+		return nil, fmt.Errorf("json marshal: %w", err)
+	}
+	if err := s.ds.Update(ctx, id, raw); err != nil {
+		return nil, fmt.Errorf("update: %w", err)
+	}
+	next := alertrule.NewAlertRule(model, id)
+	s.objs.Upsert(id, next)
+
+	return next.View(), nil
+}
+
+// Delete removes the rule. Its firing alerts resolve on the next evaluation.
+func (s *AlertService) Delete(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.objs.GetOne(id); !ok {
+		return ErrNotFound
+	}
+	if err := s.ds.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+	s.objs.Delete(id)
+	return nil
+}
+
+func (s *AlertService) GetOne(id int64) (*alertrule.AlertRuleView, error) {
+	val, ok := s.objs.GetOne(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return val.(*alertrule.AlertRule).View(), nil
+}
+
+func (s *AlertService) GetList() ([]*alertrule.AlertRuleView, error) {
+	_, vals := s.objs.GetList()
+
+	views := make([]*alertrule.AlertRuleView, 0, len(vals))
+	for _, val := range vals {
+		views = append(views, val.(*alertrule.AlertRule).View())
+	}
+	return views, nil
+}
+
+// ----- alerts ---------------------------------------------------------------
+
+// GetAlerts returns the current alerts, most recent first. A non-empty state
+// filters by alert state.
+func (s *AlertService) GetAlerts(state alertrule.AlertState) []alertrule.AlertView {
+	now := time.Now().UnixMilli()
+
+	s.alertsMu.Lock()
+	out := make([]alertrule.AlertView, 0, len(s.alerts))
+	for _, a := range s.alerts {
+		if state != "" && a.view.State != state {
+			continue
+		}
+		out = append(out, s.alertViewUnsafe(a, now))
+	}
+	s.alertsMu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].StartsAt != out[j].StartsAt {
+			return out[i].StartsAt > out[j].StartsAt
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// Silence suppresses notifications of a single alert for d; zero d lifts
+// the silence.
+func (s *AlertService) Silence(id string, d time.Duration) (*alertrule.AlertView, error) {
+	now := time.Now().UnixMilli()
+
+	s.alertsMu.Lock()
+	defer s.alertsMu.Unlock()
+
+	a, ok := s.alerts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	a.view.SilencedUntil = 0
+	if d > 0 {
+		a.view.SilencedUntil = now + d.Milliseconds()
+	}
+
+	view := s.alertViewUnsafe(a, now)
+	return &view, nil
+}
+
+// alertViewUnsafe renders an alert with its effective silence.
+// Caller holds alertsMu.
+func (s *AlertService) alertViewUnsafe(a *alertEntry, now int64) alertrule.AlertView {
+	view := a.view
+	if view.SilencedUntil <= now {
+		view.SilencedUntil = 0
+	}
+	view.Silenced = s.silenced(&view, now)
+	return view
+}
+
+// silenced reports whether notifications of the alert are suppressed, by
+// its own silence or its rule's.
+func (s *AlertService) silenced(v *alertrule.AlertView, now int64) bool {
+	if v.SilencedUntil > now {
+		return true
+	}
+	val, ok := s.objs.GetOne(v.RuleID)
+	return ok && val.(*alertrule.AlertRule).Silenced(now)
+}
+
+// ----- evaluation -----------------------------------------------------------
+
+// Run records process restarts from events and evaluates the rules every
+// EvalInterval until ctx is cancelled.
+func (s *AlertService) Run(ctx context.Context, events *ChannelEventHub, chnlsvc *ChannelService, b2bclntsvc *B2BClientService, repo *RemuxRepository) {
+	events.Observe(func(ev ChannelEvent) {
+		if ev.Process != nil && ev.Process.State == string(processmgr.EventExited) && ev.Process.Restarting {
+			s.restartsMu.Lock()
+			s.restarts[ev.ChannelID] = append(s.restarts[ev.ChannelID], ev.At)
+			s.restartsMu.Unlock()
+		}
+	})
+
+	ticker := time.NewTicker(s.opts.EvalInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		evalctx, cancel := context.WithTimeout(ctx, s.opts.EvalInterval)
+		if err := s.evaluate(evalctx, chnlsvc, b2bclntsvc, repo); err != nil && ctx.Err() == nil {
+			s.log.Warn("alert evaluation failed", zap.Error(err))
+		}
+		cancel()
+	}
+}
+
+// evaluate checks every enabled rule and advances the alerts.
+func (s *AlertService) evaluate(ctx context.Context, chnlsvc *ChannelService, b2bclntsvc *B2BClientService, repo *RemuxRepository) error {
+	now := time.Now().UnixMilli()
+
+	_, vals := s.objs.GetList()
+	var channelRules, quotaRules []*alertrule.AlertRule
+	var maxWindow int64
+	for _, val := range vals {
+		rule := val.(*alertrule.AlertRule)
+		if !rule.Enabled {
+			continue
+		}
+		switch rule.Kind {
+		case alertrule.KindB2BQuota:
+			quotaRules = append(quotaRules, rule)
+		case alertrule.KindChannelRestarts:
+			maxWindow = max(maxWindow, rule.Window.Milliseconds())
+			channelRules = append(channelRules, rule)
+		default:
+			channelRules = append(channelRules, rule)
+		}
+	}
+	restarts := s.pruneRestarts(now - maxWindow)
+
+	conds := make(map[string]*alertCond)
+	if len(channelRules) > 0 {
+		if err := s.evalChannels(ctx, chnlsvc, repo, channelRules, restarts, now, conds); err != nil {
+			return err
+		}
+	}
+	if len(quotaRules) > 0 {
+		if err := s.evalQuotas(b2bclntsvc, quotaRules, conds); err != nil {
+			return err
+		}
+	}
+
+	s.advance(conds, now)
+	return nil
+}
+
+// pruneRestarts drops restart times before cutoff and returns a snapshot.
+func (s *AlertService) pruneRestarts(cutoff int64) map[int64][]int64 {
+	s.restartsMu.Lock()
+	defer s.restartsMu.Unlock()
+
+	out := make(map[int64][]int64, len(s.restarts))
+	for chID, ts := range s.restarts {
+		i := sort.Search(len(ts), func(i int) bool { return ts[i] >= cutoff })
+		if i == len(ts) {
+			delete(s.restarts, chID)
+			continue
+		}
+		ts = ts[i:]
+		s.restarts[chID] = ts
+		out[chID] = append([]int64(nil), ts...)
+	}
+	return out
+}
+
+func (s *AlertService) evalChannels(ctx context.Context, chnlsvc *ChannelService, repo *RemuxRepository, rules []*alertrule.AlertRule, restarts map[int64][]int64, now int64, conds map[string]*alertCond) error {
+	chs, err := chnlsvc.GetList(ctx)
+	if err != nil {
+		return fmt.Errorf("list channels: %w", err)
+	}
+
+	enabledIDs := make([]string, 0, len(chs))
+	for _, ch := range chs {
+		if ch.Enabled {
+			enabledIDs = append(enabledIDs, remuxID(ch))
+		}
+	}
+	summaries, err := repo.GetSummariesByID(ctx, enabledIDs)
+	if err != nil {
+		return fmt.Errorf("get remux summaries: %w", err)
+	}
+
+	for _, ch := range chs {
+		if !ch.Enabled {
+			continue
+		}
+		sum := summaries[remuxID(ch)]
+		online := sum != nil && sum.Status != nil && sum.Status.Online
+
+		for _, rule := range rules {
+			if !rule.MatchesChannel(ch.ID, ch.B2BClientID) {
+				continue
+			}
+
+			cond := &alertCond{rule: rule, channelID: &ch.ID, b2bClientID: ch.B2BClientID, threshold: rule.Threshold}
+			switch rule.Kind {
+			case alertrule.KindChannelOffline:
+				if online {
+					continue
+				}
+				cond.elapsed = true
+				cond.message = alertChannelLabel(ch) + " is offline"
+				if sum != nil && sum.Status != nil && sum.Status.Event.Message != "" {
+					cond.message += ": " + sum.Status.Event.Message
+				}
+
+			case alertrule.KindChannelMetricBelow, alertrule.KindChannelMetricAbove:
+				if !online || sum.Metrics == nil {
+					continue
+				}
+				v, ok := sum.Metrics.Counter(rule.Metric)
+				if !ok {
+					continue
+				}
+				below := rule.Kind == alertrule.KindChannelMetricBelow
+				if below && v >= rule.Threshold || !below && v <= rule.Threshold {
+					continue
+				}
+				cond.value = v
+				cmp := "above"
+				if below {
+					cmp = "below"
+				}
+				cond.message = fmt.Sprintf("%s: %s = %g is %s %g", alertChannelLabel(ch), rule.Metric, v, cmp, rule.Threshold)
+
+			case alertrule.KindChannelRestarts:
+				n := 0
+				for _, at := range restarts[ch.ID] {
+					if at >= now-rule.Window.Milliseconds() {
+						n++
+					}
+				}
+				if int64(n) <= rule.Count {
+					continue
+				}
+				cond.value = float64(n)
+				cond.threshold = float64(rule.Count)
+				cond.message = fmt.Sprintf("%s restarted %d times in the last %s", alertChannelLabel(ch), n, rule.Window)
+
+			default:
+				continue
+			}
+
+			conds[alertFingerprint(rule.ID, "channel", ch.ID, "")] = cond
+		}
+	}
+	return nil
+}
+
+func (s *AlertService) evalQuotas(b2bclntsvc *B2BClientService, rules []*alertrule.AlertRule, conds map[string]*alertCond) error {
+	views, err := b2bclntsvc.GetList()
+	if err != nil {
+		return fmt.Errorf("list b2b clients: %w", err)
+	}
+
+	for _, v := range views {
+		for _, rule := range rules {
+			if !rule.MatchesB2BClient(v.ID) {
+				continue
+			}
+
+			check := func(ref string, usage, quota int64) {
+				if quota <= 0 {
+					return
+				}
+				pct := float64(usage) * 100 / float64(quota)
+				if pct < rule.Threshold {
+					return
+				}
+
+				what := strings.ReplaceAll(rule.Quota, "_", " ")
+				if ref != "" {
+					what += " (" + ref + ")"
+				}
+				conds[alertFingerprint(rule.ID, "b2b_client", v.ID, ref)] = &alertCond{
+					rule:        rule,
+					b2bClientID: &v.ID,
+					outputRef:   ref,
+					value:       pct,
+					threshold:   rule.Threshold,
+					message:     fmt.Sprintf("B2B client %d %q at %.0f%% of its %s quota (%d/%d)", v.ID, v.Name, pct, what, usage, quota),
+				}
+			}
+
+			switch rule.Quota {
+			case alertrule.QuotaEnabledChannels:
+				check("", v.Quotas.EnabledChannels.Usage, v.Quotas.EnabledChannels.Quota)
+			case alertrule.QuotaOnlineChannels:
+				check("", v.Quotas.OnlineChannels.Usage, v.Quotas.OnlineChannels.Quota)
+			case alertrule.QuotaEnabledOutputs:
+				for _, o := range v.Quotas.EnabledOutputs {
+					check(o.Ref, o.Usage, o.Quota)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// advance moves alerts through pending → firing → resolved given the
+// conditions holding at now, sends the resulting notifications and prunes
+// expired resolved alerts.
+func (s *AlertService) advance(conds map[string]*alertCond, now int64) {
+	type notification struct {
+		t    webhook.EventType
+		view alertrule.AlertView
+	}
+	var notes []notification
+
+	s.alertsMu.Lock()
+	for id, cond := range conds {
+		a, ok := s.alerts[id]
+		if !ok || a.view.State == alertrule.AlertResolved {
+			var silencedUntil int64
+			if ok {
+				silencedUntil = a.view.SilencedUntil // a recurrence keeps the alert-level silence
+			}
+			a = &alertEntry{view: alertrule.AlertView{
+				ID:            id,
+				State:         alertrule.AlertPending,
+				StartsAt:      now,
+				SilencedUntil: silencedUntil,
+			}}
+			s.alerts[id] = a
+		}
+
+		a.view.RuleID = cond.rule.ID
+		a.view.RuleName = cond.rule.Name
+		a.view.Kind = string(cond.rule.Kind)
+		a.view.Severity = cond.rule.Severity
+		a.view.ChannelID = cond.channelID
+		a.view.B2BClientID = cond.b2bClientID
+		a.view.OutputRef = cond.outputRef
+		a.view.Value = cond.value
+		a.view.Threshold = cond.threshold
+		a.view.Message = cond.message
+		if cond.elapsed {
+			a.view.Value = float64(now-a.view.StartsAt) / 1000
+		}
+
+		if a.view.State == alertrule.AlertPending && now-a.view.StartsAt >= cond.rule.For.Milliseconds() {
+			a.view.State = alertrule.AlertFiring
+			a.view.FiredAt = now
+		}
+		if a.view.State == alertrule.AlertFiring && !a.notified && !s.silenced(&a.view, now) {
+			a.notified = true
+			notes = append(notes, notification{webhook.EventAlertFiring, s.alertViewUnsafe(a, now)})
+		}
+	}
+
+	for id, a := range s.alerts {
+		switch a.view.State {
+		case alertrule.AlertPending:
+			if conds[id] == nil {
+				delete(s.alerts, id)
+			}
+		case alertrule.AlertFiring:
+			if conds[id] == nil {
+				a.view.State = alertrule.AlertResolved
+				a.view.ResolvedAt = now
+				if a.notified {
+					notes = append(notes, notification{webhook.EventAlertResolved, s.alertViewUnsafe(a, now)})
+				}
+			}
+		case alertrule.AlertResolved:
+			if now-a.view.ResolvedAt >= s.opts.ResolvedRetention.Milliseconds() {
+				delete(s.alerts, id)
+			}
+		}
+	}
+	s.alertsMu.Unlock()
+
+	for _, n := range notes {
+		s.log.Info(string(n.t), zap.String("alert", n.view.ID), zap.String("message", n.view.Message))
+		s.webhooks.Notify(n.t, n.view)
+	}
+}
+
+// reconcile loads persisted records into domain objects.
+//
+//   - DB (Persistence Layer) → Domain (in-memory)
+func (s *AlertService) reconcile(ctx context.Context) error {
+	ids, vals, err := s.ds.GetList(ctx)
+	if err != nil {
+		return fmt.Errorf("get list: %w", err)
+	}
+
+	for i, id := range ids {
+		var model alertrule.AlertRuleModel
+		if err := json.Unmarshal(vals[i], &model); err != nil {
+			// Data corruption detected - should never happen in normal operation.
+			s.log.Error("corrupted data detected",
+				zap.Int64("id", id),
+				zap.Error(err))
+			return fmt.Errorf("json unmarshal: %w", err)
+		}
+		s.objs.Upsert(id, alertrule.NewAlertRule(&model, id))
+	}
+
+	return nil
+}
+
+// alertFingerprint identifies the alert of a rule for one subject, e.g.
+// "3-channel-12" or "4-b2b_client-2-srt-out".
+func alertFingerprint(ruleID int64, subject string, subjectID int64, ref string) string {
+	fp := strconv.FormatInt(ruleID, 10) + "-" + subject + "-" + strconv.FormatInt(subjectID, 10)
+	if ref != "" {
+		fp += "-" + ref
+	}
+	return fp
+}
+
+func alertChannelLabel(ch *channel.ZmuxChannel) string {
+	if ch.Name != nil && *ch.Name != "" {
+		return fmt.Sprintf("channel %d %q", ch.ID, *ch.Name)
+	}
+	return fmt.Sprintf("channel %d", ch.ID)
+}
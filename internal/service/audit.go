@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/edirooss/zmux-server/internal/domain/principal"
+	"github.com/edirooss/zmux-server/internal/infrastructure/telemetry"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	auditKey = "zmux:audit" // STREAM of {v: JSON(AuditRecord)}
+
+	// auditWriteTimeout bounds a single XADD; the audited change is already
+	// persisted, so a slow Redis must not hold up the response.
+	auditWriteTimeout = 2 * time.Second
+
+	// auditRedacted replaces secret values in audit diffs.
+	auditRedacted = "[REDACTED]"
+)
+
+// Audit actions.
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+// Audited resources.
+const (
+	AuditResourceChannel           = "channel"
+	AuditResourceB2BClient         = "b2b_client"
+	AuditResourceOutputDestination = "output_destination"
+)
+
+// auditSecretFields are model fields whose values never enter the audit
+// log; a change is recorded with both sides redacted.
+var auditSecretFields = map[string]bool{
+	"password": true, // channel input password (sealed at rest)
+	"hash":     true, // B2B API token hash
+	"secret":   true, // webhook signing key
+}
+
+// AuditOptions tunes the audit stream.
+type AuditOptions struct {
+	// Retention is how long records are kept.
+	Retention time.Duration
+}
+
+func (o *AuditOptions) setDefaults() {
+	if o.Retention <= 0 {
+		o.Retention = 365 * 24 * time.Hour
+	}
+}
+
+// AuditRecord is one mutating API call.
+type AuditRecord struct {
+	ID         string              `json:"id"` // stream entry ID; pass as ?since= to read on from here
+	At         int64               `json:"at"` // UTC millis
+	Actor      principal.Principal `json:"actor"`
+	RequestID  string              `json:"request_id"`
+	ClientIP   string              `json:"client_ip"`
+	Action     string              `json:"action"`   // create | update | delete
+	Resource   string              `json:"resource"` // channel | b2b_client
+	ResourceID int64               `json:"resource_id"`
+	Changes    []AuditChange       `json:"changes"` // field-level diff of the persisted model
+}
+
+// AuditChange is a changed model field. Before is null on create, After on
+// delete.
+type AuditChange struct {
+	Path   string `json:"path"` // e.g. "input.url", "outputs[1].enabled"
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// AuditQuery filters AuditService.Get.
+type AuditQuery struct {
+	Resource string // "<resource>" or "<resource>:<id>"; empty: any
+	Actor    string // "<kind>:<id>" or "<id>"; empty: any
+	Since    string // see AuditService.Get
+	Limit    int
+}
+
+func (q *AuditQuery) matches(r *AuditRecord) bool {
+	if q.Resource != "" && q.Resource != r.Resource && q.Resource != r.Resource+":"+strconv.FormatInt(r.ResourceID, 10) {
+		return false
+	}
+	if q.Actor != "" && q.Actor != r.Actor.ID && q.Actor != r.Actor.Kind.String()+":"+r.Actor.ID {
+		return false
+	}
+	return true
+}
+
+// AuditService keeps an append-only log of mutating API calls in a Redis
+// stream. Records older than the retention are trimmed.
+type AuditService struct {
+	log  *zap.Logger
+	rdb  *redis.Client
+	opts AuditOptions
+}
+
+func NewAuditService(log *zap.Logger, rdb *redis.Client, opts AuditOptions) *AuditService {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	opts.setDefaults()
+
+	return &AuditService{
+		log:  log.Named("audit"),
+		rdb:  rdb,
+		opts: opts,
+	}
+}
+
+// Record diffs before and after (persistence models; nil for create/delete)
+// into rec.Changes and appends rec. Failures are logged, not returned: the
+// audited change has already been applied.
+func (s *AuditService) Record(ctx context.Context, rec AuditRecord, before, after any) {
+	changes, err := auditDiff(before, after)
+	if err != nil {
+		s.log.Error("audit diff failed", zap.String("resource", rec.Resource), zap.Int64("resource_id", rec.ResourceID), zap.Error(err))
+	}
+	rec.Changes = changes
+	rec.At = time.Now().UnixMilli()
+
+	raw, err := json.Marshal(rec)
+	if err != nil {
+		s.log.Error("json marshal failed", zap.Error(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), auditWriteTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err = s.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: auditKey,
+		MinID:  strconv.FormatInt(time.Now().Add(-s.opts.Retention).UnixMilli(), 10),
+		Approx: true,
+		Values: []string{"v", string(raw)},
+	}).Err()
+	telemetry.ObserveRedis("audit", "xadd", start, err)
+	if err != nil {
+		s.log.Warn("audit write failed",
+			zap.String("request_id", rec.RequestID),
+			zap.String("resource", rec.Resource),
+			zap.Int64("resource_id", rec.ResourceID),
+			zap.Error(err))
+	}
+}
+
+// Get returns up to q.Limit records matching q, oldest first.
+//
+// q.Since selects the starting point: empty returns the most recent records;
+// a record ID returns the records after it; a UTC millis timestamp returns
+// the records at or after it.
+func (s *AuditService) Get(ctx context.Context, q AuditQuery) ([]AuditRecord, error) {
+	out := make([]AuditRecord, 0, q.Limit)
+	batch := int64(max(q.Limit, 100))
+
+	if q.Since == "" {
+		// Page backwards from the newest record, then restore chronological order.
+		end := "+"
+		for len(out) < q.Limit {
+			msgs, err := s.xrange(ctx, "xrevrange", func() ([]redis.XMessage, error) {
+				return s.rdb.XRevRangeN(ctx, auditKey, end, "-", batch).Result()
+			})
+			if err != nil {
+				return nil, err
+			}
+			out = s.appendRecords(out, msgs, &q)
+			if int64(len(msgs)) < batch {
+				break
+			}
+			end = "(" + msgs[len(msgs)-1].ID
+		}
+		slices.Reverse(out)
+		return out, nil
+	}
+
+	start := q.Since
+	if strings.Contains(q.Since, "-") {
+		start = "(" + q.Since
+	}
+	for len(out) < q.Limit {
+		msgs, err := s.xrange(ctx, "xrange", func() ([]redis.XMessage, error) {
+			return s.rdb.XRangeN(ctx, auditKey, start, "+", batch).Result()
+		})
+		if err != nil {
+			return nil, err
+		}
+		out = s.appendRecords(out, msgs, &q)
+		if int64(len(msgs)) < batch {
+			break
+		}
+		start = "(" + msgs[len(msgs)-1].ID
+	}
+	return out, nil
+}
+
+func (s *AuditService) xrange(ctx context.Context, op string, fn func() ([]redis.XMessage, error)) ([]redis.XMessage, error) {
+	start := time.Now()
+	msgs, err := fn()
+	telemetry.ObserveRedis("audit", op, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return msgs, nil
+}
+
+// appendRecords decodes the records of msgs matching q into out, up to
+// q.Limit records in total. Undecodable entries are skipped.
+func (s *AuditService) appendRecords(out []AuditRecord, msgs []redis.XMessage, q *AuditQuery) []AuditRecord {
+	for _, msg := range msgs {
+		if len(out) == q.Limit {
+			break
+		}
+		raw, _ := msg.Values["v"].(string)
+		var rec AuditRecord
+		if err := json.Unmarshal([]byte(raw), &rec); err != nil {
+			s.log.Warn("corrupted audit record", zap.String("id", msg.ID), zap.Error(err))
+			continue
+		}
+		if !q.matches(&rec) {
+			continue
+		}
+		rec.ID = msg.ID
+		out = append(out, rec)
+	}
+	return out
+}
+
+// auditDiff returns the fields that differ between the JSON encodings of
+// before and after (either may be nil), sorted by path. Secret fields are
+// redacted, as are passwords in URL values.
+func auditDiff(before, after any) ([]AuditChange, error) {
+	b, err := auditFlatten(before)
+	if err != nil {
+		return nil, fmt.Errorf("before: %w", err)
+	}
+	a, err := auditFlatten(after)
+	if err != nil {
+		return nil, fmt.Errorf("after: %w", err)
+	}
+
+	paths := make(map[string]struct{}, len(b)+len(a))
+	for p := range b {
+		paths[p] = struct{}{}
+	}
+	for p := range a {
+		paths[p] = struct{}{}
+	}
+
+	changes := make([]AuditChange, 0)
+	for p := range paths {
+		bv, av := b[p], a[p]
+		bj, _ := json.Marshal(bv)
+		aj, _ := json.Marshal(av)
+		if string(bj) == string(aj) {
+			continue
+		}
+		changes = append(changes, AuditChange{Path: p, Before: auditRedact(p, bv), After: auditRedact(p, av)})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// auditFlatten maps the leaf values of v's JSON encoding by path.
+func auditFlatten(v any) (map[string]any, error) {
+	out := make(map[string]any)
+	if v == nil {
+		return out, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var tree any
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		return nil, err
+	}
+
+	var walk func(path string, node any)
+	walk = func(path string, node any) {
+		switch n := node.(type) {
+		case map[string]any:
+			for k, child := range n {
+				if path == "" {
+					walk(k, child)
+				} else {
+					walk(path+"."+k, child)
+				}
+			}
+		case []any:
+			for i, child := range n {
+				walk(path+"["+strconv.Itoa(i)+"]", child)
+			}
+		default:
+			out[path] = n
+		}
+	}
+	walk("", tree)
+	return out, nil
+}
+
+func auditRedact(path string, v any) any {
+	if v == nil {
+		return nil
+	}
+	field := path[strings.LastIndexByte(path, '.')+1:]
+	if auditSecretFields[field] {
+		return auditRedacted
+	}
+	if s, ok := v.(string); ok && strings.Contains(s, "://") {
+		if u, err := url.Parse(s); err == nil {
+			return u.Redacted()
+		}
+	}
+	return v
+}
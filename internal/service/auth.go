@@ -18,35 +18,40 @@ const principalKey contextKey = "auth.principal"
 type AuthService struct {
 	log         *zap.Logger
 	UserSession *UserSessionService
+	adminsvc    *AdminUserService
 	b2bsvc      *B2BClientService
 }
 
 // NewAuthService creates a new AuthService.
-func NewAuthService(log *zap.Logger, isDev bool, b2bsvc *B2BClientService) (*AuthService, error) {
+func NewAuthService(log *zap.Logger, sessOpts UserSessionOptions, adminsvc *AdminUserService, b2bsvc *B2BClientService) (*AuthService, error) {
 	log = log.Named("auth")
-	usersesssvc, err := NewUserSessionService(isDev)
+	usersesssvc, err := NewUserSessionService(sessOpts)
 	if err != nil {
 		return nil, fmt.Errorf("new user session service: %w", err)
 	}
 
-	return &AuthService{log: log, UserSession: usersesssvc, b2bsvc: b2bsvc}, nil
+	return &AuthService{log: log, UserSession: usersesssvc, adminsvc: adminsvc, b2bsvc: b2bsvc}, nil
 }
 
-// AuthenticateWithPassword authenticates using username and password.
-// On success, it sets and returns the Principal.
+// AuthenticateWithPassword authenticates an admin user by username and password.
+// On success, it sets and returns the Principal (ID is the admin user ID).
 func (s *AuthService) AuthenticateWithPassword(c *gin.Context, username, password string) (*principal.Principal, bool) {
-	if username == "hozi" && password == "Zz1234$#@!" {
-		p := &principal.Principal{
-			ID:   "hozi",
-			Kind: principal.Admin,
-		}
-		s.setPrincipal(c, p)
-		return p, true
+	u, ok := s.adminsvc.Authenticate(c.Request.Context(), username, password)
+	if !ok {
+		return nil, false
+	}
+	p := &principal.Principal{
+		ID:   strconv.FormatInt(u.ID, 10),
+		Kind: principal.Admin,
+		Role: u.Role,
 	}
-	return nil, false
+	s.setPrincipal(c, p)
+	return p, true
 }
 
 // AuthenticateWithSession reads session from context and authenticates user ID.
+// The admin user must still exist and be enabled; deleted or disabled users lose
+// access on their next request even while their session cookie is valid.
 func (s *AuthService) AuthenticateWithSession(c *gin.Context) (*principal.Principal, bool) {
 	session := sessions.Default(c)
 	uid, ok := s.UserSession.GetUserID(session)
@@ -54,28 +59,37 @@ func (s *AuthService) AuthenticateWithSession(c *gin.Context) (*principal.Princi
 		return nil, false
 	}
 
-	if uid == "hozi" {
-		p := &principal.Principal{
-			ID:   "hozi",
-			Kind: principal.Admin,
-		}
-		s.setPrincipal(c, p)
-		return p, true
+	id, err := strconv.ParseInt(uid, 10, 64)
+	if err != nil {
+		return nil, false // pre-migration session (non-numeric ID)
 	}
-	return nil, false
+
+	u, ok := s.adminsvc.LookupEnabled(id)
+	if !ok {
+		return nil, false
+	}
+	p := &principal.Principal{
+		ID:   strconv.FormatInt(u.ID, 10),
+		Kind: principal.Admin,
+		Role: u.Role,
+	}
+	s.setPrincipal(c, p)
+	return p, true
 }
 
-// AuthenticateWithBearerToken authenticates using a bearer token.
-// Looks up principal by bearer token in Redis and sets it on the request context.
-// DEV: No constant-time compare here—token is used as a Redis key; errors are logged and treated as auth failures.
+// AuthenticateWithBearerToken authenticates a B2B client by API token (see
+// B2BClientService.LookupByToken). The principal carries the token's ID and
+// scope; expired tokens fail.
 func (s *AuthService) AuthenticateWithBearerToken(c *gin.Context, token string) (*principal.Principal, bool) {
-	b2bClient, ok := s.b2bsvc.LookupByToken(token)
+	b2bClient, tok, ok := s.b2bsvc.LookupByToken(token)
 	if !ok {
 		return nil, false
 	}
 	p := &principal.Principal{
-		ID:   strconv.FormatInt(b2bClient.ID, 10),
-		Kind: principal.B2BClient,
+		ID:      strconv.FormatInt(b2bClient.ID, 10),
+		Kind:    principal.B2BClient,
+		TokenID: tok.ID,
+		Scope:   tok.Scope,
 	}
 	s.setPrincipal(c, p)
 	return p, true
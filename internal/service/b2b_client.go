@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -12,6 +13,8 @@ import (
 
 	b2bclient "github.com/edirooss/zmux-server/internal/domain/b2b-client"
 	"github.com/edirooss/zmux-server/internal/domain/channel"
+	"github.com/edirooss/zmux-server/internal/domain/principal"
+	"github.com/edirooss/zmux-server/internal/domain/webhook"
 	"github.com/edirooss/zmux-server/internal/infrastructure/datastore"
 	"github.com/edirooss/zmux-server/internal/infrastructure/objectstore"
 	"github.com/edirooss/zmux-server/internal/infrastructure/processmgr"
@@ -29,10 +32,13 @@ type B2BClientService struct {
 
 	mu        sync.RWMutex
 	logmngr   *processmgr.LogManager
+	procreg   *processmgr.Registry                  // optional; shared by all process managers
+	events    *ChannelEventHub                      // process lifecycle notifications
+	webhooks  *WebhookService                       // client change notifications
 	procmngrs map[int64]*processmgr.ProcessManager2 // B2B clients channels runtime
 	ds        *datastore.DataStore                  // Redis-based persistent store
 	objs      *objectstore.ObjectStore              // in-memory object store of B2BClient domain objects
-	byToken   map[string]*b2bclient.B2BClient       // in-memory token-based index of B2BClient domain objects
+	byToken   map[string]*b2bclient.B2BClient       // in-memory index of B2BClient domain objects by token hash
 
 	channelB2BClientID  map[int64]int64   // maps channel ID to its associated B2B client ID
 	b2bClientChannelIDs map[int64][]int64 // maps B2B client ID to a list of their channel IDs
@@ -43,7 +49,7 @@ type B2BClientService struct {
 	b2bClientOnlineChannelsUsage  map[int64]int64
 }
 
-func NewB2BClientService(ctx context.Context, log *zap.Logger, rdb *redis.Client, logmngr *processmgr.LogManager) (*B2BClientService, error) {
+func NewB2BClientService(ctx context.Context, log *zap.Logger, rdb *redis.Client, logmngr *processmgr.LogManager, procreg *processmgr.Registry, events *ChannelEventHub, webhooks *WebhookService) (*B2BClientService, error) {
 	if log == nil {
 		log = zap.NewNop()
 	}
@@ -55,8 +61,11 @@ func NewB2BClientService(ctx context.Context, log *zap.Logger, rdb *redis.Client
 	}
 
 	s := &B2BClientService{
-		log:     log,
-		logmngr: logmngr,
+		log:      log,
+		logmngr:  logmngr,
+		procreg:  procreg,
+		events:   events,
+		webhooks: webhooks,
 
 		procmngrs: make(map[int64]*processmgr.ProcessManager2),
 		ds:        ds,
@@ -82,12 +91,14 @@ func (s *B2BClientService) Create(ctx context.Context, r *b2bclient.B2BClientRes
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	token, err := genUniqueB2BToken(s.byToken)
+	// Every client starts with one read-write token; its secret is returned
+	// once, in the create response.
+	secret, tok, err := s.newTokenUnsafe(nil, "default", principal.ScopeReadWrite, 0)
 	if err != nil {
-		return nil, fmt.Errorf("generate token: %w", err)
+		return nil, err
 	}
 
-	model := b2bclient.NewB2BClientModel(r, token)
+	model := b2bclient.NewB2BClientModel(r, []b2bclient.TokenModel{tok.Model()})
 	raw, err := json.Marshal(model)
 	if err != nil {
 		return nil, fmt.Errorf("json marshal: %w", err)
@@ -100,11 +111,16 @@ func (s *B2BClientService) Create(ctx context.Context, r *b2bclient.B2BClientRes
 
 	b2bclnt := b2bclient.NewB2BClient(model, b2bclntID)
 	s.objs.Upsert(b2bclntID, b2bclnt)
-	s.byToken[b2bclnt.BearerToken] = b2bclnt
+	s.indexTokensUnsafe(b2bclnt)
 	s.b2bClientEnabledOutputsUsage[b2bclntID] = make(map[string]int64)
-	s.procmngrs[b2bclntID] = processmgr.NewProcessManager2(zap.NewNop(), s.logmngr, b2bclnt.Quotas.OnlineChannels.MaxPreflight, b2bclnt.Quotas.OnlineChannels.Quota)
+	s.procmngrs[b2bclntID] = processmgr.NewProcessManager2(zap.NewNop(), s.logmngr, s.procreg, s.events.ProcessEventHandler(b2bclntID), b2bclnt.Quotas.OnlineChannels.MaxPreflight, b2bclnt.Quotas.OnlineChannels.Quota)
 
-	return s.buildViewUnsafe(b2bclnt), nil
+	view := s.buildViewUnsafe(b2bclnt)
+	s.webhooks.notifyB2BClient(webhook.EventB2BClientCreated, b2bclntID, b2bclnt.Name, view)
+
+	created := *view
+	created.BearerToken = secret
+	return &created, nil
 }
 
 func (s *B2BClientService) Update(ctx context.Context, b2bclntID int64, r *b2bclient.B2BClientResource) (*b2bclient.B2BClientView, error) {
@@ -122,7 +138,7 @@ func (s *B2BClientService) Update(ctx context.Context, b2bclntID int64, r *b2bcl
 		return nil, ErrNotFound
 	}
 
-	nextModel := b2bclient.NewB2BClientModel(r, b2bclnt.BearerToken)
+	nextModel := b2bclient.NewB2BClientModel(r, b2bclnt.TokenModels())
 	raw, err := json.Marshal(nextModel)
 	if err != nil {
 		return nil, fmt.Errorf("json marshal: %w", err)
@@ -131,12 +147,15 @@ func (s *B2BClientService) Update(ctx context.Context, b2bclntID int64, r *b2bcl
 		return nil, fmt.Errorf("update: %w", err)
 	}
 
+	s.unindexTokensUnsafe(b2bclnt)
 	b2bclnt = b2bclient.NewB2BClient(nextModel, b2bclntID)
 	s.objs.Upsert(b2bclntID, b2bclnt)
-	s.byToken[b2bclnt.BearerToken] = b2bclnt
+	s.indexTokensUnsafe(b2bclnt)
 	procmngr.UpdateLimits(b2bclnt.Quotas.OnlineChannels.MaxPreflight, b2bclnt.Quotas.OnlineChannels.Quota)
 
-	return s.buildViewUnsafe(b2bclnt), nil
+	view := s.buildViewUnsafe(b2bclnt)
+	s.webhooks.notifyB2BClient(webhook.EventB2BClientUpdated, b2bclntID, b2bclnt.Name, view)
+	return view, nil
 }
 
 // Delete removes a B2B client by ID and updates in-memory indices.
@@ -158,9 +177,10 @@ func (s *B2BClientService) Delete(ctx context.Context, b2bclntID int64) error {
 		return fmt.Errorf("delete: %w", err)
 	}
 	s.objs.Delete(b2bclntID)
-	delete(s.byToken, b2bclnt.BearerToken)
+	s.unindexTokensUnsafe(b2bclnt)
 	delete(s.procmngrs, b2bclntID)
 
+	s.webhooks.notifyB2BClient(webhook.EventB2BClientDeleted, b2bclntID, b2bclnt.Name, nil)
 	return nil
 }
 
@@ -190,7 +210,44 @@ func (s *B2BClientService) RegisterChannel(b2bclntID int64, ch *channel.ZmuxChan
 	s.procmngrs[b2bclntID].Add(
 		ch.ID,
 		remuxcmd.BuildArgv(ch),
-		time.Duration(ch.RestartSec)*time.Second,
+		remuxcmd.BuildEnv(ch),
+		restartPolicy(ch),
+	)
+}
+
+// ReplaceChannel swaps a registered channel for its updated version (same
+// B2B client). The running process is kept when the remux command line and
+// credentials are unchanged (see processmgr.ProcessManager2.Replace), so e.g.
+// a rename does not interrupt the stream.
+func (s *B2BClientService) ReplaceChannel(b2bclntID int64, prev, ch *channel.ZmuxChannel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// update counters
+	if prev.Enabled {
+		s.b2bClientEnabledChannelsUsage[b2bclntID]--
+	}
+	for _, o := range prev.Outputs {
+		if o.Enabled {
+			s.b2bClientEnabledOutputsUsage[b2bclntID][o.Ref]--
+		}
+	}
+	if ch.Enabled {
+		s.b2bClientEnabledChannelsUsage[b2bclntID]++
+	}
+	for _, out := range ch.Outputs {
+		if out.Enabled {
+			s.b2bClientEnabledOutputsUsage[b2bclntID][out.Ref]++
+		}
+	}
+
+	// update proc manager (restarts only on a command line or credentials change)
+	ch.Interactive = true
+	s.procmngrs[b2bclntID].Replace(
+		ch.ID,
+		remuxcmd.BuildArgv(ch),
+		remuxcmd.BuildEnv(ch),
+		restartPolicy(ch),
 	)
 }
 
@@ -215,6 +272,48 @@ func (s *B2BClientService) UnregisterChannel(b2bclntID int64, ch *channel.ZmuxCh
 	s.procmngrs[b2bclntID].Remove(ch.ID)
 }
 
+// supervisor returns the process manager running the B2B client's channels.
+func (s *B2BClientService) supervisor(b2bclntID int64) (*processmgr.ProcessManager2, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	procmngr, ok := s.procmngrs[b2bclntID]
+	return procmngr, ok
+}
+
+// slotUsage returns the slot pool occupancy of each B2B client's process
+// manager.
+func (s *B2BClientService) slotUsage() map[int64]processmgr.SlotUsage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make(map[int64]processmgr.SlotUsage, len(s.procmngrs))
+	for b2bclntID, procmngr := range s.procmngrs {
+		out[b2bclntID] = procmngr.Slots()
+	}
+	return out
+}
+
+// Shutdown stops supervising B2B channels; children are stopped or detached per mode.
+func (s *B2BClientService) Shutdown(ctx context.Context, mode processmgr.ShutdownMode) {
+	s.mu.RLock()
+	procmngrs := make([]*processmgr.ProcessManager2, 0, len(s.procmngrs))
+	for _, procmngr := range s.procmngrs {
+		procmngrs = append(procmngrs, procmngr)
+	}
+	s.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, procmngr := range procmngrs {
+		wg.Add(1)
+		go func(procmngr *processmgr.ProcessManager2) {
+			defer wg.Done()
+			procmngr.Shutdown(ctx, mode)
+		}(procmngr)
+	}
+	wg.Wait()
+}
+
 func (s *B2BClientService) GetOne(id int64) (*b2bclient.B2BClientView, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -226,6 +325,19 @@ func (s *B2BClientService) GetOne(id int64) (*b2bclient.B2BClientView, error) {
 	return s.buildViewUnsafe(val.(*b2bclient.B2BClient)), nil
 }
 
+// GetModel returns the persisted representation of a B2B client (e.g. for
+// audit diffs).
+func (s *B2BClientService) GetModel(id int64) (*b2bclient.B2BClientModel, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	val, ok := s.objs.GetOne(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return val.(*b2bclient.B2BClient).Model(), nil
+}
+
 func (s *B2BClientService) GetMany(ids []int64) ([]*b2bclient.B2BClientView, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -258,6 +370,22 @@ func (s *B2BClientService) GetList() ([]*b2bclient.B2BClientView, error) {
 	return views, nil
 }
 
+// OutputRefUsers returns the IDs of the B2B clients whose quotas, policy or
+// channel template name the output ref.
+func (s *B2BClientService) OutputRefUsers(ref string) []int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids, vals := s.objs.GetList()
+	var out []int64
+	for i, val := range vals {
+		if val.(*b2bclient.B2BClient).UsesOutputRef(ref) {
+			out = append(out, ids[i])
+		}
+	}
+	return out
+}
+
 func (s *B2BClientService) Exists(id int64) bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -288,24 +416,53 @@ func (s *B2BClientService) reconcile(ctx context.Context) error {
 		}
 
 		b2bclnt := b2bclient.NewB2BClient(&model, b2bclntID)
+		if model.BearerToken != "" {
+			// Legacy plaintext token: persist it hashed (see b2bclient.NewB2BClient).
+			raw, err := json.Marshal(b2bclnt.Model())
+			if err != nil {
+				return fmt.Errorf("json marshal: %w", err)
+			}
+			if err := s.ds.Update(ctx, b2bclntID, raw); err != nil {
+				return fmt.Errorf("migrate bearer token: %w", err)
+			}
+			s.log.Info("migrated plaintext bearer token", zap.Int64("id", b2bclntID))
+		}
 		s.objs.Upsert(b2bclntID, b2bclnt)
-		s.byToken[b2bclnt.BearerToken] = b2bclnt
+		s.indexTokensUnsafe(b2bclnt)
 		s.b2bClientEnabledOutputsUsage[b2bclntID] = make(map[string]int64)
-		s.procmngrs[b2bclntID] = processmgr.NewProcessManager2(zap.NewNop(), s.logmngr, b2bclnt.Quotas.OnlineChannels.MaxPreflight, b2bclnt.Quotas.OnlineChannels.Quota)
+		s.procmngrs[b2bclntID] = processmgr.NewProcessManager2(zap.NewNop(), s.logmngr, s.procreg, s.events.ProcessEventHandler(b2bclntID), b2bclnt.Quotas.OnlineChannels.MaxPreflight, b2bclnt.Quotas.OnlineChannels.Quota)
 	}
 
 	return nil
 }
 
-// LookupByToken returns a domain object by bearer token from the in-memory index.
-func (s *B2BClientService) LookupByToken(token string) (*b2bclient.B2BClient, bool) {
+// LookupByToken returns the B2B client and the (unexpired) token matching a
+// bearer token secret.
+//
+// The secret is hashed before the index lookup, so lookup time does not
+// depend on how much of a stored secret a guess matches; the matched hash is
+// then confirmed in constant time.
+func (s *B2BClientService) LookupByToken(secret string) (*b2bclient.B2BClient, *b2bclient.Token, bool) {
+	hash := b2bclient.HashToken(secret)
+
 	s.mu.RLock()
-	b2bClient, ok := s.byToken[token]
+	b2bClient, ok := s.byToken[hash]
 	s.mu.RUnlock()
 	if !ok {
-		return nil, false
+		return nil, nil, false
 	}
-	return b2bClient, true
+
+	now := time.Now()
+	for i := range b2bClient.Tokens {
+		tok := &b2bClient.Tokens[i]
+		if subtle.ConstantTimeCompare([]byte(tok.Hash), []byte(hash)) == 1 {
+			if tok.Expired(now) {
+				return nil, nil, false
+			}
+			return b2bClient, tok, true
+		}
+	}
+	return nil, nil, false
 }
 
 // LookupByChannelID returns a domain object by channel ID from the in-memory index.
@@ -321,7 +478,8 @@ func (s *B2BClientService) LookupByChannelID(chnlID int64) (int64, bool) {
 
 // ----- helpers --------------------------------------------------------------
 
-// genUniqueB2BToken returns a collision-free, URL-safe bearer token prefixed with "b2b_".
+// genUniqueB2BToken returns a collision-free, URL-safe bearer token prefixed
+// with "b2b_"; index is keyed by token hash.
 // Example: b2b_pXGgPptQyC4w2UE1-LyYuwXzle7bnBQ1JmBiY1Ev6xI
 func genUniqueB2BToken(index map[string]*b2bclient.B2BClient) (string, error) {
 	const tokenBytes = 32
@@ -334,7 +492,7 @@ func genUniqueB2BToken(index map[string]*b2bclient.B2BClient) (string, error) {
 		}
 		raw := base64.RawURLEncoding.EncodeToString(b)
 		token := prefix + raw
-		if _, exists := index[token]; exists {
+		if _, exists := index[b2bclient.HashToken(token)]; exists {
 			continue
 		}
 		return token, nil
@@ -359,6 +517,7 @@ func (s *B2BClientService) buildViewUnsafe(b2bclnt *b2bclient.B2BClient) *b2bcli
 		s.b2bClientEnabledOutputsUsage[b2bclnt.ID],
 		s.procmngrs[b2bclnt.ID].Onflight(),
 		s.b2bClientChannelIDs[b2bclnt.ID],
+		time.Now(),
 	)
 }
 
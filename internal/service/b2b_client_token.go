@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	b2bclient "github.com/edirooss/zmux-server/internal/domain/b2b-client"
+	"github.com/edirooss/zmux-server/internal/domain/principal"
+)
+
+// maxB2BClientTokens caps the (unexpired) API tokens of a single B2B client.
+const maxB2BClientTokens = 20
+
+// ListTokens returns the API tokens of a B2B client (never the secrets).
+func (s *B2BClientService) ListTokens(b2bclntID int64) ([]b2bclient.TokenView, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	val, ok := s.objs.GetOne(b2bclntID)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	b2bclnt := val.(*b2bclient.B2BClient)
+
+	now := time.Now()
+	out := make([]b2bclient.TokenView, len(b2bclnt.Tokens))
+	for i, t := range b2bclnt.Tokens {
+		out[i] = t.View(now)
+	}
+	return out, nil
+}
+
+// CreateToken adds an API token to a B2B client (r must be validated). The
+// returned view is the only place the secret is ever exposed.
+//
+// Expired tokens of the client are dropped on the way.
+func (s *B2BClientService) CreateToken(ctx context.Context, b2bclntID int64, r *b2bclient.TokenResource) (*b2bclient.TokenCreatedView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, ok := s.objs.GetOne(b2bclntID)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	b2bclnt := val.(*b2bclient.B2BClient)
+
+	now := time.Now()
+	tokens := liveTokens(b2bclnt.Tokens, now)
+	if len(tokens) >= maxB2BClientTokens {
+		return nil, fmt.Errorf("%w: token limit reached (%d)", ErrConflict, maxB2BClientTokens)
+	}
+
+	var expiresAt int64
+	if r.ExpiresAt != nil {
+		expiresAt = *r.ExpiresAt
+	}
+	secret, tok, err := s.newTokenUnsafe(tokens, r.Name, principal.Scope(r.Scope), expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.replaceTokensUnsafe(ctx, b2bclnt, append(tokens, tok)); err != nil {
+		return nil, err
+	}
+	return &b2bclient.TokenCreatedView{TokenView: tok.View(now), Token: secret}, nil
+}
+
+// RotateToken replaces an API token with a new one of the same name, scope
+// and expiry. The old token stays valid for the overlap window (or its own
+// expiry, whichever is sooner); a zero overlap revokes it immediately.
+func (s *B2BClientService) RotateToken(ctx context.Context, b2bclntID int64, tokenID string, overlap time.Duration) (*b2bclient.TokenCreatedView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, ok := s.objs.GetOne(b2bclntID)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	b2bclnt := val.(*b2bclient.B2BClient)
+
+	now := time.Now()
+	tokens := liveTokens(b2bclnt.Tokens, now)
+	i := slices.IndexFunc(tokens, func(t b2bclient.Token) bool { return t.ID == tokenID })
+	if i < 0 {
+		return nil, fmt.Errorf("token %q: %w", tokenID, ErrNotFound)
+	}
+	old := tokens[i]
+
+	secret, tok, err := s.newTokenUnsafe(tokens, old.Name, old.Scope, old.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if overlap == 0 {
+		tokens = slices.Delete(tokens, i, i+1)
+	} else if until := now.Add(overlap).UnixMilli(); old.ExpiresAt == 0 || until < old.ExpiresAt {
+		tokens[i].ExpiresAt = until
+	}
+
+	if err := s.replaceTokensUnsafe(ctx, b2bclnt, append(tokens, tok)); err != nil {
+		return nil, err
+	}
+	return &b2bclient.TokenCreatedView{TokenView: tok.View(now), Token: secret}, nil
+}
+
+// RevokeToken deletes an API token; it stops authenticating immediately.
+func (s *B2BClientService) RevokeToken(ctx context.Context, b2bclntID int64, tokenID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, ok := s.objs.GetOne(b2bclntID)
+	if !ok {
+		return ErrNotFound
+	}
+	b2bclnt := val.(*b2bclient.B2BClient)
+
+	i := slices.IndexFunc(b2bclnt.Tokens, func(t b2bclient.Token) bool { return t.ID == tokenID })
+	if i < 0 {
+		return fmt.Errorf("token %q: %w", tokenID, ErrNotFound)
+	}
+	return s.replaceTokensUnsafe(ctx, b2bclnt, slices.Delete(slices.Clone(b2bclnt.Tokens), i, i+1))
+}
+
+// newTokenUnsafe generates a token secret and its domain object; the ID is
+// unique among siblings. Must be locked.
+func (s *B2BClientService) newTokenUnsafe(siblings []b2bclient.Token, name string, scope principal.Scope, expiresAt int64) (string, b2bclient.Token, error) {
+	secret, err := genUniqueB2BToken(s.byToken)
+	if err != nil {
+		return "", b2bclient.Token{}, fmt.Errorf("generate token: %w", err)
+	}
+
+	var id string
+	for id == "" || slices.ContainsFunc(siblings, func(t b2bclient.Token) bool { return t.ID == id }) {
+		b := make([]byte, 6)
+		if _, err := rand.Read(b); err != nil {
+			return "", b2bclient.Token{}, fmt.Errorf("rand read: %w", err)
+		}
+		id = hex.EncodeToString(b)
+	}
+
+	return secret, b2bclient.Token{
+		ID:        id,
+		Name:      name,
+		Hash:      b2bclient.HashToken(secret),
+		Scope:     scope,
+		CreatedAt: time.Now().UnixMilli(),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// replaceTokensUnsafe persists the client with the given token set and swaps
+// the in-memory object (copy-on-write; LookupByToken callers may hold the
+// old one). Must be locked.
+func (s *B2BClientService) replaceTokensUnsafe(ctx context.Context, b2bclnt *b2bclient.B2BClient, tokens []b2bclient.Token) error {
+	next := *b2bclnt
+	next.Tokens = tokens
+
+	raw, err := json.Marshal(next.Model())
+	if err != nil {
+		return fmt.Errorf("json marshal: %w", err)
+	}
+	if err := s.ds.Update(ctx, next.ID, raw); err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+
+	s.unindexTokensUnsafe(b2bclnt)
+	s.objs.Upsert(next.ID, &next)
+	s.indexTokensUnsafe(&next)
+	return nil
+}
+
+// indexTokensUnsafe adds the client's tokens to the hash index. Must be locked.
+func (s *B2BClientService) indexTokensUnsafe(b2bclnt *b2bclient.B2BClient) {
+	for _, t := range b2bclnt.Tokens {
+		s.byToken[t.Hash] = b2bclnt
+	}
+}
+
+// unindexTokensUnsafe removes the client's tokens from the hash index. Must be locked.
+func (s *B2BClientService) unindexTokensUnsafe(b2bclnt *b2bclient.B2BClient) {
+	for _, t := range b2bclnt.Tokens {
+		delete(s.byToken, t.Hash)
+	}
+}
+
+// liveTokens returns a copy of tokens without the expired ones.
+func liveTokens(tokens []b2bclient.Token, now time.Time) []b2bclient.Token {
+	out := make([]b2bclient.Token, 0, len(tokens)+1)
+	for _, t := range tokens {
+		if !t.Expired(now) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
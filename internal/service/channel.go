@@ -13,6 +13,7 @@ import (
 	"github.com/edirooss/zmux-server/internal/infrastructure/datastore"
 	"github.com/edirooss/zmux-server/internal/infrastructure/objectstore"
 	"github.com/edirooss/zmux-server/internal/infrastructure/processmgr"
+	"github.com/edirooss/zmux-server/pkg/envelope"
 	"github.com/edirooss/zmux-server/pkg/remuxcmd"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
@@ -35,9 +36,13 @@ type ChannelService struct {
 	ds         *datastore.DataStore     // Redis-based persistent store
 	objs       *objectstore.ObjectStore // in-memory object store
 	procmngr   *processmgr.ProcessManager
+	events     *ChannelEventHub          // config change notifications
+	webhooks   *WebhookService           // quota-exceeded notifications
+	keys       *envelope.Keyring         // encrypts stored input passwords
+	outdests   *OutputDestinationService // output localaddr restrictions
 }
 
-func NewChannelService(ctx context.Context, log *zap.Logger, rdb *redis.Client, b2bclntsvc *B2BClientService, logmngr *processmgr.LogManager) (*ChannelService, error) {
+func NewChannelService(ctx context.Context, log *zap.Logger, rdb *redis.Client, b2bclntsvc *B2BClientService, logmngr *processmgr.LogManager, procreg *processmgr.Registry, events *ChannelEventHub, webhooks *WebhookService, keys *envelope.Keyring, outdests *OutputDestinationService) (*ChannelService, error) {
 	if log == nil {
 		log = zap.NewNop()
 	}
@@ -55,7 +60,11 @@ func NewChannelService(ctx context.Context, log *zap.Logger, rdb *redis.Client,
 		b2bclntsvc: b2bclntsvc,
 		ds:         ds,
 		objs:       objectstore.NewObjectStore(log),
-		procmngr:   processmgr.NewProcessManager(log, logmngr),
+		procmngr:   processmgr.NewProcessManager(log, logmngr, procreg, events.ProcessEventHandler(0)),
+		events:     events,
+		webhooks:   webhooks,
+		keys:       keys,
+		outdests:   outdests,
 	}
 
 	if err := svc.reconcile(ctx); err != nil {
@@ -66,9 +75,13 @@ func NewChannelService(ctx context.Context, log *zap.Logger, rdb *redis.Client,
 }
 
 func (s *ChannelService) Create(ctx context.Context, ch *channel.ZmuxChannel) error {
-	rawCh, err := json.Marshal(ch.Model())
+	rawCh, err := s.marshal(ch)
 	if err != nil {
-		return fmt.Errorf("json marshal: %w", err)
+		return err
+	}
+
+	if err := s.outdests.CheckOutputs(nil, ch); err != nil {
+		return err
 	}
 
 	s.mu.Lock()
@@ -81,7 +94,11 @@ func (s *ChannelService) Create(ctx context.Context, ch *channel.ZmuxChannel) er
 			return fmt.Errorf("b2b client not found")
 		}
 
+		if err := b2bclnt.Policy.Check(nil, ch); err != nil {
+			return err
+		}
 		if err := enforceQuotaOnCreate(b2bclnt, ch); err != nil {
+			s.webhooks.notifyQuotaExceeded(ch, err)
 			return err
 		}
 	}
@@ -91,6 +108,7 @@ func (s *ChannelService) Create(ctx context.Context, ch *channel.ZmuxChannel) er
 	}
 	ch.ID = chID
 	s.objs.Upsert(chID, ch)
+	s.events.PublishConfig("created", ch)
 
 	if ch.B2BClientID != nil {
 		s.b2bclntsvc.RegisterChannel(*ch.B2BClientID, ch)
@@ -98,15 +116,15 @@ func (s *ChannelService) Create(ctx context.Context, ch *channel.ZmuxChannel) er
 	}
 
 	if ch.Enabled {
-		s.procmngr.Add(ch.ID, remuxcmd.BuildArgv(ch), time.Duration(ch.RestartSec)*time.Second)
+		s.procmngr.Add(ch.ID, remuxcmd.BuildArgv(ch), remuxcmd.BuildEnv(ch), restartPolicy(ch))
 	}
 	return nil
 }
 
 func (s *ChannelService) Update(ctx context.Context, ch *channel.ZmuxChannel) error {
-	rawCh, err := json.Marshal(ch.Model())
+	rawCh, err := s.marshal(ch)
 	if err != nil {
-		return fmt.Errorf("json marshal: %w", err)
+		return err
 	}
 
 	s.mu.Lock()
@@ -118,6 +136,10 @@ func (s *ChannelService) Update(ctx context.Context, ch *channel.ZmuxChannel) er
 	}
 	curCh := curVal.(*channel.ZmuxChannel)
 
+	if err := s.outdests.CheckOutputs(curCh, ch); err != nil {
+		return err
+	}
+
 	if ch.B2BClientID != nil {
 		b2bclntID := *ch.B2BClientID
 		b2bclnt, err := s.b2bclntsvc.GetOne(b2bclntID)
@@ -126,11 +148,19 @@ func (s *ChannelService) Update(ctx context.Context, ch *channel.ZmuxChannel) er
 		}
 
 		if curCh.B2BClientID != nil && *ch.B2BClientID == *curCh.B2BClientID {
+			if err := b2bclnt.Policy.Check(curCh, ch); err != nil {
+				return err
+			}
 			if err := enforceQuotaOnUpdate(b2bclnt, curCh, ch); err != nil {
+				s.webhooks.notifyQuotaExceeded(ch, err)
 				return err
 			}
 		} else {
+			if err := b2bclnt.Policy.Check(nil, ch); err != nil {
+				return err
+			}
 			if err := enforceQuotaOnCreate(b2bclnt, ch); err != nil {
+				s.webhooks.notifyQuotaExceeded(ch, err)
 				return err
 			}
 		}
@@ -141,6 +171,21 @@ func (s *ChannelService) Update(ctx context.Context, ch *channel.ZmuxChannel) er
 	}
 
 	s.objs.Upsert(ch.ID, ch)
+	if prev := ownerOf(curCh); prev != 0 && prev != ownerOf(ch) {
+		s.events.PublishOwnershipRevoked(ch.ID, prev)
+	}
+	s.events.PublishConfig("updated", ch)
+
+	// Same supervisor: keep the running process unless its command line or
+	// credentials changed (a rename must not glitch the stream).
+	switch {
+	case curCh.B2BClientID != nil && ch.B2BClientID != nil && *curCh.B2BClientID == *ch.B2BClientID:
+		s.b2bclntsvc.ReplaceChannel(*ch.B2BClientID, curCh, ch)
+		return nil
+	case curCh.B2BClientID == nil && ch.B2BClientID == nil && curCh.Enabled && ch.Enabled:
+		s.procmngr.Replace(ch.ID, remuxcmd.BuildArgv(ch), remuxcmd.BuildEnv(ch), restartPolicy(ch))
+		return nil
+	}
 
 	if curCh.B2BClientID != nil {
 		s.b2bclntsvc.UnregisterChannel(*curCh.B2BClientID, curCh)
@@ -156,7 +201,7 @@ func (s *ChannelService) Update(ctx context.Context, ch *channel.ZmuxChannel) er
 	}
 
 	if ch.Enabled {
-		s.procmngr.Add(ch.ID, remuxcmd.BuildArgv(ch), time.Duration(ch.RestartSec)*time.Second)
+		s.procmngr.Add(ch.ID, remuxcmd.BuildArgv(ch), remuxcmd.BuildEnv(ch), restartPolicy(ch))
 	}
 
 	return nil
@@ -178,6 +223,32 @@ func (s *ChannelService) GetLogs(ctx context.Context, id int64) ([]string, error
 	return logbuf.Read(0), nil
 }
 
+// GetLogsRange returns up to limit log entries of the channel logged within
+// [since, until] (newest → oldest), falling back to on-disk logs when the
+// window predates the in-memory buffer.
+func (s *ChannelService) GetLogsRange(ctx context.Context, id int64, since, until time.Time, limit int) ([]string, error) {
+	if _, ok := s.objs.GetOne(id); !ok {
+		return nil, ErrNotFound
+	}
+	return s.logmngr.ReadRange(id, since, until, limit)
+}
+
+// SubscribeLogs opens a live tail of the channel's log buffer (see
+// processmgr.LogSubscription). The backlog is oldest → newest. The
+// subscription is closed when the channel is deleted; callers must
+// Unsubscribe via the returned function.
+func (s *ChannelService) SubscribeLogs(id int64, queueLen int) (*processmgr.LogSubscription, []string, func(), error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.objs.GetOne(id); !ok {
+		return nil, nil, nil, ErrNotFound
+	}
+	logbuf := s.logmngr.Get(id)
+	sub, backlog := logbuf.Subscribe(queueLen)
+	return sub, backlog, func() { logbuf.Unsubscribe(sub) }, nil
+}
+
 func (s *ChannelService) GetList(ctx context.Context) ([]*channel.ZmuxChannel, error) {
 	_, vals := s.objs.GetList()
 	if len(vals) == 0 {
@@ -225,6 +296,8 @@ func (s *ChannelService) Delete(ctx context.Context, id int64) error {
 		return fmt.Errorf("delete: %w", err)
 	}
 	s.objs.Delete(id)
+	s.logmngr.Remove(id) // ends live log tails
+	s.events.PublishConfig("deleted", ch)
 
 	if ch.B2BClientID != nil {
 		s.b2bclntsvc.UnregisterChannel(*ch.B2BClientID, ch)
@@ -237,14 +310,21 @@ func (s *ChannelService) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
+// Shutdown stops supervising non-B2B channels; children are stopped or detached per mode.
+func (s *ChannelService) Shutdown(ctx context.Context, mode processmgr.ShutdownMode) {
+	s.procmngr.Shutdown(ctx, mode)
+}
+
 func (s *ChannelService) reconcile(ctx context.Context) error {
 	ids, chsBytes, err := s.ds.GetList(ctx)
 	if err != nil {
 		return fmt.Errorf("get list: %w", err)
 	}
 
+	var rewrap []*channel.ZmuxChannel // plaintext, or sealed under a non-primary key
 	for i, id := range ids {
 		ch := &channel.ZmuxChannel{}
+		ch.SetRestartPolicyDefaults() // documents predating the policy fields
 		if err := json.Unmarshal(chsBytes[i], ch); err != nil {
 			// Data corruption detected - should never happen in normal operation.
 			// Possible causes: manual Redis edits, serialization bugs, bit flips.
@@ -255,6 +335,17 @@ func (s *ChannelService) reconcile(ctx context.Context) error {
 			return fmt.Errorf("json unmarshal: %w", err)
 		}
 		ch.ID = id
+
+		if ch.Input.Password != nil {
+			if s.keys.NeedsRewrap(*ch.Input.Password) {
+				rewrap = append(rewrap, ch)
+			}
+			password, err := s.keys.Open(*ch.Input.Password)
+			if err != nil {
+				return fmt.Errorf("channel %d: open input password: %w", id, err)
+			}
+			ch.Input.Password = &password
+		}
 		s.objs.Upsert(id, ch)
 
 		if ch.B2BClientID != nil {
@@ -263,10 +354,59 @@ func (s *ChannelService) reconcile(ctx context.Context) error {
 		}
 
 		if ch.Enabled {
-			s.procmngr.Add(ch.ID, remuxcmd.BuildArgv(ch), time.Duration(ch.RestartSec)*time.Second)
+			s.procmngr.Add(ch.ID, remuxcmd.BuildArgv(ch), remuxcmd.BuildEnv(ch), restartPolicy(ch))
+		}
+	}
+
+	// Re-seal under the primary key. Best effort: a failure leaves the
+	// previous (still readable) value in place until the next start.
+	rewrapped := 0
+	for _, ch := range rewrap {
+		rawCh, err := s.marshal(ch)
+		if err == nil {
+			err = s.ds.Update(ctx, ch.ID, rawCh)
+		}
+		if err != nil {
+			s.log.Warn("input password re-encryption failed", zap.Int64("id", ch.ID), zap.Error(err))
+			continue
+		}
+		rewrapped++
+	}
+	if rewrapped > 0 {
+		s.log.Info("re-encrypted stored input passwords", zap.Int("count", rewrapped))
+	}
+
+	return nil
+}
+
+// marshal encodes the persistence model of ch with the input password sealed.
+func (s *ChannelService) marshal(ch *channel.ZmuxChannel) ([]byte, error) {
+	m := ch.Model()
+	if m.Input.Password != nil {
+		sealed, err := s.keys.Seal(*m.Input.Password)
+		if err != nil {
+			return nil, fmt.Errorf("seal input password: %w", err)
 		}
+		m.Input.Password = &sealed
 	}
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("json marshal: %w", err)
+	}
+	return raw, nil
+}
 
+func checkChannelsQuota(b2bclnt *b2bclient.B2BClientView) error {
+	q := b2bclnt.Quotas.Channels
+	if q.Quota != nil && q.Usage+1 > *q.Quota {
+		return &QuotaExceededError{
+			ClientName: b2bclnt.Name,
+			ClientID:   b2bclnt.ID,
+			Resource:   "channel",
+			Usage:      q.Usage + 1,
+			Quota:      *q.Quota,
+		}
+	}
 	return nil
 }
 
@@ -309,6 +449,9 @@ func checkEnabledOutputQuotas(b2bclnt *b2bclient.B2BClientView, ch *channel.Zmux
 }
 
 func enforceQuotaOnCreate(b2bclnt *b2bclient.B2BClientView, ch *channel.ZmuxChannel) error {
+	if err := checkChannelsQuota(b2bclnt); err != nil {
+		return err
+	}
 	if err := checkEnabledChannelQuota(b2bclnt, ch); err != nil {
 		return err
 	}
@@ -356,7 +499,7 @@ var ErrQuotaExceeded = errors.New("quota exceeded")
 type QuotaExceededError struct {
 	ClientName string
 	ClientID   int64
-	Resource   string // e.g., "enabled channel", "enabled output 'ref'"
+	Resource   string // e.g., "channel", "enabled channel", "enabled output 'ref'"
 	Usage      int64
 	Quota      int64
 }
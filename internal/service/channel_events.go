@@ -0,0 +1,468 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edirooss/zmux-server/internal/domain/channel"
+	"github.com/edirooss/zmux-server/internal/domain/principal"
+	"github.com/edirooss/zmux-server/internal/infrastructure/processmgr"
+	"go.uber.org/zap"
+)
+
+// ChannelEventType is the SSE event name of a ChannelEvent.
+type ChannelEventType string
+
+const (
+	ChannelEventStatus  ChannelEventType = "status"  // remux status transition (online/offline, event message)
+	ChannelEventConfig  ChannelEventType = "config"  // channel created/updated/deleted
+	ChannelEventProcess ChannelEventType = "process" // remux process lifecycle (started, exited, ...)
+)
+
+// ChannelEvent is a single entry of the channel event stream.
+//
+// Exactly one of Status, Config, Process is set (matching Type).
+type ChannelEvent struct {
+	ID        string           `json:"-"` // SSE id; "<boot>-<seq>" (see ChannelEventHub)
+	Type      ChannelEventType `json:"-"` // SSE event name
+	ChannelID int64            `json:"channel_id"`
+	At        int64            `json:"at"` // UTC millis
+
+	Status  *ChannelStatusChange  `json:"status,omitempty"`
+	Config  *ChannelConfigChange  `json:"config,omitempty"`
+	Process *ChannelProcessChange `json:"process,omitempty"`
+
+	// Audience
+	owner     int64 // owning B2B client ID; 0 for channels without an owner
+	ownerOnly bool  // hidden from admins (ownership hand-over notice for the previous owner)
+}
+
+// ChannelStatusChange mirrors the remux status after a transition.
+type ChannelStatusChange struct {
+	Online  bool   `json:"online"`
+	Message string `json:"msg"`      // step label OR error message
+	EventAt int64  `json:"event_at"` // UTC millis when remux recorded the message
+
+	onlineChanged bool // online flipped (first sighting: came up online)
+}
+
+// ChannelConfigChange reports a persisted channel change.
+//
+// Channel holds the principal-specific view (admin or B2B client) and is
+// absent for deletions. Consumers should treat "created"/"updated" as upserts.
+type ChannelConfigChange struct {
+	Action  string `json:"action"` // created | updated | deleted
+	Channel any    `json:"channel,omitempty"`
+
+	ch *channel.ZmuxChannel // rendered per subscriber
+}
+
+// ChannelProcessChange reports a remux process lifecycle transition.
+type ChannelProcessChange struct {
+	State       string `json:"state"`                   // started | start_failed | adopted | active | exited | failed
+	CmdPID      int    `json:"cmd_pid,omitempty"`       // OS pid
+	ExitCode    *int   `json:"exit_code,omitempty"`     // exited only; absent when unknown or signaled
+	ExitSignal  string `json:"exit_signal,omitempty"`   // exited only
+	Restarting  bool   `json:"restarting,omitempty"`    // a relaunch is scheduled
+	RestartInMs int64  `json:"restart_in_ms,omitempty"` // delay until the relaunch
+}
+
+// ChannelEventHubOptions tunes the hub.
+type ChannelEventHubOptions struct {
+	// Backlog is the number of recent events kept for Last-Event-ID resume.
+	Backlog int
+	// SubscriberBuffer bounds the per-subscriber queue; a subscriber that
+	// falls this far behind is disconnected (it can resume via Last-Event-ID).
+	SubscriberBuffer int
+	// StatusInterval is the remux status polling period of WatchStatuses.
+	StatusInterval time.Duration
+	// B2BOutputVisible reports whether B2B clients see outputs to a ref
+	// (see OutputDestinationService.B2BVisible); none when nil.
+	B2BOutputVisible func(ref string) bool
+}
+
+func (o *ChannelEventHubOptions) setDefaults() {
+	if o.Backlog <= 0 {
+		o.Backlog = 1024
+	}
+	if o.SubscriberBuffer <= 0 {
+		o.SubscriberBuffer = 256
+	}
+	if o.StatusInterval <= 0 {
+		o.StatusInterval = time.Second
+	}
+	if o.B2BOutputVisible == nil {
+		o.B2BOutputVisible = func(string) bool { return false }
+	}
+}
+
+// ChannelEventHub fans channel events out to subscribers (SSE streams).
+//
+// Sources:
+//   - ChannelService publishes config changes
+//   - process managers publish lifecycle events (ProcessEventHandler)
+//   - WatchStatuses polls remux statuses once for all subscribers and
+//     publishes transitions
+//
+// Event IDs are "<boot>-<seq>": seq is monotonic within one server instance
+// and boot identifies the instance, so a Last-Event-ID from before a restart
+// (or older than the backlog) is detected and answered with a resync.
+type ChannelEventHub struct {
+	log  *zap.Logger
+	opts ChannelEventHubOptions
+	boot string
+
+	mu      sync.Mutex
+	seq     uint64
+	backlog []ChannelEvent // ring; oldest at backlog[head] once full
+	head    int
+	subs    map[*ChannelEventSubscription]struct{}
+	closed  bool
+
+	observers []func(ChannelEvent) // in-process consumers (see Observe)
+}
+
+// NewChannelEventHub constructs an empty hub.
+func NewChannelEventHub(log *zap.Logger, opts ChannelEventHubOptions) *ChannelEventHub {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	opts.setDefaults()
+
+	return &ChannelEventHub{
+		log:     log.Named("channel-events"),
+		opts:    opts,
+		boot:    strconv.FormatInt(time.Now().UnixMilli(), 36),
+		backlog: make([]ChannelEvent, 0, opts.Backlog),
+		subs:    make(map[*ChannelEventSubscription]struct{}),
+	}
+}
+
+// ChannelEventSubscription is a live, principal-scoped view of the hub.
+type ChannelEventSubscription struct {
+	p      *principal.Principal
+	b2bID  int64 // parsed p.ID for B2B clients
+	ch     chan ChannelEvent
+	closed bool // guarded by hub.mu
+
+	b2bOutputVisible func(ref string) bool // see ChannelEventHubOptions
+}
+
+// Events delivers events in publish order. The channel is closed when the
+// subscriber falls behind, is unsubscribed, or the hub closes.
+func (s *ChannelEventSubscription) Events() <-chan ChannelEvent { return s.ch }
+
+// Subscribe registers a subscriber for p.
+//
+// lastEventID is the SSE Last-Event-ID (empty for a fresh stream). The
+// returned backlog holds the missed events visible to p; resync reports that
+// the gap could not be filled and the client should reload its snapshot.
+func (h *ChannelEventHub) Subscribe(p *principal.Principal, lastEventID string) (sub *ChannelEventSubscription, backlog []ChannelEvent, resync bool) {
+	sub = &ChannelEventSubscription{
+		p:  p,
+		ch: make(chan ChannelEvent, h.opts.SubscriberBuffer),
+
+		b2bOutputVisible: h.opts.B2BOutputVisible,
+	}
+	if p.Kind == principal.B2BClient {
+		sub.b2bID, _ = strconv.ParseInt(p.ID, 10, 64)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		sub.closed = true
+		close(sub.ch)
+		return sub, nil, false
+	}
+	h.subs[sub] = struct{}{}
+
+	if lastEventID == "" {
+		return sub, nil, false
+	}
+
+	seq, ok := h.parseIDUnsafe(lastEventID)
+	if !ok {
+		return sub, nil, true
+	}
+	oldest := h.seq - uint64(len(h.backlog)) + 1 // seq of the oldest retained event
+	if seq+1 < oldest {
+		return sub, nil, true // gap fell out of the backlog
+	}
+
+	for i := range h.backlog {
+		ev := h.backlog[(h.head+i)%len(h.backlog)]
+		if seqOf(ev.ID) <= seq {
+			continue
+		}
+		if out, ok := sub.render(ev); ok {
+			backlog = append(backlog, out)
+		}
+	}
+	return sub, backlog, false
+}
+
+// Unsubscribe removes sub; safe to call more than once.
+func (h *ChannelEventHub) Unsubscribe(sub *ChannelEventSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.dropUnsafe(sub)
+}
+
+// Close disconnects all subscribers and rejects new ones.
+// Call on server shutdown so long-lived streams do not hold up the HTTP drain.
+func (h *ChannelEventHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for sub := range h.subs {
+		h.dropUnsafe(sub)
+	}
+}
+
+// Observe registers fn to receive every published event, unfiltered and
+// unrendered. fn is called with the hub lock held: it must not block and
+// must not call back into the hub.
+func (h *ChannelEventHub) Observe(fn func(ChannelEvent)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.observers = append(h.observers, fn)
+}
+
+// Publish stamps ev with an ID, stores it in the backlog and delivers it to
+// every subscriber allowed to see it. Never blocks.
+func (h *ChannelEventHub) Publish(ev ChannelEvent) {
+	if ev.At == 0 {
+		ev.At = time.Now().UnixMilli()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seq++
+	ev.ID = h.boot + "-" + strconv.FormatUint(h.seq, 10)
+
+	if len(h.backlog) < h.opts.Backlog {
+		h.backlog = append(h.backlog, ev)
+	} else {
+		h.backlog[h.head] = ev
+		h.head = (h.head + 1) % len(h.backlog)
+	}
+
+	for _, fn := range h.observers {
+		fn(ev)
+	}
+
+	for sub := range h.subs {
+		out, ok := sub.render(ev)
+		if !ok {
+			continue
+		}
+		select {
+		case sub.ch <- out:
+		default:
+			h.log.Warn("subscriber fell behind; disconnecting",
+				zap.String("principal_id", sub.p.ID),
+				zap.String("principal_kind", sub.p.Kind.String()))
+			h.dropUnsafe(sub)
+		}
+	}
+}
+
+// PublishConfig publishes a config change of ch ("created", "updated", "deleted").
+func (h *ChannelEventHub) PublishConfig(action string, ch *channel.ZmuxChannel) {
+	ev := ChannelEvent{
+		Type:      ChannelEventConfig,
+		ChannelID: ch.ID,
+		Config:    &ChannelConfigChange{Action: action},
+		owner:     ownerOf(ch),
+	}
+	if action != "deleted" {
+		ev.Config.ch = ch
+	}
+	h.Publish(ev)
+}
+
+// PublishOwnershipRevoked tells the previous owner of a channel that it is
+// gone from their scope (admins see the regular "updated" event instead).
+func (h *ChannelEventHub) PublishOwnershipRevoked(chID, prevOwner int64) {
+	h.Publish(ChannelEvent{
+		Type:      ChannelEventConfig,
+		ChannelID: chID,
+		Config:    &ChannelConfigChange{Action: "deleted"},
+		owner:     prevOwner,
+		ownerOnly: true,
+	})
+}
+
+// ProcessEventHandler adapts process manager lifecycle events for channels
+// owned by b2bclntID (0: no owner). Events are also counted for /metrics.
+func (h *ChannelEventHub) ProcessEventHandler(b2bclntID int64) processmgr.EventHandler {
+	return func(pev processmgr.Event) {
+		countProcessEvent(b2bclntID, pev)
+		h.Publish(ChannelEvent{
+			Type:      ChannelEventProcess,
+			ChannelID: pev.UID,
+			At:        pev.At.UnixMilli(),
+			Process: &ChannelProcessChange{
+				State:       string(pev.Kind),
+				CmdPID:      pev.CmdPID,
+				ExitCode:    pev.ExitCode,
+				ExitSignal:  pev.ExitSignal,
+				Restarting:  pev.Restarting,
+				RestartInMs: pev.RestartIn.Milliseconds(),
+			},
+			owner: b2bclntID,
+		})
+	}
+}
+
+// WatchStatuses polls remux statuses of enabled channels every
+// StatusInterval (a single MGET regardless of the number of subscribers) and
+// publishes a status event whenever online state or the event message
+// changes. The first pass only records a baseline. Blocks until ctx is done.
+func (h *ChannelEventHub) WatchStatuses(ctx context.Context, chnlsvc *ChannelService, repo *RemuxRepository) {
+	ticker := time.NewTicker(h.opts.StatusInterval)
+	defer ticker.Stop()
+
+	var last map[int64]RemuxStatus // nil until the baseline pass
+	for {
+		next, err := h.pollStatuses(ctx, chnlsvc, repo, last)
+		if err != nil {
+			h.log.Warn("status poll failed", zap.Error(err))
+		} else {
+			last = next
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *ChannelEventHub) pollStatuses(ctx context.Context, chnlsvc *ChannelService, repo *RemuxRepository, last map[int64]RemuxStatus) (map[int64]RemuxStatus, error) {
+	chs, err := chnlsvc.GetList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list channels: %w", err)
+	}
+
+	ids := make([]string, 0, len(chs))
+	byRemuxID := make(map[string]*channel.ZmuxChannel, len(chs))
+	for _, ch := range chs {
+		if ch.Enabled {
+			id := remuxID(ch)
+			ids = append(ids, id)
+			byRemuxID[id] = ch
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.opts.StatusInterval)
+	defer cancel()
+
+	statuses, err := repo.GetStatusesByID(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("get statuses: %w", err)
+	}
+
+	next := make(map[int64]RemuxStatus, len(statuses))
+	for id, st := range statuses {
+		ch := byRemuxID[id]
+		next[ch.ID] = *st
+
+		if last == nil {
+			continue // baseline
+		}
+		prev, seen := last[ch.ID]
+		if seen && prev.Online == st.Online && prev.Event.Message == st.Event.Message {
+			continue
+		}
+		h.Publish(ChannelEvent{
+			Type:      ChannelEventStatus,
+			ChannelID: ch.ID,
+			Status: &ChannelStatusChange{
+				Online:  st.Online,
+				Message: st.Event.Message,
+				EventAt: st.Event.At,
+
+				onlineChanged: seen && prev.Online != st.Online || !seen && st.Online,
+			},
+			owner: ownerOf(ch),
+		})
+	}
+	return next, nil
+}
+
+// ----- helpers --------------------------------------------------------------
+
+// render returns ev as seen by the subscriber (false: not visible).
+func (s *ChannelEventSubscription) render(ev ChannelEvent) (ChannelEvent, bool) {
+	switch s.p.Kind {
+	case principal.Admin:
+		if ev.ownerOnly {
+			return ev, false
+		}
+		if ev.Config != nil && ev.Config.ch != nil {
+			cfg := *ev.Config
+			cfg.Channel = cfg.ch.AdminView()
+			ev.Config = &cfg
+		}
+		return ev, true
+
+	case principal.B2BClient:
+		if ev.owner == 0 || ev.owner != s.b2bID {
+			return ev, false
+		}
+		if ev.Config != nil && ev.Config.ch != nil {
+			cfg := *ev.Config
+			cfg.Channel = cfg.ch.B2BClientView(s.b2bOutputVisible)
+			ev.Config = &cfg
+		}
+		return ev, true
+	}
+	return ev, false
+}
+
+// dropUnsafe closes and removes sub. Caller must hold h.mu.
+func (h *ChannelEventHub) dropUnsafe(sub *ChannelEventSubscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(h.subs, sub)
+	close(sub.ch)
+}
+
+// parseIDUnsafe extracts the sequence number of an ID issued by this instance.
+func (h *ChannelEventHub) parseIDUnsafe(id string) (uint64, bool) {
+	boot, seqStr, ok := strings.Cut(id, "-")
+	if !ok || boot != h.boot {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	if err != nil || seq > h.seq {
+		return 0, false
+	}
+	return seq, true
+}
+
+// seqOf extracts the sequence number of a well-formed event ID.
+func seqOf(id string) uint64 {
+	_, seqStr, _ := strings.Cut(id, "-")
+	seq, _ := strconv.ParseUint(seqStr, 10, 64)
+	return seq
+}
+
+func ownerOf(ch *channel.ZmuxChannel) int64 {
+	if ch.B2BClientID == nil {
+		return 0
+	}
+	return *ch.B2BClientID
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/edirooss/zmux-server/internal/infrastructure/telemetry"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	channelHistoryKeyPrefix = "zmux:channel_events:" // zmux:channel_events:<id> → STREAM of {v: JSON(ChannelHistoryEntry)}
+
+	// channelHistoryWriteTimeout bounds a single XADD/DEL.
+	channelHistoryWriteTimeout = 2 * time.Second
+)
+
+// ChannelHistoryOptions tunes the history streams.
+type ChannelHistoryOptions struct {
+	// MaxLen is the approximate number of entries kept per channel.
+	MaxLen int64
+	// QueueSize bounds events pending a write; events beyond it are dropped.
+	QueueSize int
+}
+
+func (o *ChannelHistoryOptions) setDefaults() {
+	if o.MaxLen <= 0 {
+		o.MaxLen = 1000
+	}
+	if o.QueueSize <= 0 {
+		o.QueueSize = 1024
+	}
+}
+
+// ChannelHistoryEntry is a recorded channel event.
+//
+// Config entries carry the action only; the channel itself is not recorded.
+type ChannelHistoryEntry struct {
+	ID   string           `json:"id"` // stream entry ID; pass as ?since= to read on from here
+	Type ChannelEventType `json:"type"`
+	At   int64            `json:"at"` // UTC millis
+
+	Status  *ChannelStatusChange  `json:"status,omitempty"`
+	Config  *ChannelConfigChange  `json:"config,omitempty"`
+	Process *ChannelProcessChange `json:"process,omitempty"`
+}
+
+// b2bVisible reports whether the entry is user-facing (shown to B2B
+// clients): status transitions and config changes. Process lifecycle
+// events are supervisor internals.
+func (e *ChannelHistoryEntry) b2bVisible() bool {
+	return e.Type == ChannelEventStatus || e.Type == ChannelEventConfig
+}
+
+// ChannelHistory records status transitions, process lifecycle events and
+// config changes of each channel into a bounded Redis stream.
+//
+// Events are taken from the hub and written asynchronously, in publish
+// order; a channel's stream is removed when the channel is deleted.
+type ChannelHistory struct {
+	log   *zap.Logger
+	rdb   *redis.Client
+	opts  ChannelHistoryOptions
+	queue chan ChannelEvent
+}
+
+// NewChannelHistory constructs a ChannelHistory recording events of hub.
+// Call Run to start writing.
+func NewChannelHistory(log *zap.Logger, rdb *redis.Client, hub *ChannelEventHub, opts ChannelHistoryOptions) *ChannelHistory {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	opts.setDefaults()
+
+	h := &ChannelHistory{
+		log:   log.Named("channel-history"),
+		rdb:   rdb,
+		opts:  opts,
+		queue: make(chan ChannelEvent, opts.QueueSize),
+	}
+
+	hub.Observe(func(ev ChannelEvent) {
+		if ev.ownerOnly {
+			return // audience-specific notice, not a channel change
+		}
+		select {
+		case h.queue <- ev:
+		default:
+			h.log.Warn("history queue full; event dropped",
+				zap.Int64("channel_id", ev.ChannelID),
+				zap.String("type", string(ev.Type)))
+		}
+	})
+	return h
+}
+
+// Run writes queued events until ctx is done.
+func (h *ChannelHistory) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-h.queue:
+			h.write(ctx, ev)
+		}
+	}
+}
+
+func (h *ChannelHistory) write(ctx context.Context, ev ChannelEvent) {
+	ctx, cancel := context.WithTimeout(ctx, channelHistoryWriteTimeout)
+	defer cancel()
+
+	key := channelHistoryKey(ev.ChannelID)
+	if ev.Config != nil && ev.Config.Action == "deleted" {
+		start := time.Now()
+		err := h.rdb.Del(ctx, key).Err()
+		telemetry.ObserveRedis("channel_history", "del", start, err)
+		if err != nil {
+			h.log.Warn("history cleanup failed", zap.Int64("channel_id", ev.ChannelID), zap.Error(err))
+		}
+		return
+	}
+
+	entry := ChannelHistoryEntry{
+		Type:    ev.Type,
+		At:      ev.At,
+		Status:  ev.Status,
+		Process: ev.Process,
+	}
+	if ev.Config != nil {
+		entry.Config = &ChannelConfigChange{Action: ev.Config.Action}
+	}
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		h.log.Error("json marshal failed", zap.Error(err))
+		return
+	}
+
+	start := time.Now()
+	err = h.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		MaxLen: h.opts.MaxLen,
+		Approx: true,
+		Values: []string{"v", string(raw)},
+	}).Err()
+	telemetry.ObserveRedis("channel_history", "xadd", start, err)
+	if err != nil {
+		h.log.Warn("history write failed", zap.Int64("channel_id", ev.ChannelID), zap.Error(err))
+	}
+}
+
+// Get returns up to limit recorded events of a channel, oldest first.
+//
+// since selects the starting point: empty returns the most recent events;
+// an entry ID (as returned in ChannelHistoryEntry.ID) returns the events
+// after it; a UTC millis timestamp returns the events at or after it.
+// b2bOnly restricts the result to user-facing events.
+func (h *ChannelHistory) Get(ctx context.Context, chID int64, since string, limit int, b2bOnly bool) ([]ChannelHistoryEntry, error) {
+	key := channelHistoryKey(chID)
+	out := make([]ChannelHistoryEntry, 0, limit)
+
+	if since == "" {
+		// Page backwards from the newest entry, then restore chronological order.
+		end := "+"
+		for len(out) < limit {
+			msgs, err := h.xrange(ctx, "xrevrange", func() ([]redis.XMessage, error) {
+				return h.rdb.XRevRangeN(ctx, key, end, "-", int64(limit)).Result()
+			})
+			if err != nil {
+				return nil, err
+			}
+			out = appendHistory(out, msgs, limit, b2bOnly)
+			if len(msgs) < limit {
+				break
+			}
+			end = "(" + msgs[len(msgs)-1].ID
+		}
+		slices.Reverse(out)
+		return out, nil
+	}
+
+	start := since
+	if strings.Contains(since, "-") {
+		start = "(" + since
+	}
+	for len(out) < limit {
+		msgs, err := h.xrange(ctx, "xrange", func() ([]redis.XMessage, error) {
+			return h.rdb.XRangeN(ctx, key, start, "+", int64(limit)).Result()
+		})
+		if err != nil {
+			return nil, err
+		}
+		out = appendHistory(out, msgs, limit, b2bOnly)
+		if len(msgs) < limit {
+			break
+		}
+		start = "(" + msgs[len(msgs)-1].ID
+	}
+	return out, nil
+}
+
+func (h *ChannelHistory) xrange(ctx context.Context, op string, fn func() ([]redis.XMessage, error)) ([]redis.XMessage, error) {
+	start := time.Now()
+	msgs, err := fn()
+	telemetry.ObserveRedis("channel_history", op, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return msgs, nil
+}
+
+// appendHistory decodes msgs into out, up to limit entries in total.
+// Undecodable entries are skipped.
+func appendHistory(out []ChannelHistoryEntry, msgs []redis.XMessage, limit int, b2bOnly bool) []ChannelHistoryEntry {
+	for _, msg := range msgs {
+		if len(out) == limit {
+			break
+		}
+		raw, _ := msg.Values["v"].(string)
+		var e ChannelHistoryEntry
+		if err := json.Unmarshal([]byte(raw), &e); err != nil {
+			continue
+		}
+		if b2bOnly && !e.b2bVisible() {
+			continue
+		}
+		e.ID = msg.ID
+		out = append(out, e)
+	}
+	return out
+}
+
+// ParseHistorySince validates a ?since= value: a stream entry ID
+// ("<millis>-<seq>") or a UTC millis timestamp.
+func ParseHistorySince(s string) error {
+	ms, seq, found := strings.Cut(s, "-")
+	if _, err := strconv.ParseUint(ms, 10, 64); err != nil {
+		return fmt.Errorf("invalid since %q: must be UTC millis or an event id", s)
+	}
+	if found {
+		if _, err := strconv.ParseUint(seq, 10, 64); err != nil {
+			return fmt.Errorf("invalid since %q: must be UTC millis or an event id", s)
+		}
+	}
+	return nil
+}
+
+func channelHistoryKey(chID int64) string {
+	return channelHistoryKeyPrefix + strconv.FormatInt(chID, 10)
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/edirooss/zmux-server/internal/domain/channel"
+	"github.com/edirooss/zmux-server/internal/infrastructure/processmgr"
+)
+
+// ChannelStateDisabled is the runtime state of a channel that is not
+// registered with any process manager (channel disabled).
+const ChannelStateDisabled = "disabled"
+
+// ChannelRuntime is the API model of a channel's supervisor state
+// (GET /api/channels/:id/runtime and "runtime" in the summary).
+//
+// Counters restart whenever the channel's remux command line changes.
+type ChannelRuntime struct {
+	State          string `json:"state"`                      // scheduled | starting | preflight | waiting_for_onflight | active | exiting | backoff | failed | stopped | disabled
+	CmdPID         int    `json:"cmd_pid,omitempty"`          // OS pid of the remux process
+	StartedAt      int64  `json:"started_at,omitempty"`       // UTC millis
+	LastExitAt     int64  `json:"last_exit_at,omitempty"`     // UTC millis
+	LastExitCode   *int   `json:"last_exit_code,omitempty"`   // absent when unknown or signaled
+	LastExitSignal string `json:"last_exit_signal,omitempty"` // e.g. "killed"
+	Restarts       int    `json:"restarts"`
+	Failures       int    `json:"failures"`                 // consecutive failed runs
+	NextLaunchAt   int64  `json:"next_launch_at,omitempty"` // UTC millis; scheduled/backoff/waiting only
+}
+
+// ChannelAction is a runtime action on the remux process of a channel.
+// Actions act on the process supervisor only; the persisted channel is not
+// touched (a server restart brings every enabled channel back up).
+type ChannelAction string
+
+const (
+	ChannelActionRestart ChannelAction = "restart" // graceful (SIGTERM → grace → SIGKILL), then relaunch
+	ChannelActionStop    ChannelAction = "stop"    // close and hold until start/restart
+	ChannelActionStart   ChannelAction = "start"   // release a stopped (or failed) channel
+)
+
+// unitSupervisor is the runtime API shared by ProcessManager and
+// ProcessManager2.
+type unitSupervisor interface {
+	Runtime(uid int64) (processmgr.UnitRuntime, bool)
+	ResetFailed(uid int64) bool
+	Restart(uid int64) bool
+	Stop(uid int64) bool
+	Start(uid int64) bool
+}
+
+// supervisorUnsafe returns the process manager of ch (nil when none).
+// Caller must hold s.mu.
+func (s *ChannelService) supervisorUnsafe(ch *channel.ZmuxChannel) unitSupervisor {
+	if ch.B2BClientID == nil {
+		return s.procmngr
+	}
+	if procmngr, ok := s.b2bclntsvc.supervisor(*ch.B2BClientID); ok {
+		return procmngr
+	}
+	return nil
+}
+
+// GetRuntime returns the supervisor state of the channel's remux process.
+func (s *ChannelService) GetRuntime(id int64) (*ChannelRuntime, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	val, ok := s.objs.GetOne(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	sup := s.supervisorUnsafe(val.(*channel.ZmuxChannel))
+	if sup == nil {
+		return &ChannelRuntime{State: ChannelStateDisabled}, nil
+	}
+	rt, registered := sup.Runtime(id)
+	if !registered {
+		return &ChannelRuntime{State: ChannelStateDisabled}, nil
+	}
+	return newChannelRuntime(rt), nil
+}
+
+// Control applies a runtime action to the channel's remux process.
+// Returns ErrConflict for channels not under supervision (disabled).
+func (s *ChannelService) Control(id int64, action ChannelAction) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	val, ok := s.objs.GetOne(id)
+	if !ok {
+		return ErrNotFound
+	}
+
+	sup := s.supervisorUnsafe(val.(*channel.ZmuxChannel))
+	if sup == nil {
+		return fmt.Errorf("%w: channel is not running (disabled)", ErrConflict)
+	}
+
+	var applied bool
+	switch action {
+	case ChannelActionRestart:
+		applied = sup.Restart(id)
+	case ChannelActionStop:
+		applied = sup.Stop(id)
+	case ChannelActionStart:
+		applied = sup.Start(id)
+	default:
+		return fmt.Errorf("unknown channel action %q", action)
+	}
+	if !applied {
+		return fmt.Errorf("%w: channel is not running (disabled)", ErrConflict)
+	}
+	return nil
+}
+
+// ResetFailed clears the crash-loop breaker of a channel parked in the
+// "failed" state and relaunches it. Returns ErrConflict when the channel is
+// not failed.
+func (s *ChannelService) ResetFailed(id int64) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	val, ok := s.objs.GetOne(id)
+	if !ok {
+		return ErrNotFound
+	}
+
+	sup := s.supervisorUnsafe(val.(*channel.ZmuxChannel))
+	if sup == nil || !sup.ResetFailed(id) {
+		return fmt.Errorf("%w: channel is not in failed state", ErrConflict)
+	}
+	return nil
+}
+
+func newChannelRuntime(rt processmgr.UnitRuntime) *ChannelRuntime {
+	return &ChannelRuntime{
+		State:          string(rt.State),
+		CmdPID:         rt.CmdPID,
+		StartedAt:      unixMilli(rt.StartedAt),
+		LastExitAt:     unixMilli(rt.LastExitAt),
+		LastExitCode:   rt.LastExitCode,
+		LastExitSignal: rt.LastExitSignal,
+		Restarts:       rt.Restarts,
+		Failures:       rt.Failures,
+		NextLaunchAt:   unixMilli(rt.NextLaunchAt),
+	}
+}
+
+// unixMilli is t.UnixMilli with the zero time mapped to 0 (omitted).
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// restartPolicy maps the channel's restart fields to the supervisor policy.
+func restartPolicy(ch *channel.ZmuxChannel) processmgr.RestartPolicy {
+	return processmgr.RestartPolicy{
+		Delay:      time.Duration(ch.RestartSec) * time.Second,
+		MaxDelay:   time.Duration(ch.RestartMaxDelaySec) * time.Second,
+		ResetAfter: time.Duration(ch.RestartResetSec) * time.Second,
+		Burst:      int(ch.StartLimitBurst),
+		Interval:   time.Duration(ch.StartLimitIntervalSec) * time.Second,
+	}
+}
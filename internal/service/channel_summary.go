@@ -10,17 +10,22 @@ import (
 	"golang.org/x/sync/singleflight"
 
 	"github.com/edirooss/zmux-server/internal/domain/channel"
+	"github.com/edirooss/zmux-server/internal/domain/channel/views"
 	"go.uber.org/zap"
 )
 
 // ChannelSummary is the API model for GET /api/channels/summary.
-// We embed ZmuxChannel so its fields are flattened (id, name, etc.) and
-// add monitoring fields conditionally.
+// We embed the admin view of the channel so its fields are flattened (id,
+// name, etc.; the input password is write-only) and add monitoring fields
+// conditionally.
 //   - status is present only if channel.Enabled == true AND status key exists.
 //   - ifmt/metrics are present only if status.liveness == "Live" and keys exist.
+//   - runtime is the supervisor state of the remux process (always present
+//     unless the channel was deleted mid-refresh).
 type ChannelSummary struct {
-	channel.ZmuxChannel
+	views.AdminZmuxChannel
 	RemuxSummary
+	Runtime *ChannelRuntime `json:"runtime,omitempty"`
 }
 
 type SummaryOptions struct {
@@ -171,7 +176,8 @@ func (s *SummaryService) refresh(ctx context.Context) ([]ChannelSummary, error)
 
 	out := make([]ChannelSummary, 0, len(chs))
 	for _, ch := range chs {
-		sum := ChannelSummary{ZmuxChannel: *ch}
+		sum := ChannelSummary{AdminZmuxChannel: *ch.AdminView()}
+		sum.Runtime, _ = s.chanService.GetRuntime(ch.ID) // nil if deleted meanwhile
 		if ch.Enabled {
 			if st, ok := summeriesByID[remuxID(ch)]; ok {
 				sum.Status = st.Status
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// logLevels ranks remux log levels (lowest → highest severity).
+var logLevels = map[string]int{
+	"trace":   0,
+	"debug":   1,
+	"info":    2,
+	"warn":    3,
+	"warning": 3,
+	"error":   4,
+	"fatal":   5,
+	"panic":   5,
+}
+
+// LogFilter selects channel log entries.
+//
+// Entries are the raw lines written by remux (JSON objects with a "level"
+// field); lines that are not JSON or carry an unknown level never pass a
+// level filter.
+type LogFilter struct {
+	minLevel int    // -1: any
+	contains string // "": any
+}
+
+// NewLogFilter builds a filter.
+//
+//   - level:    minimum severity (trace|debug|info|warn|error|fatal); empty = any
+//   - contains: case-sensitive substring of the raw line; empty = any
+func NewLogFilter(level, contains string) (*LogFilter, error) {
+	f := &LogFilter{minLevel: -1, contains: contains}
+	if level != "" {
+		rank, ok := logLevels[strings.ToLower(level)]
+		if !ok {
+			return nil, fmt.Errorf("invalid level %q (allowed: trace, debug, info, warn, error, fatal)", level)
+		}
+		f.minLevel = rank
+	}
+	return f, nil
+}
+
+// Match reports whether entry passes the filter.
+func (f *LogFilter) Match(entry string) bool {
+	if f.contains != "" && !strings.Contains(entry, f.contains) {
+		return false
+	}
+	if f.minLevel < 0 {
+		return true
+	}
+
+	var line struct {
+		Level string `json:"level"`
+	}
+	if err := json.Unmarshal([]byte(entry), &line); err != nil {
+		return false
+	}
+	rank, ok := logLevels[strings.ToLower(line.Level)]
+	return ok && rank >= f.minLevel
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/edirooss/zmux-server/internal/domain/remux"
+	"github.com/edirooss/zmux-server/internal/infrastructure/processmgr"
+	"github.com/edirooss/zmux-server/internal/infrastructure/telemetry"
+	"github.com/edirooss/zmux-server/pkg/promtext"
+	"go.uber.org/zap"
+)
+
+// metricsScrapeTimeout bounds the Redis work of a single scrape.
+const metricsScrapeTimeout = 2 * time.Second
+
+// MetricsCollector writes scrape-time metric families: channel states, B2B
+// quota and slot usage, and the per-channel counters remux publishes at
+// remux:<id>:metrics. Register Collect with telemetry.Registry.
+type MetricsCollector struct {
+	log        *zap.Logger
+	chnlsvc    *ChannelService
+	b2bclntsvc *B2BClientService
+	repo       *RemuxRepository
+}
+
+func NewMetricsCollector(log *zap.Logger, chnlsvc *ChannelService, b2bclntsvc *B2BClientService, repo *RemuxRepository) *MetricsCollector {
+	return &MetricsCollector{
+		log:        log.Named("metrics"),
+		chnlsvc:    chnlsvc,
+		b2bclntsvc: b2bclntsvc,
+		repo:       repo,
+	}
+}
+
+// Collect implements promtext.CollectorFunc.
+func (m *MetricsCollector) Collect(w *promtext.Writer) {
+	ctx, cancel := context.WithTimeout(context.Background(), metricsScrapeTimeout)
+	defer cancel()
+
+	m.collectChannels(ctx, w)
+	m.collectB2BClients(w)
+}
+
+// collectChannels writes channel counts by configuration, remux status and
+// supervisor state, followed by the remux metrics of online channels.
+func (m *MetricsCollector) collectChannels(ctx context.Context, w *promtext.Writer) {
+	chs, err := m.chnlsvc.GetList(ctx)
+	if err != nil {
+		m.log.Warn("list channels failed", zap.Error(err))
+		return
+	}
+
+	enabledIDs := make([]string, 0, len(chs))
+	for _, ch := range chs {
+		if ch.Enabled {
+			enabledIDs = append(enabledIDs, remuxID(ch))
+		}
+	}
+	summaries, err := m.repo.GetSummariesByID(ctx, enabledIDs)
+	if err != nil {
+		m.log.Warn("get remux summaries failed", zap.Error(err))
+		summaries = nil // counts below still report enabled/disabled
+	}
+
+	var enabled, disabled, online, offline int
+	procStates := make(map[string]int)
+	var remuxMetrics []channelRemuxMetrics
+	for _, ch := range chs {
+		if !ch.Enabled {
+			disabled++
+			continue
+		}
+		enabled++
+
+		sum, ok := summaries[remuxID(ch)]
+		if ok && sum.Status != nil && sum.Status.Online {
+			online++
+			if sum.Metrics != nil {
+				remuxMetrics = append(remuxMetrics, channelRemuxMetrics{strconv.FormatInt(ch.ID, 10), sum.Metrics})
+			}
+		} else {
+			offline++
+		}
+
+		if rt, err := m.chnlsvc.GetRuntime(ch.ID); err == nil {
+			procStates[rt.State]++
+		}
+	}
+
+	w.Family("zmux_channels", "Channels by state: enabled/disabled (configuration), online/offline (remux status of enabled channels).", "gauge")
+	w.Sample("zmux_channels", float64(enabled), "state", "enabled")
+	w.Sample("zmux_channels", float64(disabled), "state", "disabled")
+	w.Sample("zmux_channels", float64(online), "state", "online")
+	w.Sample("zmux_channels", float64(offline), "state", "offline")
+
+	w.Family("zmux_channel_processes", "Remux processes of enabled channels by supervisor state.", "gauge")
+	for _, state := range sortedKeys(procStates) {
+		w.Sample("zmux_channel_processes", float64(procStates[state]), "state", state)
+	}
+
+	writeRemuxMetrics(w, remuxMetrics)
+}
+
+// collectB2BClients writes per-client quota usage/limits and slot pool
+// occupancy.
+func (m *MetricsCollector) collectB2BClients(w *promtext.Writer) {
+	views, err := m.b2bclntsvc.GetList()
+	if err != nil {
+		m.log.Warn("list b2b clients failed", zap.Error(err))
+		return
+	}
+	slots := m.b2bclntsvc.slotUsage()
+
+	w.Family("zmux_b2b_enabled_channels", "Enabled channels of a B2B client (quota usage).", "gauge")
+	for _, v := range views {
+		w.Sample("zmux_b2b_enabled_channels", float64(v.Quotas.EnabledChannels.Usage), "b2b_client", strconv.FormatInt(v.ID, 10))
+	}
+	w.Family("zmux_b2b_enabled_channels_quota", "Enabled channels quota of a B2B client.", "gauge")
+	for _, v := range views {
+		w.Sample("zmux_b2b_enabled_channels_quota", float64(v.Quotas.EnabledChannels.Quota), "b2b_client", strconv.FormatInt(v.ID, 10))
+	}
+
+	w.Family("zmux_b2b_enabled_outputs", "Enabled outputs of a B2B client per output ref (quota usage).", "gauge")
+	for _, v := range views {
+		for _, o := range v.Quotas.EnabledOutputs {
+			w.Sample("zmux_b2b_enabled_outputs", float64(o.Usage), "b2b_client", strconv.FormatInt(v.ID, 10), "output_ref", o.Ref)
+		}
+	}
+	w.Family("zmux_b2b_enabled_outputs_quota", "Enabled outputs quota of a B2B client per output ref.", "gauge")
+	for _, v := range views {
+		for _, o := range v.Quotas.EnabledOutputs {
+			w.Sample("zmux_b2b_enabled_outputs_quota", float64(o.Quota), "b2b_client", strconv.FormatInt(v.ID, 10), "output_ref", o.Ref)
+		}
+	}
+
+	w.Family("zmux_b2b_slots_used", "Occupied process slots of a B2B client by pool (preflight: warming up; onflight: online channels quota usage).", "gauge")
+	for _, v := range views {
+		su, ok := slots[v.ID]
+		if !ok {
+			continue
+		}
+		id := strconv.FormatInt(v.ID, 10)
+		w.Sample("zmux_b2b_slots_used", float64(su.Preflight), "b2b_client", id, "pool", "preflight")
+		w.Sample("zmux_b2b_slots_used", float64(su.Onflight), "b2b_client", id, "pool", "onflight")
+	}
+	w.Family("zmux_b2b_slots_capacity", "Process slot capacity of a B2B client by pool (preflight: max_preflight; onflight: online channels quota).", "gauge")
+	for _, v := range views {
+		su, ok := slots[v.ID]
+		if !ok {
+			continue
+		}
+		id := strconv.FormatInt(v.ID, 10)
+		w.Sample("zmux_b2b_slots_capacity", float64(su.PreflightCap), "b2b_client", id, "pool", "preflight")
+		w.Sample("zmux_b2b_slots_capacity", float64(su.OnflightCap), "b2b_client", id, "pool", "onflight")
+	}
+}
+
+// channelRemuxMetrics is the remux metrics document of an online channel.
+type channelRemuxMetrics struct {
+	chID string
+	m    *remux.Metrics
+}
+
+// writeRemuxMetrics writes the typed remux counters (remux:<id>:metrics) of
+// online channels: input totals by channel_id, per-output values by
+// channel_id and output. The family set is fixed; other leaves of the
+// document are not exported.
+func writeRemuxMetrics(w *promtext.Writer, chs []channelRemuxMetrics) {
+	input := []struct {
+		name, help, typ string
+		value           func(*remux.Metrics) float64
+	}{
+		{"zmux_remux_input_bitrate_bps", "Input bitrate of an online channel (bits/s).", "gauge",
+			func(m *remux.Metrics) float64 { return m.Bitrate }},
+		{"zmux_remux_input_packets_total", "Input packets read by remux.", "counter",
+			func(m *remux.Metrics) float64 { return float64(m.Packets) }},
+		{"zmux_remux_input_bytes_total", "Input bytes read by remux.", "counter",
+			func(m *remux.Metrics) float64 { return float64(m.Bytes) }},
+		{"zmux_remux_continuity_errors_total", "MPEG-TS continuity counter errors on the input.", "counter",
+			func(m *remux.Metrics) float64 { return float64(m.ContinuityErrors) }},
+	}
+	for _, f := range input {
+		w.Family(f.name, f.help, f.typ)
+		for _, c := range chs {
+			w.Sample(f.name, f.value(c.m), "channel_id", c.chID)
+		}
+	}
+
+	output := []struct {
+		name, help, typ string
+		value           func(*remux.OutputMetrics) float64
+	}{
+		{"zmux_remux_output_bitrate_bps", "Output bitrate of an online channel (bits/s).", "gauge",
+			func(o *remux.OutputMetrics) float64 { return o.Bitrate }},
+		{"zmux_remux_output_packets_total", "Output packets written by remux.", "counter",
+			func(o *remux.OutputMetrics) float64 { return float64(o.Packets) }},
+		{"zmux_remux_output_bytes_total", "Output bytes written by remux.", "counter",
+			func(o *remux.OutputMetrics) float64 { return float64(o.Bytes) }},
+	}
+	for _, f := range output {
+		w.Family(f.name, f.help, f.typ)
+		for _, c := range chs {
+			for i := range c.m.Outputs {
+				o := &c.m.Outputs[i]
+				w.Sample(f.name, f.value(o), "channel_id", c.chID, "output", o.Ref)
+			}
+		}
+	}
+}
+
+// countProcessEvent updates the process lifecycle counters
+// (see telemetry.ProcessEvents).
+func countProcessEvent(b2bclntID int64, pev processmgr.Event) {
+	owner := ""
+	if b2bclntID != 0 {
+		owner = strconv.FormatInt(b2bclntID, 10)
+	}
+
+	telemetry.ProcessEvents.Inc(owner, string(pev.Kind))
+	if pev.Kind != processmgr.EventExited {
+		return
+	}
+
+	code := "unknown"
+	switch {
+	case pev.ExitCode != nil:
+		code = strconv.Itoa(*pev.ExitCode)
+	case pev.ExitSignal != "":
+		code = "signal:" + pev.ExitSignal
+	}
+	telemetry.ProcessExits.Inc(owner, code)
+	if pev.Restarting {
+		telemetry.ProcessRestarts.Inc(owner)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edirooss/zmux-server/internal/infrastructure/telemetry"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	metricsHistoryKeyPrefix = "zmux:metrics_history:" // zmux:metrics_history:<id>:<tier> → LIST of JSON(MetricsPoint), newest first
+)
+
+// metricsTier is one downsampled ring buffer of the metrics history.
+type metricsTier struct {
+	name string        // key suffix and API step label
+	res  time.Duration // bucket width
+	len  int64         // buckets kept
+}
+
+// span is the time covered by the tier.
+func (t metricsTier) span() time.Duration { return t.res * time.Duration(t.len) }
+
+// metricsTiers lists the history resolutions, finest first: 1s for 10
+// minutes, 1m for 24 hours, 1h for 30 days.
+var metricsTiers = []metricsTier{
+	{name: "1s", res: time.Second, len: 600},
+	{name: "1m", res: time.Minute, len: 1440},
+	{name: "1h", res: time.Hour, len: 720},
+}
+
+// metricsWrite holds the points pushed to one tier list in a sample pass,
+// oldest first.
+type metricsWrite struct {
+	tier   metricsTier
+	points []MetricsPoint
+}
+
+// MetricsPoint is one bucket of a channel's metrics history.
+//
+// Bitrate is aggregated over the bucket; Packets and ContinuityErrors are
+// the remux counters as of the end of the bucket (diff consecutive points
+// for rates).
+type MetricsPoint struct {
+	At               int64   `json:"at"` // bucket start, UTC millis
+	Samples          int64   `json:"samples"`
+	BitrateAvg       float64 `json:"bitrate_avg"` // bits/s
+	BitrateMin       float64 `json:"bitrate_min"`
+	BitrateMax       float64 `json:"bitrate_max"`
+	Packets          float64 `json:"packets"`
+	ContinuityErrors float64 `json:"continuity_errors"`
+}
+
+// merge folds p (a later bucket or sample) into m.
+func (m *MetricsPoint) merge(p MetricsPoint) {
+	if m.Samples == 0 {
+		at := m.At
+		*m = p
+		m.At = at
+		return
+	}
+	total := m.Samples + p.Samples
+	m.BitrateAvg = (m.BitrateAvg*float64(m.Samples) + p.BitrateAvg*float64(p.Samples)) / float64(total)
+	m.BitrateMin = min(m.BitrateMin, p.BitrateMin)
+	m.BitrateMax = max(m.BitrateMax, p.BitrateMax)
+	m.Packets = p.Packets
+	m.ContinuityErrors = p.ContinuityErrors
+	m.Samples = total
+}
+
+// MetricsHistory is the response of MetricsHistoryService.Get.
+type MetricsHistory struct {
+	ChannelID int64          `json:"channel_id"`
+	From      int64          `json:"from"` // UTC millis
+	To        int64          `json:"to"`   // UTC millis
+	Step      string         `json:"step"` // bucket width, e.g. "1m"
+	Points    []MetricsPoint `json:"points"`
+}
+
+// MetricsHistoryService samples the remux metrics of online channels every
+// second and keeps them in downsampled ring buffers in Redis (see
+// metricsTiers). Each tier is a capped list per channel that expires once
+// the channel stops producing samples for the tier's span.
+type MetricsHistoryService struct {
+	log *zap.Logger
+	rdb *redis.Client
+
+	mu   sync.Mutex
+	acc  map[int64][]MetricsPoint // channel ID → open bucket per coarse tier (metricsTiers[1:])
+	seen map[int64]int64          // channel ID → last sample second (dedups ticker jitter)
+}
+
+func NewMetricsHistoryService(log *zap.Logger, rdb *redis.Client) *MetricsHistoryService {
+	if log == nil {
+		log = zap.NewNop()
+	}
+
+	return &MetricsHistoryService{
+		log:  log.Named("metrics-history"),
+		rdb:  rdb,
+		acc:  make(map[int64][]MetricsPoint),
+		seen: make(map[int64]int64),
+	}
+}
+
+// Run samples every metricsTiers[0].res until ctx is done.
+func (s *MetricsHistoryService) Run(ctx context.Context, chnlsvc *ChannelService, repo *RemuxRepository) {
+	ticker := time.NewTicker(metricsTiers[0].res)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		samplectx, cancel := context.WithTimeout(ctx, metricsTiers[0].res)
+		if err := s.sample(samplectx, chnlsvc, repo); err != nil && ctx.Err() == nil {
+			s.log.Warn("metrics sample failed", zap.Error(err))
+		}
+		cancel()
+	}
+}
+
+func (s *MetricsHistoryService) sample(ctx context.Context, chnlsvc *ChannelService, repo *RemuxRepository) error {
+	chs, err := chnlsvc.GetList(ctx)
+	if err != nil {
+		return fmt.Errorf("list channels: %w", err)
+	}
+
+	ids := make([]string, 0, len(chs))
+	for _, ch := range chs {
+		if ch.Enabled {
+			ids = append(ids, remuxID(ch))
+		}
+	}
+	summaries, err := repo.GetSummariesByID(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("get remux summaries: %w", err)
+	}
+
+	now := time.Now()
+	sec := now.Unix()
+	writes := make(map[string]*metricsWrite) // by key
+	push := func(chID int64, tier metricsTier, p MetricsPoint) {
+		key := metricsHistoryKey(chID, tier)
+		if writes[key] == nil {
+			writes[key] = &metricsWrite{tier: tier}
+		}
+		writes[key].points = append(writes[key].points, p)
+	}
+
+	s.mu.Lock()
+	for _, ch := range chs {
+		sum := summaries[remuxID(ch)]
+		if sum == nil || sum.Status == nil || !sum.Status.Online || sum.Metrics == nil {
+			continue
+		}
+		if s.seen[ch.ID] == sec {
+			continue
+		}
+		s.seen[ch.ID] = sec
+		p := MetricsPoint{
+			At:               now.Truncate(metricsTiers[0].res).UnixMilli(),
+			Samples:          1,
+			BitrateAvg:       sum.Metrics.Bitrate,
+			BitrateMin:       sum.Metrics.Bitrate,
+			BitrateMax:       sum.Metrics.Bitrate,
+			Packets:          float64(sum.Metrics.Packets),
+			ContinuityErrors: float64(sum.Metrics.ContinuityErrors),
+		}
+
+		push(ch.ID, metricsTiers[0], p)
+
+		// Roll the sample up through the coarser tiers; a bucket is written
+		// once a sample of the next bucket arrives.
+		acc := s.acc[ch.ID]
+		if acc == nil {
+			acc = make([]MetricsPoint, len(metricsTiers)-1)
+			s.acc[ch.ID] = acc
+		}
+		for i, tier := range metricsTiers[1:] {
+			at := now.Truncate(tier.res).UnixMilli()
+			if acc[i].Samples > 0 && acc[i].At != at {
+				push(ch.ID, tier, acc[i])
+				acc[i] = MetricsPoint{}
+			}
+			acc[i].At = at
+			acc[i].merge(p)
+		}
+	}
+	for chID, last := range s.seen {
+		if sec-last > int64(metricsTiers[len(metricsTiers)-1].res/time.Second) {
+			delete(s.seen, chID) // the open buckets can no longer be completed
+			delete(s.acc, chID)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(writes) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	_, err = s.rdb.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for key, w := range writes {
+			vals := make([]any, 0, len(w.points))
+			for _, pt := range w.points {
+				raw, err := json.Marshal(pt)
+				if err != nil {
+					return fmt.Errorf("json marshal: %w", err)
+				}
+				vals = append(vals, raw)
+			}
+			p.LPush(ctx, key, vals...)
+			p.LTrim(ctx, key, 0, w.tier.len-1)
+			p.PExpire(ctx, key, w.tier.span())
+		}
+		return nil
+	})
+	telemetry.ObserveRedis("metrics_history", "lpush", start, err)
+	if err != nil {
+		return fmt.Errorf("write history: %w", err)
+	}
+	return nil
+}
+
+// Get returns the metrics history of a channel between from and to (UTC
+// millis, inclusive).
+//
+// The coarsest tier not exceeding step is read, falling back to coarser ones
+// when from is beyond its span. Buckets are aggregated to step; a step
+// finer than the tier's resolution is rounded up to it (zero: the finest
+// resolution available for from).
+func (s *MetricsHistoryService) Get(ctx context.Context, chID int64, from, to int64, step time.Duration) (*MetricsHistory, error) {
+	now := time.Now().UnixMilli()
+	i := 0
+	for i < len(metricsTiers)-1 && from < now-metricsTiers[i].span().Milliseconds() {
+		i++ // finest tier covering from
+	}
+	for i < len(metricsTiers)-1 && metricsTiers[i+1].res <= step {
+		i++ // coarsest tier not exceeding step
+	}
+	tier := metricsTiers[i]
+	if step < tier.res {
+		step = tier.res
+	}
+
+	key := metricsHistoryKey(chID, tier)
+	start := time.Now()
+	raws, err := s.rdb.LRange(ctx, key, 0, -1).Result()
+	telemetry.ObserveRedis("metrics_history", "lrange", start, err)
+	if err != nil {
+		return nil, fmt.Errorf("lrange: %w", err)
+	}
+
+	out := &MetricsHistory{ChannelID: chID, From: from, To: to, Step: formatMetricsStep(step), Points: []MetricsPoint{}}
+	stepMs := step.Milliseconds()
+	for i := len(raws) - 1; i >= 0; i-- { // oldest first
+		var p MetricsPoint
+		if err := json.Unmarshal([]byte(raws[i]), &p); err != nil {
+			s.log.Warn("corrupted metrics history entry", zap.String("key", key), zap.Error(err))
+			continue
+		}
+		if p.At < from || p.At > to {
+			continue
+		}
+
+		at := p.At - p.At%stepMs
+		if n := len(out.Points); n > 0 && out.Points[n-1].At == at {
+			out.Points[n-1].merge(p)
+			continue
+		}
+		bucket := MetricsPoint{At: at}
+		bucket.merge(p)
+		out.Points = append(out.Points, bucket)
+	}
+	return out, nil
+}
+
+// ParseMetricsStep parses a ?step= value: a Go duration ("30s", "5m") or
+// whole seconds.
+func ParseMetricsStep(s string) (time.Duration, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && n > 0 {
+		return time.Duration(n) * time.Second, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid step %q: must be a positive duration (e.g. 1m) or seconds", s)
+	}
+	return d, nil
+}
+
+func formatMetricsStep(d time.Duration) string {
+	s := d.String() // "1m0s" → "1m", "1h0m0s" → "1h"
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+	return s
+}
+
+func metricsHistoryKey(chID int64, tier metricsTier) string {
+	return metricsHistoryKeyPrefix + strconv.FormatInt(chID, 10) + ":" + tier.name
+}
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edirooss/zmux-server/internal/domain/channel"
+	outputdestination "github.com/edirooss/zmux-server/internal/domain/output-destination"
+	"github.com/edirooss/zmux-server/internal/infrastructure/datastore"
+	"github.com/edirooss/zmux-server/internal/infrastructure/objectstore"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	outputDestinationKeyPrefix = "zmux:output_destination:"       // zmux:output_destination:<id> → JSON(OutputDestinationModel)
+	outputDestinationSeededKey = "zmux:output_destination_seeded" // set once the legacy destinations were provisioned
+)
+
+// legacyOutputDestinations are the destinations that used to be hard-coded
+// (B2B-visible and toggleable). They are provisioned once, on first start, so
+// existing channels and B2B quotas keep working.
+var legacyOutputDestinations = []outputdestination.OutputDestinationResource{
+	{Ref: "onprem_mr01", Name: "onprem_mr01", PktSize: 1316, StreamMapping: []string{"video"}, B2BVisible: true, B2BToggleable: true},
+	{Ref: "onprem_mz01", Name: "onprem_mz01", PktSize: 1316, StreamMapping: []string{"video"}, B2BVisible: true, B2BToggleable: true},
+	{Ref: "pubcloud_sky320", Name: "pubcloud_sky320", PktSize: 1316, StreamMapping: []string{"video"}, B2BVisible: true, B2BToggleable: true},
+}
+
+// OutputDestinationService manages the catalog of output destinations
+// persisted in Redis. Channel outputs refer to destinations by ref; the
+// catalog decides what B2B clients see and toggle, the defaults of new
+// outputs and their allowed localaddrs.
+//
+// Domain objects are immutable once published; every write replaces the
+// object in the in-memory store and the ref index.
+type OutputDestinationService struct {
+	log *zap.Logger
+
+	mu    sync.RWMutex
+	ds    *datastore.DataStore                            // Redis-based persistent store
+	objs  *objectstore.ObjectStore                        // in-memory object store of OutputDestination domain objects
+	byRef map[string]*outputdestination.OutputDestination // in-memory ref-based index
+}
+
+func NewOutputDestinationService(ctx context.Context, log *zap.Logger, rdb *redis.Client) (*OutputDestinationService, error) {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	log = log.Named("output-destination-service")
+
+	ds, err := datastore.NewDataStore(ctx, log, rdb, outputDestinationKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("datastore: %w", err)
+	}
+
+	s := &OutputDestinationService{
+		log:   log,
+		ds:    ds,
+		objs:  objectstore.NewObjectStore(log),
+		byRef: make(map[string]*outputdestination.OutputDestination),
+	}
+
+	if err := s.reconcile(ctx); err != nil {
+		return nil, fmt.Errorf("reconcile: %w", err)
+	}
+
+	if err := s.seed(ctx, rdb); err != nil {
+		return nil, fmt.Errorf("seed: %w", err)
+	}
+
+	return s, nil
+}
+
+// Create adds a destination (r must be validated). Refs are unique.
+func (s *OutputDestinationService) Create(ctx context.Context, r *outputdestination.OutputDestinationResource) (*outputdestination.OutputDestinationView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.createUnsafe(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return d.View(), nil
+}
+
+// Update replaces a destination (r must be validated). The ref is immutable:
+// channel outputs and B2B client quotas refer to it.
+func (s *OutputDestinationService) Update(ctx context.Context, id int64, r *outputdestination.OutputDestinationResource) (*outputdestination.OutputDestinationView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, ok := s.objs.GetOne(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	cur := val.(*outputdestination.OutputDestination)
+
+	if r.Ref != cur.Ref {
+		return nil, fmt.Errorf("%w: ref cannot be changed (%q)", ErrConflict, cur.Ref)
+	}
+
+	model := outputdestination.NewOutputDestinationModel(r, cur.CreatedAt, time.Now().UnixMilli())
+	raw, err := json.Marshal(model)
+	if err != nil {
+		return nil, fmt.Errorf("json marshal: %w", err)
+	}
+	if err := s.ds.Update(ctx, id, raw); err != nil {
+		return nil, fmt.Errorf("update: %w", err)
+	}
+
+	d := outputdestination.NewOutputDestination(model, id)
+	s.objs.Upsert(id, d)
+	s.byRef[d.Ref] = d
+	return d.View(), nil
+}
+
+// Delete removes a destination. Existing channel outputs keep the ref; they
+// are no longer visible to B2B clients.
+func (s *OutputDestinationService) Delete(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, ok := s.objs.GetOne(id)
+	if !ok {
+		return ErrNotFound
+	}
+	d := val.(*outputdestination.OutputDestination)
+
+	if err := s.ds.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+	s.objs.Delete(id)
+	delete(s.byRef, d.Ref)
+	return nil
+}
+
+func (s *OutputDestinationService) GetOne(id int64) (*outputdestination.OutputDestinationView, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	val, ok := s.objs.GetOne(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return val.(*outputdestination.OutputDestination).View(), nil
+}
+
+// GetModel returns the persisted representation of a destination (e.g. for
+// audit diffs).
+func (s *OutputDestinationService) GetModel(id int64) (*outputdestination.OutputDestinationModel, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	val, ok := s.objs.GetOne(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return val.(*outputdestination.OutputDestination).Model(), nil
+}
+
+func (s *OutputDestinationService) GetList() ([]*outputdestination.OutputDestinationView, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, vals := s.objs.GetList()
+
+	views := make([]*outputdestination.OutputDestinationView, 0, len(vals))
+	for _, val := range vals {
+		views = append(views, val.(*outputdestination.OutputDestination).View())
+	}
+	return views, nil
+}
+
+// Lookup returns the destination with the given ref (nil if none). The
+// returned object is shared and must not be modified.
+func (s *OutputDestinationService) Lookup(ref string) *outputdestination.OutputDestination {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.byRef[ref]
+}
+
+// Exists reports whether a destination with the given ref exists.
+func (s *OutputDestinationService) Exists(ref string) bool {
+	return s.Lookup(ref) != nil
+}
+
+// B2BVisible reports whether B2B clients see outputs to ref.
+func (s *OutputDestinationService) B2BVisible(ref string) bool {
+	d := s.Lookup(ref)
+	return d != nil && d.B2BVisible
+}
+
+// B2BToggleable reports whether B2B clients may enable/disable outputs to ref.
+func (s *OutputDestinationService) B2BToggleable(ref string) bool {
+	d := s.Lookup(ref)
+	return d != nil && d.B2BToggleable
+}
+
+// CheckOutputs validates a channel write against the catalog: outputs to a
+// destination must use one of its localaddrs. Only new outputs and changed
+// localaddrs are checked (prev is nil on create), so channels predating a
+// destination change keep working.
+func (s *OutputDestinationService) CheckOutputs(prev, next *channel.ZmuxChannel) error {
+	var prevOutputs map[string]channel.OutputEntry
+	if prev != nil {
+		prevOutputs = prev.OutputsByRef()
+	}
+	for _, o := range next.Outputs {
+		if e, ok := prevOutputs[o.Ref]; ok && equalStringPtr(e.Output.Localaddr, o.Localaddr) {
+			continue
+		}
+		if d := s.Lookup(o.Ref); d != nil {
+			if err := d.CheckOutput(o); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ----- internals ------------------------------------------------------------
+
+// createUnsafe persists a new destination and indexes it. Must be locked.
+func (s *OutputDestinationService) createUnsafe(ctx context.Context, r *outputdestination.OutputDestinationResource) (*outputdestination.OutputDestination, error) {
+	if _, taken := s.byRef[r.Ref]; taken {
+		return nil, fmt.Errorf("%w: ref %q already exists", ErrConflict, r.Ref)
+	}
+
+	now := time.Now().UnixMilli()
+	model := outputdestination.NewOutputDestinationModel(r, now, now)
+	raw, err := json.Marshal(model)
+	if err != nil {
+		return nil, fmt.Errorf("json marshal: %w", err)
+	}
+
+	id, err := s.ds.Create(ctx, raw)
+	if err != nil {
+		return nil, fmt.Errorf("create: %w", err)
+	}
+
+	d := outputdestination.NewOutputDestination(model, id)
+	s.objs.Upsert(id, d)
+	s.byRef[d.Ref] = d
+	return d, nil
+}
+
+// reconcile loads persisted records into domain objects and rebuilds in-memory indices.
+//
+//   - DB (Persistence Layer) → Domain (in-memory)
+func (s *OutputDestinationService) reconcile(ctx context.Context) error {
+	ids, vals, err := s.ds.GetList(ctx)
+	if err != nil {
+		return fmt.Errorf("get list: %w", err)
+	}
+
+	for i, id := range ids {
+		var model outputdestination.OutputDestinationModel
+		if err := json.Unmarshal(vals[i], &model); err != nil {
+			// Data corruption detected - should never happen in normal operation.
+			s.log.Error("corrupted data detected",
+				zap.Int64("id", id),
+				zap.String("data_preview", safePreview(vals[i])),
+				zap.Error(err))
+			return fmt.Errorf("json unmarshal: %w", err)
+		}
+
+		d := outputdestination.NewOutputDestination(&model, id)
+		s.objs.Upsert(id, d)
+		s.byRef[d.Ref] = d
+	}
+
+	return nil
+}
+
+// seed provisions the legacy destinations once. Later deletions stick: the
+// marker key keeps them from coming back on restart.
+func (s *OutputDestinationService) seed(ctx context.Context, rdb *redis.Client) error {
+	n, err := rdb.Exists(ctx, outputDestinationSeededKey).Result()
+	if err != nil {
+		return fmt.Errorf("redis exists: %w", err)
+	}
+	if n > 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range legacyOutputDestinations {
+		r := legacyOutputDestinations[i]
+		if _, ok := s.byRef[r.Ref]; ok {
+			continue
+		}
+		d, err := s.createUnsafe(ctx, &r)
+		if err != nil {
+			return err
+		}
+		s.log.Info("output destination provisioned", zap.Int64("id", d.ID), zap.String("ref", d.Ref))
+	}
+
+	if err := rdb.Set(ctx, outputDestinationSeededKey, "1", 0).Err(); err != nil {
+		return fmt.Errorf("redis set: %w", err)
+	}
+	return nil
+}
+
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
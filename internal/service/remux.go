@@ -4,7 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
+	"time"
 
+	"github.com/edirooss/zmux-server/internal/domain/remux"
+	"github.com/edirooss/zmux-server/internal/infrastructure/telemetry"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
@@ -13,6 +17,8 @@ import (
 type RemuxRepository struct {
 	log *zap.Logger
 	rdb *redis.Client
+
+	warned sync.Map // "<kind>:<schema_version>" of newer documents already logged
 }
 
 // This repository operates over monitoring data that is continuously refreshed
@@ -42,11 +48,11 @@ type RemuxStatus struct {
 	} `json:"event"`
 }
 
-// RemuxSummary bundles RemuxStatus with optional ifmt/metrics JSON blobs for "Online" remuxers.
+// RemuxSummary bundles RemuxStatus with optional ifmt/metrics for "Online" remuxers.
 type RemuxSummary struct {
-	Status  *RemuxStatus     `json:"status,omitempty"`
-	Ifmt    *json.RawMessage `json:"ifmt,omitempty"`    // optional; if Status.Online == false, always missing
-	Metrics *json.RawMessage `json:"metrics,omitempty"` // optional; if Status.Online == false, always missing
+	Status  *RemuxStatus   `json:"status,omitempty"`
+	Ifmt    *remux.Ifmt    `json:"ifmt,omitempty"`    // optional; if Status.Online == false, always missing
+	Metrics *remux.Metrics `json:"metrics,omitempty"` // optional; if Status.Online == false, always missing
 }
 
 // GetStatusesByID fetches remux:<id>:status for the provided remux IDs via a single
@@ -65,7 +71,9 @@ func (r *RemuxRepository) GetStatusesByID(ctx context.Context, ids []string) (ma
 		keys[i] = remuxStatusKey(id)
 	}
 
+	start := time.Now()
 	vals, err := r.rdb.MGet(ctx, keys...).Result()
+	telemetry.ObserveRedis("remux", "mget_status", start, err)
 	if err != nil {
 		return nil, fmt.Errorf("mget: %w", err)
 	}
@@ -143,7 +151,9 @@ func (r *RemuxRepository) GetSummariesByID(ctx context.Context, ids []string) (m
 			keys = append(keys, remuxIfmtKey(id), remuxMetricsKey(id))
 		}
 
+		start := time.Now()
 		vals, err := r.rdb.MGet(ctx, keys...).Result()
+		telemetry.ObserveRedis("remux", "mget_ifmt_metrics", start, err)
 		if err != nil {
 			return nil, fmt.Errorf("mget ifmt/metrics: %w", err)
 		}
@@ -158,14 +168,61 @@ func (r *RemuxRepository) GetSummariesByID(ctx context.Context, ids []string) (m
 			if err != nil {
 				return nil, fmt.Errorf("metrics for id %s: %w", id, err)
 			}
-			summariesByID[id].Ifmt = ifmt
-			summariesByID[id].Metrics = metrics
+			summariesByID[id].Ifmt = r.decodeIfmt(id, ifmt)
+			summariesByID[id].Metrics = r.decodeMetrics(id, metrics)
 		}
 	}
 
 	return summariesByID, nil
 }
 
+// decodeIfmt decodes an optional ifmt value. Decoding is tolerant (see
+// remux.Ifmt); a value that is not a JSON object (torn write, foreign
+// writer) is treated as missing.
+func (r *RemuxRepository) decodeIfmt(id string, raw *json.RawMessage) *remux.Ifmt {
+	if raw == nil {
+		return nil
+	}
+	var ifmt remux.Ifmt
+	if err := json.Unmarshal(*raw, &ifmt); err != nil {
+		r.log.Debug("undecodable ifmt", zap.String("id", id), zap.Error(err))
+		return nil
+	}
+	if ifmt.UnknownSchema {
+		r.warnUnknownSchema("ifmt", id, ifmt.SchemaVersion, remux.IfmtSchemaVersion)
+	}
+	return &ifmt
+}
+
+// decodeMetrics is decodeIfmt for metrics.
+func (r *RemuxRepository) decodeMetrics(id string, raw *json.RawMessage) *remux.Metrics {
+	if raw == nil {
+		return nil
+	}
+	var metrics remux.Metrics
+	if err := json.Unmarshal(*raw, &metrics); err != nil {
+		r.log.Debug("undecodable metrics", zap.String("id", id), zap.Error(err))
+		return nil
+	}
+	if metrics.UnknownSchema {
+		r.warnUnknownSchema("metrics", id, metrics.SchemaVersion, remux.MetricsSchemaVersion)
+	}
+	return &metrics
+}
+
+// warnUnknownSchema logs a document newer than this server knows, once per
+// kind and version (documents are re-read on every poll).
+func (r *RemuxRepository) warnUnknownSchema(kind, id string, version, known int) {
+	if _, dup := r.warned.LoadOrStore(fmt.Sprintf("%s:%d", kind, version), struct{}{}); dup {
+		return
+	}
+	r.log.Warn("remux document schema is newer than supported; decoding best-effort",
+		zap.String("kind", kind),
+		zap.String("id", id),
+		zap.Int("schema_version", version),
+		zap.Int("supported", known))
+}
+
 func optionalVal(v interface{}) (*json.RawMessage, error) {
 	if v == nil {
 		return nil, nil // value missing (optional)
@@ -0,0 +1,391 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	b2bclient "github.com/edirooss/zmux-server/internal/domain/b2b-client"
+	"github.com/edirooss/zmux-server/internal/domain/channel"
+	"github.com/edirooss/zmux-server/internal/infrastructure/telemetry"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	uptimeKeyPrefix = "zmux:uptime:" // zmux:uptime:<id> → STREAM of {state: online|offline|disabled}; entry ID time = transition time
+
+	// uptimeRetention is how far back transitions are kept (longest window
+	// plus slack for the state at its start).
+	uptimeRetention = 35 * 24 * time.Hour
+
+	// uptimeWriteTimeout bounds a single XADD/DEL.
+	uptimeWriteTimeout = 2 * time.Second
+)
+
+// Channel availability states recorded by UptimeService.
+const (
+	uptimeOnline   = "online"
+	uptimeOffline  = "offline"
+	uptimeDisabled = "disabled" // not monitored; excluded from availability
+)
+
+// UptimeWindows maps report window names to their length (rolling, ending now).
+var UptimeWindows = map[string]time.Duration{
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+}
+
+// UptimeStats is the availability of a channel (or a rollup of channels)
+// over a report window.
+//
+// Only time during which a channel was enabled counts (monitored). An
+// outage is an offline period entered from online (or already ongoing at
+// the window start); MTTR is the mean length of the outages that
+// recovered within the window.
+type UptimeStats struct {
+	MonitoredSec float64  `json:"monitored_sec"`
+	UptimeSec    float64  `json:"uptime_sec"`
+	DowntimeSec  float64  `json:"downtime_sec"`
+	UptimePct    *float64 `json:"uptime_pct"` // null when not monitored
+	Outages      int      `json:"outages"`
+	MTTRSec      *float64 `json:"mttr_sec"` // null when no outage recovered
+
+	recovered   int     // outages recovered in the window
+	recoverySec float64 // total length of the recovered outages
+}
+
+func (s *UptimeStats) add(o UptimeStats) {
+	s.MonitoredSec += o.MonitoredSec
+	s.UptimeSec += o.UptimeSec
+	s.DowntimeSec += o.DowntimeSec
+	s.Outages += o.Outages
+	s.recovered += o.recovered
+	s.recoverySec += o.recoverySec
+}
+
+// finish derives the percentage and MTTR.
+func (s *UptimeStats) finish() {
+	s.UptimePct, s.MTTRSec = nil, nil
+	if s.MonitoredSec > 0 {
+		pct := s.UptimeSec / s.MonitoredSec * 100
+		s.UptimePct = &pct
+	}
+	if s.recovered > 0 {
+		mttr := s.recoverySec / float64(s.recovered)
+		s.MTTRSec = &mttr
+	}
+}
+
+// ChannelUptime is the per-channel row of an UptimeReport.
+type ChannelUptime struct {
+	ChannelID   int64   `json:"channel_id"`
+	Name        *string `json:"name"`
+	B2BClientID *int64  `json:"b2b_client_id"`
+	UptimeStats
+}
+
+// B2BClientUptime is the per-client rollup of an UptimeReport, across the
+// client's channels.
+type B2BClientUptime struct {
+	B2BClientID int64   `json:"b2b_client_id"`
+	Name        string  `json:"name"`
+	ChannelIDs  []int64 `json:"channel_ids"`
+	UptimeStats
+}
+
+// UptimeReport is the response of GET /api/reports/uptime.
+type UptimeReport struct {
+	Window     string            `json:"window"`
+	From       int64             `json:"from"` // UTC millis
+	To         int64             `json:"to"`   // UTC millis
+	Channels   []ChannelUptime   `json:"channels"`
+	B2BClients []B2BClientUptime `json:"b2b_clients"`
+}
+
+// uptimeRecord is a pending availability state change.
+type uptimeRecord struct {
+	chID    int64
+	state   string // "" deletes the channel's history
+	initial bool   // startup snapshot; written even if unchanged
+	enabled bool   // config change of an enabled channel; only a transition when coming from disabled
+}
+
+// UptimeService records channel availability transitions (online, offline,
+// disabled) into a per-channel Redis stream and computes availability
+// reports from them.
+//
+// Transitions come from the channel event hub (remux online/offline flips,
+// enable/disable through config changes). At startup the current state of
+// every channel is recorded as well, so the time the server was down is
+// attributed to the state last seen before it stopped.
+type UptimeService struct {
+	log   *zap.Logger
+	rdb   *redis.Client
+	queue chan uptimeRecord
+	last  map[int64]string // channel ID → last written state; owned by Run
+}
+
+// NewUptimeService constructs an UptimeService recording transitions of
+// hub. Call Run to start writing.
+func NewUptimeService(log *zap.Logger, rdb *redis.Client, hub *ChannelEventHub) *UptimeService {
+	if log == nil {
+		log = zap.NewNop()
+	}
+
+	s := &UptimeService{
+		log:   log.Named("uptime"),
+		rdb:   rdb,
+		queue: make(chan uptimeRecord, 1024),
+		last:  make(map[int64]string),
+	}
+
+	hub.Observe(func(ev ChannelEvent) {
+		var rec uptimeRecord
+		switch {
+		case ev.Status != nil && ev.Status.onlineChanged && ev.Status.Online:
+			rec = uptimeRecord{chID: ev.ChannelID, state: uptimeOnline}
+		case ev.Status != nil && ev.Status.onlineChanged:
+			rec = uptimeRecord{chID: ev.ChannelID, state: uptimeOffline}
+		case ev.Config != nil && !ev.ownerOnly && ev.Config.Action == "deleted":
+			rec = uptimeRecord{chID: ev.ChannelID}
+		case ev.Config != nil && ev.Config.ch != nil && !ev.Config.ch.Enabled:
+			rec = uptimeRecord{chID: ev.ChannelID, state: uptimeDisabled}
+		case ev.Config != nil && ev.Config.ch != nil:
+			rec = uptimeRecord{chID: ev.ChannelID, state: uptimeOffline, enabled: true} // offline until remux reports online
+		default:
+			return
+		}
+		select {
+		case s.queue <- rec:
+		default:
+			s.log.Warn("uptime queue full; transition dropped", zap.Int64("channel_id", ev.ChannelID))
+		}
+	})
+	return s
+}
+
+// Run records the current state of every channel, then writes queued
+// transitions until ctx is done.
+func (s *UptimeService) Run(ctx context.Context, chnlsvc *ChannelService, repo *RemuxRepository) {
+	if err := s.snapshot(ctx, chnlsvc, repo); err != nil {
+		s.log.Warn("initial uptime snapshot failed", zap.Error(err))
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case rec := <-s.queue:
+			s.write(ctx, rec)
+		}
+	}
+}
+
+func (s *UptimeService) snapshot(ctx context.Context, chnlsvc *ChannelService, repo *RemuxRepository) error {
+	chs, err := chnlsvc.GetList(ctx)
+	if err != nil {
+		return fmt.Errorf("list channels: %w", err)
+	}
+	ids := make([]string, 0, len(chs))
+	for _, ch := range chs {
+		if ch.Enabled {
+			ids = append(ids, remuxID(ch))
+		}
+	}
+	statuses, err := repo.GetStatusesByID(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("get statuses: %w", err)
+	}
+
+	for _, ch := range chs {
+		state := uptimeDisabled
+		if ch.Enabled {
+			state = uptimeOffline
+			if st, ok := statuses[remuxID(ch)]; ok && st.Online {
+				state = uptimeOnline
+			}
+		}
+		s.write(ctx, uptimeRecord{chID: ch.ID, state: state, initial: true})
+	}
+	return nil
+}
+
+func (s *UptimeService) write(ctx context.Context, rec uptimeRecord) {
+	ctx, cancel := context.WithTimeout(ctx, uptimeWriteTimeout)
+	defer cancel()
+
+	key := uptimeKey(rec.chID)
+	if rec.state == "" {
+		delete(s.last, rec.chID)
+		start := time.Now()
+		err := s.rdb.Del(ctx, key).Err()
+		telemetry.ObserveRedis("uptime", "del", start, err)
+		if err != nil {
+			s.log.Warn("uptime history cleanup failed", zap.Int64("channel_id", rec.chID), zap.Error(err))
+		}
+		return
+	}
+
+	last, known := s.last[rec.chID]
+	if !rec.initial && last == rec.state {
+		return
+	}
+	if rec.enabled && known && last != uptimeDisabled {
+		return // edit of an already enabled channel
+	}
+	s.last[rec.chID] = rec.state
+
+	start := time.Now()
+	err := s.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		MinID:  strconv.FormatInt(time.Now().Add(-uptimeRetention).UnixMilli(), 10),
+		Approx: true,
+		Values: []string{"state", rec.state},
+	}).Err()
+	telemetry.ObserveRedis("uptime", "xadd", start, err)
+	if err != nil {
+		s.log.Warn("uptime write failed", zap.Int64("channel_id", rec.chID), zap.Error(err))
+	}
+}
+
+// Report computes availability over window (see UptimeWindows) for chs and
+// the B2B clients in clients (rolled up over their channels within chs).
+func (s *UptimeService) Report(ctx context.Context, window string, chs []*channel.ZmuxChannel, clients []*b2bclient.B2BClientView) (*UptimeReport, error) {
+	length, ok := UptimeWindows[window]
+	if !ok {
+		return nil, fmt.Errorf("invalid window %q: must be one of day, week, month", window)
+	}
+	to := time.Now().UnixMilli()
+	from := to - length.Milliseconds()
+
+	type reads struct {
+		before *redis.XMessageSliceCmd
+		within *redis.XMessageSliceCmd
+	}
+	cmds := make([]reads, len(chs))
+	start := time.Now()
+	_, err := s.rdb.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for i, ch := range chs {
+			key := uptimeKey(ch.ID)
+			cmds[i].before = p.XRevRangeN(ctx, key, "("+strconv.FormatInt(from, 10), "-", 1)
+			cmds[i].within = p.XRange(ctx, key, strconv.FormatInt(from, 10), "+")
+		}
+		return nil
+	})
+	telemetry.ObserveRedis("uptime", "xrange", start, err)
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("read transitions: %w", err)
+	}
+
+	report := &UptimeReport{
+		Window:     window,
+		From:       from,
+		To:         to,
+		Channels:   make([]ChannelUptime, 0, len(chs)),
+		B2BClients: make([]B2BClientUptime, 0, len(clients)),
+	}
+	byChannel := make(map[int64]UptimeStats, len(chs))
+	for i, ch := range chs {
+		before, _ := cmds[i].before.Result()
+		within, _ := cmds[i].within.Result()
+		stats := computeUptime(append(before, within...), from, to)
+		byChannel[ch.ID] = stats
+
+		stats.finish()
+		report.Channels = append(report.Channels, ChannelUptime{
+			ChannelID:   ch.ID,
+			Name:        ch.Name,
+			B2BClientID: ch.B2BClientID,
+			UptimeStats: stats,
+		})
+	}
+
+	for _, clnt := range clients {
+		row := B2BClientUptime{B2BClientID: clnt.ID, Name: clnt.Name, ChannelIDs: []int64{}}
+		for _, chID := range clnt.ChannelIDs {
+			if stats, ok := byChannel[chID]; ok {
+				row.ChannelIDs = append(row.ChannelIDs, chID)
+				row.add(stats)
+			}
+		}
+		slices.Sort(row.ChannelIDs)
+		row.finish()
+		report.B2BClients = append(report.B2BClients, row)
+	}
+	return report, nil
+}
+
+// computeUptime folds the transitions of one channel (oldest first; at most
+// one before from, giving the state at the window start) into stats over
+// [from, to).
+func computeUptime(msgs []redis.XMessage, from, to int64) UptimeStats {
+	var stats UptimeStats
+	state := "" // unknown before the first transition
+	at := from  // start of the current segment
+	var (
+		outageAt int64 // start of the ongoing outage
+		inOutage bool
+	)
+
+	account := func(until int64) {
+		d := float64(until-at) / 1000
+		switch state {
+		case uptimeOnline:
+			stats.UptimeSec += d
+			stats.MonitoredSec += d
+		case uptimeOffline:
+			stats.DowntimeSec += d
+			stats.MonitoredSec += d
+		}
+	}
+
+	for _, msg := range msgs {
+		next, _ := msg.Values["state"].(string)
+		t := streamIDMillis(msg.ID)
+		if t < from {
+			state = next // state at the window start
+			if state == uptimeOffline {
+				outageAt, inOutage = from, true
+				stats.Outages++
+			}
+			continue
+		}
+		if next == state {
+			continue
+		}
+		if t > to {
+			break
+		}
+
+		account(t)
+		switch {
+		case next == uptimeOffline && state == uptimeOnline:
+			outageAt, inOutage = t, true
+			stats.Outages++
+		case next == uptimeOnline && inOutage:
+			stats.recovered++
+			stats.recoverySec += float64(t-outageAt) / 1000
+			inOutage = false
+		case next == uptimeDisabled:
+			inOutage = false // disabled while down; not a recovery
+		}
+		state, at = next, t
+	}
+	account(to)
+	return stats
+}
+
+func streamIDMillis(id string) int64 {
+	ms, _, _ := strings.Cut(id, "-")
+	n, _ := strconv.ParseInt(ms, 10, 64)
+	return n
+}
+
+func uptimeKey(chID int64) string {
+	return uptimeKeyPrefix + strconv.FormatInt(chID, 10)
+}
@@ -0,0 +1,447 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	b2bclient "github.com/edirooss/zmux-server/internal/domain/b2b-client"
+	"github.com/edirooss/zmux-server/internal/domain/channel"
+	"github.com/edirooss/zmux-server/internal/infrastructure/telemetry"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	usageKeyPrefix = "zmux:usage:" // zmux:usage:<YYYY-MM-DD> → HASH of <b2b client id>:<counter> → seconds (UTC day)
+)
+
+// Usage counters (hash field suffixes), in seconds.
+const (
+	usageEnabled = "enabled" // channel enabled
+	usageOnline  = "online"  // channel enabled and online
+	usageOutput  = "output:" // + output ref: output enabled on an enabled channel
+)
+
+const (
+	usageDateLayout  = "2006-01-02" // day key
+	usageMonthLayout = "2006-01"    // ?month=
+)
+
+// UsageOptions tunes usage metering.
+type UsageOptions struct {
+	// FlushInterval is how often accrued usage is written to Redis.
+	FlushInterval time.Duration
+	// Retention is how long daily counters are kept.
+	Retention time.Duration
+}
+
+func (o *UsageOptions) setDefaults() {
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = time.Minute
+	}
+	if o.Retention <= 0 {
+		o.Retention = 400 * 24 * time.Hour
+	}
+}
+
+// UsageTotals is the consumption of a B2B client over a period, in hours.
+type UsageTotals struct {
+	ChannelHoursEnabled float64            `json:"channel_hours_enabled"`
+	ChannelHoursOnline  float64            `json:"channel_hours_online"`
+	OutputHours         map[string]float64 `json:"output_hours"` // by output ref
+}
+
+func (t *UsageTotals) add(counter string, secs float64) {
+	switch {
+	case counter == usageEnabled:
+		t.ChannelHoursEnabled += secs / time.Hour.Seconds()
+	case counter == usageOnline:
+		t.ChannelHoursOnline += secs / time.Hour.Seconds()
+	case strings.HasPrefix(counter, usageOutput):
+		t.OutputHours[strings.TrimPrefix(counter, usageOutput)] += secs / time.Hour.Seconds()
+	}
+}
+
+func (t *UsageTotals) round() {
+	r := func(h float64) float64 { return math.Round(h*1000) / 1000 } // 3.6s resolution
+	t.ChannelHoursEnabled = r(t.ChannelHoursEnabled)
+	t.ChannelHoursOnline = r(t.ChannelHoursOnline)
+	for ref, h := range t.OutputHours {
+		t.OutputHours[ref] = r(h)
+	}
+}
+
+// UsageDay is the consumption of a B2B client over one UTC day.
+type UsageDay struct {
+	Date string `json:"date"` // YYYY-MM-DD
+	UsageTotals
+}
+
+// B2BClientUsage is the consumption of a B2B client over a calendar month
+// (UTC).
+type B2BClientUsage struct {
+	B2BClientID int64      `json:"b2b_client_id"`
+	Name        string     `json:"name"` // empty for deleted clients
+	Month       string     `json:"month"`
+	Days        []UsageDay `json:"days,omitempty"` // days with usage, oldest first
+	UsageTotals
+}
+
+// UsageExport is the billing export of all B2B clients for a month.
+type UsageExport struct {
+	Month      string           `json:"month"`
+	B2BClients []B2BClientUsage `json:"b2b_clients"` // by ID
+}
+
+// usageChannel is the metered state of a channel since the last accrual.
+type usageChannel struct {
+	owner   int64 // 0: not metered
+	enabled bool
+	online  bool
+	outputs []string // enabled output refs
+	since   int64    // UTC millis
+}
+
+// UsageService meters B2B client consumption: channel-hours enabled,
+// channel-hours online and output-hours per output ref.
+//
+// State changes come from the channel event hub (enable/disable and output
+// changes through config events, online/offline through status events) and
+// are accrued in memory to the owning client, split at UTC midnight. Every
+// FlushInterval the accrued time is added to per-day Redis counters, which
+// survive restarts; time the server is down is not metered.
+type UsageService struct {
+	log  *zap.Logger
+	rdb  *redis.Client
+	opts UsageOptions
+
+	mu      sync.Mutex
+	chans   map[int64]*usageChannel       // by channel ID
+	pending map[string]map[string]float64 // date → field → seconds not yet flushed
+}
+
+// NewUsageService constructs a UsageService metering the channels of hub.
+// Call Run to start writing.
+func NewUsageService(log *zap.Logger, rdb *redis.Client, hub *ChannelEventHub, opts UsageOptions) *UsageService {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	opts.setDefaults()
+
+	s := &UsageService{
+		log:     log.Named("usage"),
+		rdb:     rdb,
+		opts:    opts,
+		chans:   make(map[int64]*usageChannel),
+		pending: make(map[string]map[string]float64),
+	}
+
+	hub.Observe(func(ev ChannelEvent) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		uc := s.chans[ev.ChannelID]
+		switch {
+		case ev.Status != nil && ev.Status.onlineChanged:
+			if uc != nil {
+				s.accrueUnsafe(uc, ev.At)
+				uc.online = uc.enabled && ev.Status.Online
+			}
+		case ev.Config != nil && !ev.ownerOnly && ev.Config.Action == "deleted":
+			if uc != nil {
+				s.accrueUnsafe(uc, ev.At)
+				delete(s.chans, ev.ChannelID)
+			}
+		case ev.Config != nil && ev.Config.ch != nil:
+			if uc == nil {
+				uc = &usageChannel{since: ev.At}
+				s.chans[ev.ChannelID] = uc
+			}
+			s.accrueUnsafe(uc, ev.At)
+			uc.set(ev.Config.ch, uc.online)
+		}
+	})
+	return s
+}
+
+// set applies the metered fields of ch.
+func (uc *usageChannel) set(ch *channel.ZmuxChannel, online bool) {
+	uc.owner = 0
+	if ch.B2BClientID != nil {
+		uc.owner = *ch.B2BClientID
+	}
+	uc.enabled = ch.Enabled
+	uc.online = ch.Enabled && online
+	uc.outputs = uc.outputs[:0]
+	for _, o := range ch.Outputs {
+		if o.Enabled {
+			uc.outputs = append(uc.outputs, o.Ref)
+		}
+	}
+}
+
+// accrueUnsafe adds the time since uc.since up to until (UTC millis) to the
+// pending counters of uc.owner. Caller must hold s.mu.
+func (s *UsageService) accrueUnsafe(uc *usageChannel, until int64) {
+	from := uc.since
+	if until <= from {
+		return
+	}
+	uc.since = until
+	if uc.owner == 0 || !uc.enabled {
+		return
+	}
+
+	prefix := strconv.FormatInt(uc.owner, 10) + ":"
+	for from < until {
+		day := time.UnixMilli(from).UTC()
+		end := min(until, day.Truncate(24*time.Hour).Add(24*time.Hour).UnixMilli())
+		secs := float64(end-from) / 1000
+		date := day.Format(usageDateLayout)
+
+		counters := s.pending[date]
+		if counters == nil {
+			counters = make(map[string]float64)
+			s.pending[date] = counters
+		}
+		counters[prefix+usageEnabled] += secs
+		if uc.online {
+			counters[prefix+usageOnline] += secs
+		}
+		for _, ref := range uc.outputs {
+			counters[prefix+usageOutput+ref] += secs
+		}
+		from = end
+	}
+}
+
+// Run records the current state of every channel, then flushes accrued usage
+// every FlushInterval until ctx is done (with a final flush).
+func (s *UsageService) Run(ctx context.Context, chnlsvc *ChannelService, repo *RemuxRepository) {
+	if err := s.snapshot(ctx, chnlsvc, repo); err != nil {
+		s.log.Warn("initial usage snapshot failed", zap.Error(err))
+	}
+
+	ticker := time.NewTicker(s.opts.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := s.flush(flushctx); err != nil {
+				s.log.Warn("final usage flush failed", zap.Error(err))
+			}
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.flush(ctx); err != nil && ctx.Err() == nil {
+			s.log.Warn("usage flush failed; retrying next interval", zap.Error(err))
+		}
+	}
+}
+
+func (s *UsageService) snapshot(ctx context.Context, chnlsvc *ChannelService, repo *RemuxRepository) error {
+	chs, err := chnlsvc.GetList(ctx)
+	if err != nil {
+		return fmt.Errorf("list channels: %w", err)
+	}
+	ids := make([]string, 0, len(chs))
+	for _, ch := range chs {
+		if ch.Enabled {
+			ids = append(ids, remuxID(ch))
+		}
+	}
+	statuses, err := repo.GetStatusesByID(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("get statuses: %w", err)
+	}
+
+	now := time.Now().UnixMilli()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ch := range chs {
+		if _, ok := s.chans[ch.ID]; ok {
+			continue // already tracked from an event
+		}
+		st, ok := statuses[remuxID(ch)]
+		uc := &usageChannel{since: now}
+		uc.set(ch, ok && st.Online)
+		s.chans[ch.ID] = uc
+	}
+	return nil
+}
+
+// flush accrues every channel up to now and adds the pending counters to
+// Redis. On failure the counters stay pending.
+func (s *UsageService) flush(ctx context.Context) error {
+	now := time.Now().UnixMilli()
+	s.mu.Lock()
+	for _, uc := range s.chans {
+		s.accrueUnsafe(uc, now)
+	}
+	pending := s.pending
+	s.pending = make(map[string]map[string]float64)
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	_, err := s.rdb.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for date, counters := range pending {
+			key := usageKeyPrefix + date
+			for field, secs := range counters {
+				p.HIncrByFloat(ctx, key, field, secs)
+			}
+			p.PExpire(ctx, key, s.opts.Retention)
+		}
+		return nil
+	})
+	telemetry.ObserveRedis("usage", "hincrbyfloat", start, err)
+	if err != nil {
+		s.mu.Lock()
+		for date, counters := range pending {
+			if s.pending[date] == nil {
+				s.pending[date] = make(map[string]float64)
+			}
+			for field, secs := range counters {
+				s.pending[date][field] += secs
+			}
+		}
+		s.mu.Unlock()
+		return fmt.Errorf("write counters: %w", err)
+	}
+	return nil
+}
+
+// GetB2BClientUsage returns the usage of a B2B client in month (any time
+// within it), with a per-day breakdown.
+func (s *UsageService) GetB2BClientUsage(ctx context.Context, clnt *b2bclient.B2BClientView, month time.Time) (*B2BClientUsage, error) {
+	usages, err := s.read(ctx, month, true)
+	if err != nil {
+		return nil, err
+	}
+	u, ok := usages[clnt.ID]
+	if !ok {
+		u = newB2BClientUsage(clnt.ID, month)
+		u.Days = []UsageDay{}
+	}
+	u.Name = clnt.Name
+	return u, nil
+}
+
+// Export returns the usage totals of every B2B client with usage in month,
+// plus every client in clients (zero usage); clients also provides names.
+func (s *UsageService) Export(ctx context.Context, month time.Time, clients []*b2bclient.B2BClientView) (*UsageExport, error) {
+	usages, err := s.read(ctx, month, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, clnt := range clients {
+		if usages[clnt.ID] == nil {
+			usages[clnt.ID] = newB2BClientUsage(clnt.ID, month)
+		}
+		usages[clnt.ID].Name = clnt.Name
+	}
+
+	out := &UsageExport{Month: month.Format(usageMonthLayout), B2BClients: make([]B2BClientUsage, 0, len(usages))}
+	for _, u := range usages {
+		out.B2BClients = append(out.B2BClients, *u)
+	}
+	sort.Slice(out.B2BClients, func(i, j int) bool { return out.B2BClients[i].B2BClientID < out.B2BClients[j].B2BClientID })
+	return out, nil
+}
+
+// read loads the day counters of month, by client.
+func (s *UsageService) read(ctx context.Context, month time.Time, days bool) (map[int64]*B2BClientUsage, error) {
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	dates := make([]string, 0, 31)
+	for d := first; d.Month() == first.Month(); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d.Format(usageDateLayout))
+	}
+
+	cmds := make([]*redis.MapStringStringCmd, len(dates))
+	start := time.Now()
+	_, err := s.rdb.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for i, date := range dates {
+			cmds[i] = p.HGetAll(ctx, usageKeyPrefix+date)
+		}
+		return nil
+	})
+	telemetry.ObserveRedis("usage", "hgetall", start, err)
+	if err != nil {
+		return nil, fmt.Errorf("read counters: %w", err)
+	}
+
+	usages := make(map[int64]*B2BClientUsage)
+	for i, date := range dates {
+		byClient := make(map[int64]*UsageDay)
+		for field, raw := range cmds[i].Val() {
+			id, counter, ok := strings.Cut(field, ":")
+			clntID, err := strconv.ParseInt(id, 10, 64)
+			if !ok || err != nil {
+				continue
+			}
+			secs, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				s.log.Warn("corrupted usage counter", zap.String("date", date), zap.String("field", field), zap.Error(err))
+				continue
+			}
+
+			u := usages[clntID]
+			if u == nil {
+				u = newB2BClientUsage(clntID, month)
+				usages[clntID] = u
+			}
+			u.add(counter, secs)
+
+			if days {
+				day := byClient[clntID]
+				if day == nil {
+					day = &UsageDay{Date: date, UsageTotals: UsageTotals{OutputHours: map[string]float64{}}}
+					byClient[clntID] = day
+				}
+				day.add(counter, secs)
+			}
+		}
+		for clntID, day := range byClient {
+			day.round()
+			usages[clntID].Days = append(usages[clntID].Days, *day)
+		}
+	}
+	for _, u := range usages {
+		u.round()
+	}
+	return usages, nil
+}
+
+func newB2BClientUsage(clntID int64, month time.Time) *B2BClientUsage {
+	return &B2BClientUsage{
+		B2BClientID: clntID,
+		Month:       month.Format(usageMonthLayout),
+		UsageTotals: UsageTotals{OutputHours: map[string]float64{}},
+	}
+}
+
+// ParseUsageMonth parses a ?month= value (YYYY-MM, UTC); empty is the
+// current month.
+func ParseUsageMonth(s string) (time.Time, error) {
+	if s == "" {
+		return time.Now().UTC(), nil
+	}
+	t, err := time.Parse(usageMonthLayout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid month %q: must be YYYY-MM", s)
+	}
+	return t, nil
+}
@@ -3,6 +3,8 @@ package service
 import (
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-contrib/sessions/redis"
@@ -19,20 +21,31 @@ type UserSessionService struct {
 // It is used internally by UserSessionService methods like SetUserSession and GetUserID.
 const sessionKeyUserID = "uid"
 
+// UserSessionOptions configures the Redis-backed session store and cookie policy.
+type UserSessionOptions struct {
+	RedisAddr     string        // host:port
+	RedisUsername string        //
+	RedisPassword string        //
+	RedisDB       int           //
+	PoolSize      int           // session store connection pool size
+	Secret        string        // cookie signing key
+	MaxAge        time.Duration // cookie lifetime
+	Secure        bool          // mark cookies Secure (false only for local dev over HTTP)
+}
+
 // NewUserSessionService creates a new UserSessionService.
-// The `isDev` flag controls whether cookies are marked Secure.
-func NewUserSessionService(isDev bool) (*UserSessionService, error) {
+func NewUserSessionService(opts UserSessionOptions) (*UserSessionService, error) {
 	// Create Redis session store
-	store, err := redis.NewStoreWithDB(10, "tcp", "127.0.0.1:6379", "", "", "0",
-		[]byte("nZCowo9+aofuYO/54sK2mca+aj8M9XA2zVLrP1kh6uk=") /* TODO(security): rotate key */)
+	store, err := redis.NewStoreWithDB(opts.PoolSize, "tcp", opts.RedisAddr, opts.RedisUsername, opts.RedisPassword,
+		strconv.Itoa(opts.RedisDB), []byte(opts.Secret))
 	if err != nil {
 		return nil, fmt.Errorf("new store: %w", err)
 	}
 
 	cookieOptions := sessions.Options{
 		Path:     "/api",
-		MaxAge:   4 * 3600,
-		Secure:   !isDev,
+		MaxAge:   int(opts.MaxAge / time.Second),
+		Secure:   opts.Secure,
 		HttpOnly: true,
 		SameSite: http.SameSiteStrictMode,
 	}
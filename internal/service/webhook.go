@@ -0,0 +1,621 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	b2bclient "github.com/edirooss/zmux-server/internal/domain/b2b-client"
+	"github.com/edirooss/zmux-server/internal/domain/channel"
+	"github.com/edirooss/zmux-server/internal/domain/webhook"
+	"github.com/edirooss/zmux-server/internal/infrastructure/datastore"
+	"github.com/edirooss/zmux-server/internal/infrastructure/objectstore"
+	"github.com/edirooss/zmux-server/internal/infrastructure/processmgr"
+	"github.com/edirooss/zmux-server/internal/infrastructure/telemetry"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	webhookKeyPrefix         = "zmux:webhook:"            // zmux:webhook:<id> → JSON(WebhookModel)
+	webhookDeliveryKeyPrefix = "zmux:webhook_deliveries:" // zmux:webhook_deliveries:<id> → LIST of JSON(DeliveryView), newest first
+
+	// webhookDeliveryLogLen is the number of delivery attempts kept per webhook.
+	webhookDeliveryLogLen = 200
+)
+
+// WebhookOptions tunes delivery.
+type WebhookOptions struct {
+	// Workers is the number of concurrent deliveries.
+	Workers int
+	// Timeout bounds a single delivery attempt (connect to response headers).
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts per event, first one included.
+	MaxAttempts int
+	// QueueSize bounds pending deliveries; events beyond it are dropped.
+	QueueSize int
+}
+
+func (o *WebhookOptions) setDefaults() {
+	if o.Workers <= 0 {
+		o.Workers = 4
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 10 * time.Second
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 6
+	}
+	if o.QueueSize <= 0 {
+		o.QueueSize = 1024
+	}
+}
+
+// webhookRetryBase and webhookRetryMax bound the retry backoff: 2s, 8s, 32s,
+// ~2m, ~8.5m, then 10m.
+const (
+	webhookRetryBase = 2 * time.Second
+	webhookRetryMax  = 10 * time.Minute
+)
+
+// WebhookService manages webhook subscriptions persisted in Redis and
+// delivers notifications to them.
+//
+// Delivery:
+//   - Each (event, webhook) pair gets a delivery ID, kept across retries.
+//   - The JSON body (webhook.Event) is POSTed with X-Zmux-Event,
+//     X-Zmux-Delivery, X-Zmux-Timestamp (unix seconds) and
+//     X-Zmux-Signature: sha256=hex(HMAC-SHA256(secret, timestamp + "." + body)).
+//   - Any 2xx response is a success. Otherwise the attempt is retried with
+//     exponential backoff, up to MaxAttempts.
+//   - Every attempt is appended to the webhook's delivery log.
+//
+// Notifications never block the caller: the queue is bounded and overflow
+// is dropped (logged).
+type WebhookService struct {
+	log  *zap.Logger
+	rdb  *redis.Client
+	opts WebhookOptions
+
+	mu   sync.RWMutex
+	ds   *datastore.DataStore     // Redis-based persistent store
+	objs *objectstore.ObjectStore // in-memory object store of Webhook domain objects
+
+	client  *http.Client
+	queue   chan webhookJob
+	closed  bool // guarded by mu; queue is closed
+	workers sync.WaitGroup
+}
+
+// webhookJob is a pending delivery attempt.
+type webhookJob struct {
+	hookID  int64
+	ev      webhook.Event
+	attempt int // 1-based
+}
+
+func NewWebhookService(ctx context.Context, log *zap.Logger, rdb *redis.Client, opts WebhookOptions) (*WebhookService, error) {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	log = log.Named("webhook-service")
+	opts.setDefaults()
+
+	ds, err := datastore.NewDataStore(ctx, log, rdb, webhookKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("datastore: %w", err)
+	}
+
+	s := &WebhookService{
+		log:    log,
+		rdb:    rdb,
+		opts:   opts,
+		ds:     ds,
+		objs:   objectstore.NewObjectStore(log),
+		client: &http.Client{Timeout: opts.Timeout},
+		queue:  make(chan webhookJob, opts.QueueSize),
+	}
+
+	if err := s.reconcile(ctx); err != nil {
+		return nil, fmt.Errorf("reconcile: %w", err)
+	}
+
+	for range opts.Workers {
+		s.workers.Add(1)
+		go s.worker()
+	}
+	return s, nil
+}
+
+// Create adds a webhook. A secret is generated when r.Secret is nil; the
+// returned view is the only one carrying the secret.
+func (s *WebhookService) Create(ctx context.Context, r *webhook.WebhookResource) (*webhook.WebhookView, error) {
+	secret := ""
+	if r.Secret != nil {
+		secret = *r.Secret
+	} else {
+		var err error
+		if secret, err = genWebhookSecret(); err != nil {
+			return nil, fmt.Errorf("generate secret: %w", err)
+		}
+	}
+
+	now := time.Now().UnixMilli()
+	model := &webhook.WebhookModel{
+		URL:         r.URL,
+		Secret:      secret,
+		Events:      r.Events,
+		Enabled:     r.Enabled,
+		Description: r.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	raw, err := json.Marshal(model)
+	if err != nil {
+		return nil, fmt.Errorf("json marshal: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, err := s.ds.Create(ctx, raw)
+	if err != nil {
+		return nil, fmt.Errorf("create: %w", err)
+	}
+	w := webhook.NewWebhook(model, id)
+	s.objs.Upsert(id, w)
+
+	view := w.View()
+	view.Secret = w.Secret
+	return view, nil
+}
+
+// Update replaces the webhook; r.Secret nil keeps the current secret.
+func (s *WebhookService) Update(ctx context.Context, id int64, r *webhook.WebhookResource) (*webhook.WebhookView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, ok := s.objs.GetOne(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	cur := val.(*webhook.Webhook)
+
+	next := *cur
+	next.URL = r.URL
+	next.Events = r.Events
+	next.Enabled = r.Enabled
+	next.Description = r.Description
+	next.UpdatedAt = time.Now().UnixMilli()
+	if r.Secret != nil {
+		next.Secret = *r.Secret
+	}
+
+	raw, err := json.Marshal(next.Model())
+	if err != nil {
+		return nil, fmt.Errorf("json marshal: %w", err)
+	}
+	if err := s.ds.Update(ctx, id, raw); err != nil {
+		return nil, fmt.Errorf("update: %w", err)
+	}
+	s.objs.Upsert(id, &next)
+
+	return next.View(), nil
+}
+
+// Delete removes a webhook and its delivery log. Pending retries are dropped.
+func (s *WebhookService) Delete(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.objs.GetOne(id); !ok {
+		return ErrNotFound
+	}
+	if err := s.ds.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+	s.objs.Delete(id)
+
+	start := time.Now()
+	err := s.rdb.Del(ctx, webhookDeliveryKey(id)).Err()
+	telemetry.ObserveRedis("webhook", "del", start, err)
+	if err != nil {
+		s.log.Warn("delivery log cleanup failed", zap.Int64("id", id), zap.Error(err))
+	}
+	return nil
+}
+
+func (s *WebhookService) GetOne(id int64) (*webhook.WebhookView, error) {
+	val, ok := s.objs.GetOne(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return val.(*webhook.Webhook).View(), nil
+}
+
+func (s *WebhookService) GetList() ([]*webhook.WebhookView, error) {
+	_, vals := s.objs.GetList()
+
+	views := make([]*webhook.WebhookView, 0, len(vals))
+	for _, val := range vals {
+		views = append(views, val.(*webhook.Webhook).View())
+	}
+	return views, nil
+}
+
+// GetDeliveries returns up to limit most recent delivery attempts of the
+// webhook, newest first.
+func (s *WebhookService) GetDeliveries(ctx context.Context, id int64, limit int) ([]webhook.DeliveryView, error) {
+	if _, ok := s.objs.GetOne(id); !ok {
+		return nil, ErrNotFound
+	}
+
+	start := time.Now()
+	raws, err := s.rdb.LRange(ctx, webhookDeliveryKey(id), 0, int64(limit)-1).Result()
+	telemetry.ObserveRedis("webhook", "lrange", start, err)
+	if err != nil {
+		return nil, fmt.Errorf("lrange: %w", err)
+	}
+
+	out := make([]webhook.DeliveryView, 0, len(raws))
+	for _, raw := range raws {
+		var d webhook.DeliveryView
+		if err := json.Unmarshal([]byte(raw), &d); err != nil {
+			s.log.Warn("corrupted delivery log entry", zap.Int64("id", id), zap.Error(err))
+			continue
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+// Ping queues a test delivery ("ping" event) to the webhook, regardless of
+// its event subscriptions and enabled flag. Returns the delivery ID.
+func (s *WebhookService) Ping(id int64) (string, error) {
+	if _, ok := s.objs.GetOne(id); !ok {
+		return "", ErrNotFound
+	}
+
+	ev := webhook.Event{
+		ID:   uuid.NewString(),
+		Type: webhook.EventPing,
+		At:   time.Now().UnixMilli(),
+		Data: webhookPingData{WebhookID: id},
+	}
+	if !s.enqueue(webhookJob{hookID: id, ev: ev, attempt: 1}) {
+		return "", errors.New("delivery queue is full")
+	}
+	return ev.ID, nil
+}
+
+// Notify queues event t for every enabled webhook subscribed to it.
+// Never blocks.
+func (s *WebhookService) Notify(t webhook.EventType, data any) {
+	if s == nil {
+		return
+	}
+	at := time.Now().UnixMilli()
+
+	_, vals := s.objs.GetList()
+	for _, val := range vals {
+		w := val.(*webhook.Webhook)
+		if !w.Subscribed(t) {
+			continue
+		}
+		s.enqueue(webhookJob{
+			hookID:  w.ID,
+			ev:      webhook.Event{ID: uuid.NewString(), Type: t, At: at, Data: data},
+			attempt: 1,
+		})
+	}
+}
+
+// Watch subscribes to channel events: remux online/offline transitions and
+// crash-loop breaker trips are turned into notifications. chnlsvc resolves
+// channel names.
+func (s *WebhookService) Watch(events *ChannelEventHub, chnlsvc *ChannelService) {
+	events.Observe(func(ev ChannelEvent) {
+		var t webhook.EventType
+		switch {
+		case ev.Status != nil && ev.Status.onlineChanged && ev.Status.Online:
+			t = webhook.EventChannelOnline
+		case ev.Status != nil && ev.Status.onlineChanged:
+			t = webhook.EventChannelOffline
+		case ev.Process != nil && ev.Process.State == string(processmgr.EventFailed):
+			t = webhook.EventChannelCrashLoop
+		default:
+			return
+		}
+
+		data := webhookChannelData{ChannelID: ev.ChannelID}
+		if ch, err := chnlsvc.GetOne(ev.ChannelID); err == nil {
+			data.ChannelName = ch.Name
+			data.B2BClientID = ch.B2BClientID
+		}
+		if ev.Status != nil {
+			data.Message = ev.Status.Message
+		}
+		s.Notify(t, data)
+	})
+}
+
+// Shutdown stops accepting notifications, drops pending retries and waits
+// for in-flight deliveries (bounded by ctx).
+func (s *WebhookService) Shutdown(ctx context.Context) {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.log.Warn("webhook deliveries still in flight at shutdown")
+	}
+}
+
+// ----- notification payloads ------------------------------------------------
+
+// webhookPingData is the payload of ping.
+type webhookPingData struct {
+	WebhookID int64 `json:"webhook_id"`
+}
+
+// webhookChannelData is the payload of channel.* events.
+type webhookChannelData struct {
+	ChannelID   int64   `json:"channel_id"`
+	ChannelName *string `json:"channel_name,omitempty"`
+	B2BClientID *int64  `json:"b2b_client_id,omitempty"`
+	Message     string  `json:"msg,omitempty"` // remux status message (online/offline)
+}
+
+// webhookQuotaData is the payload of channel.quota_exceeded.
+type webhookQuotaData struct {
+	ChannelID     int64   `json:"channel_id,omitempty"` // absent on create
+	ChannelName   *string `json:"channel_name,omitempty"`
+	B2BClientID   int64   `json:"b2b_client_id"`
+	B2BClientName string  `json:"b2b_client_name"`
+	Resource      string  `json:"resource"`
+	Usage         int64   `json:"usage"` // usage the rejected write would have resulted in
+	Quota         int64   `json:"quota"`
+}
+
+// webhookB2BClientData is the payload of b2b_client.* events (never
+// carries the bearer token).
+type webhookB2BClientData struct {
+	ID     int64                 `json:"id"`
+	Name   string                `json:"name"`
+	Quotas *b2bclient.QuotasView `json:"quotas,omitempty"` // absent on delete
+}
+
+// notifyQuotaExceeded reports a write of ch rejected by a quota; err is the
+// enforcement result (no-op unless it is a *QuotaExceededError).
+func (s *WebhookService) notifyQuotaExceeded(ch *channel.ZmuxChannel, err error) {
+	var qe *QuotaExceededError
+	if !errors.As(err, &qe) {
+		return
+	}
+	s.Notify(webhook.EventChannelQuotaExceeded, webhookQuotaData{
+		ChannelID:     ch.ID,
+		ChannelName:   ch.Name,
+		B2BClientID:   qe.ClientID,
+		B2BClientName: qe.ClientName,
+		Resource:      qe.Resource,
+		Usage:         qe.Usage,
+		Quota:         qe.Quota,
+	})
+}
+
+// notifyB2BClient reports a B2B client change; view is nil for deletions.
+func (s *WebhookService) notifyB2BClient(t webhook.EventType, id int64, name string, view *b2bclient.B2BClientView) {
+	data := webhookB2BClientData{ID: id, Name: name}
+	if view != nil {
+		quotas := view.Quotas
+		data.Quotas = &quotas
+	}
+	s.Notify(t, data)
+}
+
+// ----- delivery -------------------------------------------------------------
+
+// enqueue queues job without blocking; false when full or shut down.
+func (s *WebhookService) enqueue(job webhookJob) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return false
+	}
+	select {
+	case s.queue <- job:
+		return true
+	default:
+		s.log.Warn("webhook delivery queue full; dropping",
+			zap.Int64("webhook_id", job.hookID),
+			zap.String("event", string(job.ev.Type)),
+			zap.String("delivery_id", job.ev.ID))
+		return false
+	}
+}
+
+func (s *WebhookService) worker() {
+	defer s.workers.Done()
+	for job := range s.queue {
+		s.deliver(job)
+	}
+}
+
+// deliver performs one attempt, records it and schedules a retry on failure.
+func (s *WebhookService) deliver(job webhookJob) {
+	val, ok := s.objs.GetOne(job.hookID)
+	if !ok {
+		return // deleted meanwhile
+	}
+	w := val.(*webhook.Webhook)
+	if job.ev.Type != webhook.EventPing && !w.Subscribed(job.ev.Type) {
+		return // disabled or unsubscribed meanwhile
+	}
+
+	rec := webhook.DeliveryView{
+		DeliveryID: job.ev.ID,
+		Event:      string(job.ev.Type),
+		Attempt:    job.attempt,
+		At:         time.Now().UnixMilli(),
+	}
+
+	start := time.Now()
+	status, err := s.post(w, job.ev)
+	rec.DurationMs = time.Since(start).Milliseconds()
+	rec.StatusCode = status
+	switch {
+	case err != nil:
+		rec.Error = err.Error()
+	case status < 200 || status > 299:
+		rec.Error = "unexpected status " + strconv.Itoa(status)
+	default:
+		rec.Success = true
+	}
+
+	if !rec.Success && job.attempt < s.opts.MaxAttempts {
+		delay := webhookRetryDelay(job.attempt)
+		rec.NextRetryAt = time.Now().Add(delay).UnixMilli()
+		next := job
+		next.attempt++
+		time.AfterFunc(delay, func() { s.enqueue(next) })
+	}
+	if !rec.Success {
+		s.log.Debug("webhook delivery failed",
+			zap.Int64("webhook_id", w.ID),
+			zap.String("delivery_id", rec.DeliveryID),
+			zap.Int("attempt", rec.Attempt),
+			zap.String("error", rec.Error))
+	}
+
+	s.record(w.ID, rec)
+}
+
+// post sends ev to w; returns the response status (0 when none).
+func (s *WebhookService) post(w *webhook.Webhook, ev webhook.Event) (int, error) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return 0, fmt.Errorf("json marshal: %w", err)
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "zmux-server-webhook")
+	req.Header.Set("X-Zmux-Event", string(ev.Type))
+	req.Header.Set("X-Zmux-Delivery", ev.ID)
+	req.Header.Set("X-Zmux-Timestamp", ts)
+	req.Header.Set("X-Zmux-Signature", "sha256="+signWebhook(w.Secret, ts, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) // allow connection reuse
+
+	return resp.StatusCode, nil
+}
+
+// record appends rec to the webhook's delivery log (bounded).
+func (s *WebhookService) record(id int64, rec webhook.DeliveryView) {
+	raw, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	key := webhookDeliveryKey(id)
+	start := time.Now()
+	_, err = s.rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.LPush(ctx, key, raw)
+		p.LTrim(ctx, key, 0, webhookDeliveryLogLen-1)
+		return nil
+	})
+	telemetry.ObserveRedis("webhook", "lpush", start, err)
+	if err != nil {
+		s.log.Warn("delivery log write failed", zap.Int64("id", id), zap.Error(err))
+	}
+}
+
+// reconcile loads persisted records into domain objects.
+//
+//   - DB (Persistence Layer) → Domain (in-memory)
+func (s *WebhookService) reconcile(ctx context.Context) error {
+	ids, vals, err := s.ds.GetList(ctx)
+	if err != nil {
+		return fmt.Errorf("get list: %w", err)
+	}
+
+	for i, id := range ids {
+		var model webhook.WebhookModel
+		if err := json.Unmarshal(vals[i], &model); err != nil {
+			// Data corruption detected - should never happen in normal operation.
+			s.log.Error("corrupted data detected",
+				zap.Int64("id", id),
+				zap.Error(err))
+			return fmt.Errorf("json unmarshal: %w", err)
+		}
+		s.objs.Upsert(id, webhook.NewWebhook(&model, id))
+	}
+
+	return nil
+}
+
+// signWebhook returns hex(HMAC-SHA256(secret, ts + "." + body)).
+func signWebhook(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookRetryDelay returns the wait after the given failed attempt.
+func webhookRetryDelay(attempt int) time.Duration {
+	d := webhookRetryBase
+	for i := 1; i < attempt && d < webhookRetryMax; i++ {
+		d *= 4
+	}
+	return min(d, webhookRetryMax)
+}
+
+func genWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func webhookDeliveryKey(id int64) string {
+	return webhookDeliveryKeyPrefix + strconv.FormatInt(id, 10)
+}
@@ -3,6 +3,7 @@ package avurl
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/edirooss/zmux-server/pkg/hostutil"
@@ -92,6 +93,20 @@ func EmbeddUserinfo(url, username, password *string) *string {
 	return ptr(avurlJoin(schema, userinfo, host, port, path, _md))
 }
 
+// userinfoRe matches the scheme and userinfo of URLs embedded in free text.
+// Greedy up to the last '@' of the authority: passwords may contain a raw '@'.
+var userinfoRe = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://)[^/?#\s]+@`)
+
+// ScrubUserinfo replaces the userinfo of every URL in s with "***", e.g.
+// "rtsp://u:p@host/x" → "rtsp://***@host/x". Safe on arbitrary text
+// (log lines, command strings).
+func ScrubUserinfo(s string) string {
+	if !strings.Contains(s, "@") {
+		return s
+	}
+	return userinfoRe.ReplaceAllString(s, "${1}***@")
+}
+
 // escapUsername escapes only '/', '?', '#' and ':' using percent-encoding.
 func escapUsername(input string) string {
 	replacer := strings.NewReplacer(
@@ -0,0 +1,208 @@
+// Package envelope encrypts short secrets (e.g. stream credentials) for
+// at-rest storage.
+//
+// Design:
+//
+//   - Envelope encryption: every value is encrypted with a fresh random data
+//     key (DEK, AES-256-GCM), and the DEK is encrypted ("wrapped") with a key
+//     encryption key (KEK, AES-256-GCM) from the Keyring.
+//   - Sealed values are self-describing strings:
+//     enc:v1:<kid>:<wrapped DEK>:<ciphertext>
+//     (binary parts are nonce-prefixed, unpadded standard base64).
+//   - Rotation: Seal always uses the primary KEK; Open accepts any KEK in the
+//     keyring. NeedsRewrap reports values under a non-primary KEK (or not
+//     sealed at all) so callers can re-seal them; retire an old KEK once no
+//     stored value references it.
+//   - An empty keyring disables encryption: Seal returns the plaintext, and
+//     Open passes plaintext through (but fails on sealed values).
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	prefix = "enc:v1:"
+	keyLen = 32 // AES-256
+)
+
+var (
+	// ErrUnknownKey means a sealed value references a KEK missing from the keyring.
+	ErrUnknownKey = errors.New("unknown encryption key")
+	// ErrMalformed means a sealed value cannot be decoded or authenticated.
+	ErrMalformed = errors.New("malformed sealed value")
+)
+
+// Keyring holds the key encryption keys by ID. The zero value (and nil) is an
+// empty keyring. Safe for concurrent use; immutable after ParseKeys.
+type Keyring struct {
+	primary string
+	keks    map[string]cipher.AEAD
+}
+
+// ParseKeys builds a keyring from "<id>:<base64 32-byte key>" specs. The
+// first spec is the primary key (used by Seal). IDs must be unique and must
+// not contain ':'.
+func ParseKeys(specs []string) (*Keyring, error) {
+	k := &Keyring{keks: make(map[string]cipher.AEAD, len(specs))}
+	for i, spec := range specs {
+		id, b64, ok := strings.Cut(spec, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("key %d: want <id>:<base64 key>", i)
+		}
+		if _, dup := k.keks[id]; dup {
+			return nil, fmt.Errorf("key %d: duplicate id %q", i, id)
+		}
+		raw, err := base64.StdEncoding.DecodeString(b64)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: base64: %w", id, err)
+		}
+		if len(raw) != keyLen {
+			return nil, fmt.Errorf("key %q: must be %d bytes (got %d)", id, keyLen, len(raw))
+		}
+		aead, err := newAEAD(raw)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		k.keks[id] = aead
+		if i == 0 {
+			k.primary = id
+		}
+	}
+	return k, nil
+}
+
+// Enabled reports whether the keyring holds a key (Seal encrypts).
+func (k *Keyring) Enabled() bool {
+	return k != nil && k.primary != ""
+}
+
+// IsSealed reports whether s is a sealed value.
+func IsSealed(s string) bool {
+	return strings.HasPrefix(s, prefix)
+}
+
+// Seal encrypts plaintext under the primary key. With an empty keyring the
+// plaintext is returned unchanged.
+func (k *Keyring) Seal(plaintext string) (string, error) {
+	if !k.Enabled() {
+		return plaintext, nil
+	}
+
+	dek := make([]byte, keyLen)
+	if _, err := rand.Read(dek); err != nil {
+		return "", fmt.Errorf("rand read: %w", err)
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return "", err
+	}
+
+	// The key ID is authenticated with the wrapped DEK, so a value cannot be
+	// relabeled to another key.
+	wrapped, err := seal(k.keks[k.primary], dek, []byte(k.primary))
+	if err != nil {
+		return "", err
+	}
+	ct, err := seal(aead, []byte(plaintext), nil)
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawStdEncoding
+	return prefix + k.primary + ":" + enc.EncodeToString(wrapped) + ":" + enc.EncodeToString(ct), nil
+}
+
+// Open decrypts a sealed value. Values that are not sealed (stored before
+// encryption was enabled) are returned unchanged.
+func (k *Keyring) Open(s string) (string, error) {
+	if !IsSealed(s) {
+		return s, nil
+	}
+
+	parts := strings.Split(strings.TrimPrefix(s, prefix), ":")
+	if len(parts) != 3 {
+		return "", ErrMalformed
+	}
+	kid := parts[0]
+
+	var kek cipher.AEAD
+	if k != nil {
+		kek = k.keks[kid]
+	}
+	if kek == nil {
+		return "", fmt.Errorf("%w %q", ErrUnknownKey, kid)
+	}
+
+	enc := base64.RawStdEncoding
+	wrapped, err := enc.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrMalformed
+	}
+	ct, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrMalformed
+	}
+
+	dek, err := open(kek, wrapped, []byte(kid))
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return "", ErrMalformed
+	}
+	pt, err := open(aead, ct, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(pt), nil
+}
+
+// NeedsRewrap reports whether s should be re-sealed: it is plaintext while
+// the keyring is enabled, or it is sealed under a non-primary key.
+func (k *Keyring) NeedsRewrap(s string) bool {
+	if !k.Enabled() {
+		return false
+	}
+	if !IsSealed(s) {
+		return true
+	}
+	kid, _, _ := strings.Cut(strings.TrimPrefix(s, prefix), ":")
+	return kid != k.primary
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal returns nonce || AEAD(plaintext).
+func seal(aead cipher.AEAD, plaintext, ad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("rand read: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, ad), nil
+}
+
+// open reverses seal.
+func open(aead cipher.AEAD, data, ad []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	pt, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], ad)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	return pt, nil
+}
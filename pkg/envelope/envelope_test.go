@@ -0,0 +1,184 @@
+package envelope
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, keyLen))
+}
+
+func mustKeyring(t *testing.T, specs ...string) *Keyring {
+	t.Helper()
+	k, err := ParseKeys(specs)
+	if err != nil {
+		t.Fatalf("ParseKeys: %v", err)
+	}
+	return k
+}
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []string
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"one", []string{"k1:" + testKey(1)}, false},
+		{"two", []string{"k2:" + testKey(2), "k1:" + testKey(1)}, false},
+		{"missing id", []string{":" + testKey(1)}, true},
+		{"missing separator", []string{testKey(1)}, true},
+		{"duplicate id", []string{"k1:" + testKey(1), "k1:" + testKey(2)}, true},
+		{"bad base64", []string{"k1:not base64!"}, true},
+		{"short key", []string{"k1:" + base64.StdEncoding.EncodeToString([]byte("short"))}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseKeys(tt.specs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKeys() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	k := mustKeyring(t, "k1:"+testKey(1))
+
+	for _, pt := range []string{"", "secret", "p@ss:w/rd?#", strings.Repeat("x", 128)} {
+		sealed, err := k.Seal(pt)
+		if err != nil {
+			t.Fatalf("Seal(%q): %v", pt, err)
+		}
+		if !IsSealed(sealed) || !strings.HasPrefix(sealed, prefix+"k1:") {
+			t.Fatalf("Seal(%q) = %q, want %sk1:...", pt, sealed, prefix)
+		}
+		if pt != "" && strings.Contains(sealed, pt) {
+			t.Fatalf("Seal(%q) leaks the plaintext: %q", pt, sealed)
+		}
+		got, err := k.Open(sealed)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		if got != pt {
+			t.Fatalf("Open = %q, want %q", got, pt)
+		}
+	}
+}
+
+func TestSealFreshDataKey(t *testing.T) {
+	k := mustKeyring(t, "k1:"+testKey(1))
+
+	a, _ := k.Seal("secret")
+	b, _ := k.Seal("secret")
+	if a == b {
+		t.Fatalf("Seal is deterministic: %q", a)
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	k1 := mustKeyring(t, "k1:"+testKey(1))
+	sealed, err := k1.Seal("secret")
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	parts := strings.Split(strings.TrimPrefix(sealed, prefix), ":")
+
+	tests := []struct {
+		name    string
+		keys    *Keyring
+		value   string
+		wantErr error
+	}{
+		{"unknown key", mustKeyring(t, "k2:"+testKey(2)), sealed, ErrUnknownKey},
+		{"empty keyring", nil, sealed, ErrUnknownKey},
+		{"same id, other key", mustKeyring(t, "k1:"+testKey(2)), sealed, ErrMalformed},
+		{"relabeled key id", mustKeyring(t, "k1:"+testKey(1), "k9:"+testKey(1)), prefix + "k9:" + parts[1] + ":" + parts[2], ErrMalformed},
+		{"missing part", k1, prefix + parts[0] + ":" + parts[1], ErrMalformed},
+		{"bad base64", k1, prefix + parts[0] + ":" + parts[1] + ":!!", ErrMalformed},
+		{"tampered ciphertext", k1, prefix + parts[0] + ":" + parts[1] + ":" + flipLast(parts[2]), ErrMalformed},
+		{"truncated", k1, prefix + parts[0] + ":" + parts[1] + ":AA", ErrMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.keys.Open(tt.value); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Open() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// flipLast flips a bit in the last byte of the base64-encoded s.
+func flipLast(s string) string {
+	b, _ := base64.RawStdEncoding.DecodeString(s)
+	b[len(b)-1] ^= 1
+	return base64.RawStdEncoding.EncodeToString(b)
+}
+
+func TestEmptyKeyring(t *testing.T) {
+	for _, k := range []*Keyring{nil, mustKeyring(t)} {
+		if k.Enabled() {
+			t.Fatal("Enabled() = true")
+		}
+		sealed, err := k.Seal("secret")
+		if err != nil || sealed != "secret" {
+			t.Fatalf("Seal = %q, %v; want plaintext", sealed, err)
+		}
+		if got, err := k.Open("secret"); err != nil || got != "secret" {
+			t.Fatalf("Open = %q, %v; want plaintext", got, err)
+		}
+		if k.NeedsRewrap("secret") {
+			t.Fatal("NeedsRewrap(plaintext) = true")
+		}
+	}
+}
+
+func TestRewrap(t *testing.T) {
+	old := mustKeyring(t, "k1:"+testKey(1))
+	rotated := mustKeyring(t, "k2:"+testKey(2), "k1:"+testKey(1))
+
+	// plaintext stored before encryption was enabled
+	if !old.NeedsRewrap("secret") {
+		t.Fatal("NeedsRewrap(plaintext) = false")
+	}
+	if got, err := old.Open("secret"); err != nil || got != "secret" {
+		t.Fatalf("Open(plaintext) = %q, %v", got, err)
+	}
+
+	sealed, err := old.Seal("secret")
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if old.NeedsRewrap(sealed) {
+		t.Fatal("NeedsRewrap under the primary key = true")
+	}
+
+	// after rotation: still readable, flagged for rewrap
+	if !rotated.NeedsRewrap(sealed) {
+		t.Fatal("NeedsRewrap under a retired key = false")
+	}
+	pt, err := rotated.Open(sealed)
+	if err != nil || pt != "secret" {
+		t.Fatalf("Open = %q, %v", pt, err)
+	}
+	resealed, err := rotated.Seal(pt)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if !strings.HasPrefix(resealed, prefix+"k2:") || rotated.NeedsRewrap(resealed) {
+		t.Fatalf("resealed = %q, want primary key k2", resealed)
+	}
+
+	// once k1 is retired, only the rewrapped value opens
+	retired := mustKeyring(t, "k2:"+testKey(2))
+	if got, err := retired.Open(resealed); err != nil || got != "secret" {
+		t.Fatalf("Open(resealed) = %q, %v", got, err)
+	}
+	if _, err := retired.Open(sealed); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("Open(old) = %v, want ErrUnknownKey", err)
+	}
+}
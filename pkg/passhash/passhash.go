@@ -0,0 +1,142 @@
+// Package passhash hashes and verifies passwords for at-rest storage.
+//
+// Design:
+//
+//   - New hashes are always argon2id, encoded in the PHC string format:
+//     $argon2id$v=19$m=<KiB>,t=<iterations>,p=<threads>$<salt>$<hash>
+//     (salt and hash are unpadded standard base64).
+//   - Verify also accepts bcrypt hashes ($2a$/$2b$/$2y$), so hashes
+//     provisioned by external tooling (e.g. htpasswd -B) can be imported.
+//   - NeedsRehash reports hashes produced with weaker/older parameters so
+//     callers can transparently upgrade them on the next successful login.
+//
+// Parameters follow the OWASP minimum for argon2id (19 MiB, t=2, p=1): cheap
+// enough to run on every Basic-Auth request, expensive enough for offline attacks.
+package passhash
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	argonMemory  uint32 = 19 * 1024 // KiB
+	argonTime    uint32 = 2
+	argonThreads uint8  = 1
+	argonKeyLen  uint32 = 32
+	argonSaltLen        = 16
+)
+
+var (
+	// ErrUnsupportedHash means the encoded hash is not in a recognized format.
+	ErrUnsupportedHash = errors.New("unsupported password hash format")
+)
+
+// Hash returns the PHC-encoded argon2id hash of password.
+func Hash(password string) (string, error) {
+	salt := make([]byte, argonSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("rand read: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, argonTime, argonMemory, argonThreads, argonKeyLen)
+
+	b64 := base64.RawStdEncoding
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, argonMemory, argonTime, argonThreads, b64.EncodeToString(salt), b64.EncodeToString(key)), nil
+}
+
+// Verify reports whether password matches the encoded hash (argon2id or bcrypt).
+// Comparison is constant-time. A malformed hash yields (false, error).
+func Verify(password, encoded string) (bool, error) {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		p, salt, key, err := decodeArgon2id(encoded)
+		if err != nil {
+			return false, err
+		}
+		other := argon2.IDKey([]byte(password), salt, p.time, p.memory, p.threads, uint32(len(key)))
+		return subtle.ConstantTimeCompare(key, other) == 1, nil
+
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if err == nil {
+			return true, nil
+		}
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return false, err
+
+	default:
+		return false, ErrUnsupportedHash
+	}
+}
+
+// NeedsRehash reports whether encoded should be replaced by a fresh Hash()
+// (non-argon2id algorithm or weaker parameters than the current defaults).
+func NeedsRehash(encoded string) bool {
+	if !strings.HasPrefix(encoded, "$argon2id$") {
+		return true
+	}
+	p, _, _, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return p.memory < argonMemory || p.time < argonTime || p.threads < argonThreads
+}
+
+// ----- helpers --------------------------------------------------------------
+
+type argon2Params struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+}
+
+// decodeArgon2id parses "$argon2id$v=19$m=..,t=..,p=..$salt$hash".
+func decodeArgon2id(encoded string) (argon2Params, []byte, []byte, error) {
+	var p argon2Params
+
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return p, nil, nil, ErrUnsupportedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return p, nil, nil, fmt.Errorf("argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return p, nil, nil, fmt.Errorf("argon2id version %d unsupported", version)
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads); err != nil {
+		return p, nil, nil, fmt.Errorf("argon2id params: %w", err)
+	}
+	if p.time < 1 || p.threads < 1 {
+		return p, nil, nil, errors.New("argon2id params: t and p must be at least 1") // argon2 panics otherwise
+	}
+
+	b64 := base64.RawStdEncoding
+	salt, err := b64.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, fmt.Errorf("argon2id salt: %w", err)
+	}
+	key, err := b64.DecodeString(parts[5])
+	if err != nil {
+		return p, nil, nil, fmt.Errorf("argon2id hash: %w", err)
+	}
+	if len(key) == 0 {
+		return p, nil, nil, errors.New("argon2id hash: empty")
+	}
+
+	return p, salt, key, nil
+}
@@ -0,0 +1,123 @@
+package passhash
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHashVerify(t *testing.T) {
+	for _, pw := range []string{"", "hunter2", "pässwörd with spaces", strings.Repeat("x", 200)} {
+		encoded, err := Hash(pw)
+		if err != nil {
+			t.Fatalf("Hash(%q): %v", pw, err)
+		}
+		if !strings.HasPrefix(encoded, "$argon2id$v=19$m=19456,t=2,p=1$") {
+			t.Fatalf("Hash(%q) = %q, want PHC argon2id with default params", pw, encoded)
+		}
+		if ok, err := Verify(pw, encoded); !ok || err != nil {
+			t.Fatalf("Verify(%q) = %v, %v; want true", pw, ok, err)
+		}
+		if ok, err := Verify(pw+"!", encoded); ok || err != nil {
+			t.Fatalf("Verify(wrong) = %v, %v; want false", ok, err)
+		}
+		if NeedsRehash(encoded) {
+			t.Fatalf("NeedsRehash(%q) = true", encoded)
+		}
+	}
+}
+
+func TestHashSalted(t *testing.T) {
+	a, _ := Hash("hunter2")
+	b, _ := Hash("hunter2")
+	if a == b {
+		t.Fatalf("Hash is unsalted: %q", a)
+	}
+}
+
+func TestVerifyBcrypt(t *testing.T) {
+	raw, err := bcrypt.GenerateFromPassword([]byte("hunter2"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt: %v", err)
+	}
+	encoded := string(raw)
+
+	if ok, err := Verify("hunter2", encoded); !ok || err != nil {
+		t.Fatalf("Verify = %v, %v; want true", ok, err)
+	}
+	if ok, err := Verify("hunter3", encoded); ok || err != nil {
+		t.Fatalf("Verify(wrong) = %v, %v; want false", ok, err)
+	}
+	if !NeedsRehash(encoded) {
+		t.Fatal("NeedsRehash(bcrypt) = false")
+	}
+}
+
+func TestVerifyMalformed(t *testing.T) {
+	valid, err := Hash("hunter2")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	parts := strings.Split(valid, "$")
+	with := func(i int, s string) string {
+		p := append([]string(nil), parts...)
+		p[i] = s
+		return strings.Join(p, "$")
+	}
+
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{"empty", ""},
+		{"plaintext", "hunter2"},
+		{"unknown algorithm", "$argon2i$v=19$m=19456,t=2,p=1$c2FsdA$aGFzaA"},
+		{"missing part", strings.Join(parts[:5], "$")},
+		{"bad version", with(2, "v=16")},
+		{"bad params", with(3, "m=x,t=2,p=1")},
+		{"zero iterations", with(3, "m=19456,t=0,p=1")},
+		{"zero threads", with(3, "m=19456,t=2,p=0")},
+		{"bad salt", with(4, "!!")},
+		{"bad hash", with(5, "!!")},
+		{"empty hash", with(5, "")},
+		{"bad bcrypt", "$2b$10$short"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := Verify("hunter2", tt.encoded)
+			if ok || err == nil {
+				t.Fatalf("Verify() = %v, %v; want false, error", ok, err)
+			}
+			if !NeedsRehash(tt.encoded) {
+				t.Fatal("NeedsRehash() = false")
+			}
+		})
+	}
+
+	if _, err := Verify("hunter2", "hunter2"); !errors.Is(err, ErrUnsupportedHash) {
+		t.Fatalf("Verify(plaintext) = %v, want ErrUnsupportedHash", err)
+	}
+}
+
+func TestNeedsRehashWeakParams(t *testing.T) {
+	valid, err := Hash("hunter2")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	parts := strings.Split(valid, "$")
+
+	for _, params := range []string{"m=4096,t=2,p=1", "m=19456,t=1,p=1"} {
+		p := append([]string(nil), parts...)
+		p[3] = params
+		if !NeedsRehash(strings.Join(p, "$")) {
+			t.Fatalf("NeedsRehash(%s) = false", params)
+		}
+	}
+	p := append([]string(nil), parts...)
+	p[3] = "m=65536,t=3,p=4"
+	if NeedsRehash(strings.Join(p, "$")) {
+		t.Fatal("NeedsRehash(stronger params) = true")
+	}
+}
@@ -0,0 +1,380 @@
+// Package promtext is a minimal Prometheus instrumentation library writing
+// the text exposition format (version 0.0.4).
+//
+// Design:
+//
+//   - A Registry owns metric families (CounterVec, GaugeVec, HistogramVec)
+//     and scrape-time collectors (CollectorFunc) for values that are cheaper
+//     to read on demand than to maintain (e.g. sizes of in-memory tables).
+//   - Label values are positional, in the order the labels were declared.
+//   - Output is deterministic: families in registration order, series sorted
+//     by label values.
+//
+// Only what zmux needs is implemented: no summaries, no exemplars, no
+// protobuf format.
+package promtext
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the media type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefBuckets are latency buckets (seconds) suited to HTTP and Redis calls.
+var DefBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// collector writes one or more metric families.
+type collector interface {
+	collect(w *Writer)
+}
+
+// CollectorFunc writes metric families at scrape time.
+type CollectorFunc func(w *Writer)
+
+func (f CollectorFunc) collect(w *Writer) { f(w) }
+
+// Registry is a set of metrics exposed together.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+	names      map[string]struct{}
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]struct{})}
+}
+
+func (r *Registry) register(name string, c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, dup := r.names[name]; dup {
+		panic("promtext: duplicate metric " + name)
+	}
+	r.names[name] = struct{}{}
+	r.collectors = append(r.collectors, c)
+}
+
+// Register adds a scrape-time collector.
+func (r *Registry) Register(f CollectorFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, f)
+}
+
+// WriteTo writes all metrics in the text exposition format.
+func (r *Registry) WriteTo(out io.Writer) (int64, error) {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	cw := &countingWriter{w: out}
+	w := &Writer{bw: bufio.NewWriter(cw)}
+	for _, c := range collectors {
+		c.collect(w)
+	}
+	if err := w.bw.Flush(); err != nil {
+		return cw.n, err
+	}
+	return cw.n, nil
+}
+
+// Handler serves the registry over HTTP.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.Header().Set("Content-Type", ContentType)
+		_, _ = r.WriteTo(rw)
+	})
+}
+
+// --- Writer ------------------------------------------------------------------
+
+// Writer emits families and samples for a CollectorFunc.
+type Writer struct {
+	bw *bufio.Writer
+}
+
+// Family starts a metric family (# HELP / # TYPE). typ is "counter",
+// "gauge", "histogram" or "untyped".
+func (w *Writer) Family(name, help, typ string) {
+	fmt.Fprintf(w.bw, "# HELP %s %s\n# TYPE %s %s\n", name, escapeHelp(help), name, typ)
+}
+
+// Sample writes one sample; labels are name/value pairs.
+func (w *Writer) Sample(name string, value float64, labels ...string) {
+	w.bw.WriteString(name)
+	if len(labels) >= 2 {
+		w.bw.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				w.bw.WriteByte(',')
+			}
+			w.bw.WriteString(labels[i])
+			w.bw.WriteString(`="`)
+			w.bw.WriteString(escapeLabel(labels[i+1]))
+			w.bw.WriteByte('"')
+		}
+		w.bw.WriteByte('}')
+	}
+	w.bw.WriteByte(' ')
+	w.bw.WriteString(formatFloat(value))
+	w.bw.WriteByte('\n')
+}
+
+// --- vectors -----------------------------------------------------------------
+
+// vec is the labelled series table shared by all metric kinds.
+type vec[T any] struct {
+	name, help string
+	labels     []string
+	newSeries  func() *T
+
+	mu     sync.RWMutex
+	series map[string]*entry[T]
+}
+
+type entry[T any] struct {
+	values []string
+	s      *T
+}
+
+func (v *vec[T]) get(values []string) *T {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("promtext: %s: got %d label values, want %d", v.name, len(values), len(v.labels)))
+	}
+	key := strings.Join(values, "\xff")
+
+	v.mu.RLock()
+	e, ok := v.series[key]
+	v.mu.RUnlock()
+	if ok {
+		return e.s
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if e, ok := v.series[key]; ok {
+		return e.s
+	}
+	e = &entry[T]{values: append([]string(nil), values...), s: v.newSeries()}
+	v.series[key] = e
+	return e.s
+}
+
+// delete drops the series with the given label values (e.g. a deleted unit).
+func (v *vec[T]) delete(values []string) {
+	v.mu.Lock()
+	delete(v.series, strings.Join(values, "\xff"))
+	v.mu.Unlock()
+}
+
+// sorted returns the series ordered by label values.
+func (v *vec[T]) sorted() []*entry[T] {
+	v.mu.RLock()
+	out := make([]*entry[T], 0, len(v.series))
+	for _, e := range v.series {
+		out = append(out, e)
+	}
+	v.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i].values, out[j].values
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+	return out
+}
+
+// pairs zips label names and values (plus extra pairs) for Writer.Sample.
+func (v *vec[T]) pairs(values []string, extra ...string) []string {
+	out := make([]string, 0, 2*len(values)+len(extra))
+	for i, name := range v.labels {
+		out = append(out, name, values[i])
+	}
+	return append(out, extra...)
+}
+
+// --- counter / gauge ---------------------------------------------------------
+
+// value is a float64 guarded by a mutex (counters and gauges).
+type value struct {
+	mu sync.Mutex
+	v  float64
+}
+
+func (x *value) add(d float64) {
+	x.mu.Lock()
+	x.v += d
+	x.mu.Unlock()
+}
+
+func (x *value) set(v float64) {
+	x.mu.Lock()
+	x.v = v
+	x.mu.Unlock()
+}
+
+func (x *value) load() float64 {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.v
+}
+
+// CounterVec is a family of monotonically increasing counters.
+type CounterVec struct{ v *vec[value] }
+
+// NewCounterVec registers a counter family.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{v: &vec[value]{name: name, help: help, labels: labels, newSeries: func() *value { return &value{} }, series: make(map[string]*entry[value])}}
+	r.register(name, c)
+	return c
+}
+
+// Inc adds 1 to the series with the given label values.
+func (c *CounterVec) Inc(values ...string) { c.v.get(values).add(1) }
+
+// Add adds d (>= 0) to the series with the given label values.
+func (c *CounterVec) Add(d float64, values ...string) {
+	if d < 0 {
+		panic("promtext: counter cannot decrease")
+	}
+	c.v.get(values).add(d)
+}
+
+func (c *CounterVec) collect(w *Writer) {
+	w.Family(c.v.name, c.v.help, "counter")
+	for _, e := range c.v.sorted() {
+		w.Sample(c.v.name, e.s.load(), c.v.pairs(e.values)...)
+	}
+}
+
+// GaugeVec is a family of values that can go up and down.
+type GaugeVec struct{ v *vec[value] }
+
+// NewGaugeVec registers a gauge family.
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{v: &vec[value]{name: name, help: help, labels: labels, newSeries: func() *value { return &value{} }, series: make(map[string]*entry[value])}}
+	r.register(name, g)
+	return g
+}
+
+// Set sets the series with the given label values.
+func (g *GaugeVec) Set(v float64, values ...string) { g.v.get(values).set(v) }
+
+// Add adds d (may be negative) to the series with the given label values.
+func (g *GaugeVec) Add(d float64, values ...string) { g.v.get(values).add(d) }
+
+// Delete drops the series with the given label values.
+func (g *GaugeVec) Delete(values ...string) { g.v.delete(values) }
+
+func (g *GaugeVec) collect(w *Writer) {
+	w.Family(g.v.name, g.v.help, "gauge")
+	for _, e := range g.v.sorted() {
+		w.Sample(g.v.name, e.s.load(), g.v.pairs(e.values)...)
+	}
+}
+
+// --- histogram ---------------------------------------------------------------
+
+type histogram struct {
+	mu     sync.Mutex
+	counts []uint64 // per bucket (non-cumulative); last is +Inf
+	sum    float64
+	count  uint64
+}
+
+// HistogramVec is a family of histograms sharing bucket bounds.
+type HistogramVec struct {
+	v       *vec[histogram]
+	buckets []float64
+}
+
+// NewHistogramVec registers a histogram family. buckets must be sorted
+// ascending; nil means DefBuckets.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	h := &HistogramVec{buckets: buckets}
+	h.v = &vec[histogram]{name: name, help: help, labels: labels, series: make(map[string]*entry[histogram]),
+		newSeries: func() *histogram { return &histogram{counts: make([]uint64, len(buckets)+1)} }}
+	r.register(name, h)
+	return h
+}
+
+// Observe records v in the series with the given label values.
+func (h *HistogramVec) Observe(v float64, values ...string) {
+	i := sort.SearchFloat64s(h.buckets, v) // first bound >= v
+	s := h.v.get(values)
+	s.mu.Lock()
+	s.counts[i]++
+	s.sum += v
+	s.count++
+	s.mu.Unlock()
+}
+
+func (h *HistogramVec) collect(w *Writer) {
+	w.Family(h.v.name, h.v.help, "histogram")
+	for _, e := range h.v.sorted() {
+		e.s.mu.Lock()
+		counts := append([]uint64(nil), e.s.counts...)
+		sum, count := e.s.sum, e.s.count
+		e.s.mu.Unlock()
+
+		var cum uint64
+		for i, bound := range h.buckets {
+			cum += counts[i]
+			w.Sample(h.v.name+"_bucket", float64(cum), h.v.pairs(e.values, "le", formatFloat(bound))...)
+		}
+		w.Sample(h.v.name+"_bucket", float64(count), h.v.pairs(e.values, "le", "+Inf")...)
+		w.Sample(h.v.name+"_sum", sum, h.v.pairs(e.values)...)
+		w.Sample(h.v.name+"_count", float64(count), h.v.pairs(e.values)...)
+	}
+}
+
+// --- helpers -----------------------------------------------------------------
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, +1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
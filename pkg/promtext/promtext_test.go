@@ -0,0 +1,172 @@
+package promtext
+
+import (
+	"io"
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func scrape(t *testing.T, r *Registry) string {
+	t.Helper()
+	var b strings.Builder
+	n, err := r.WriteTo(&b)
+	if err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	if n != int64(b.Len()) {
+		t.Fatalf("WriteTo reported %d bytes, wrote %d", n, b.Len())
+	}
+	return b.String()
+}
+
+func TestCounter(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("http_requests_total", "Requests by route\nand code.", "route", "code")
+	c.Inc("/b", "200")
+	c.Add(2, "/a", "500")
+	c.Inc("/a", "200")
+	c.Inc("/a", "200")
+
+	want := `# HELP http_requests_total Requests by route\nand code.
+# TYPE http_requests_total counter
+http_requests_total{route="/a",code="200"} 2
+http_requests_total{route="/a",code="500"} 2
+http_requests_total{route="/b",code="200"} 1
+`
+	if got := scrape(t, r); got != want {
+		t.Fatalf("scrape:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCounterDecreasePanics(t *testing.T) {
+	c := NewRegistry().NewCounterVec("c_total", "c")
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Add(-1) did not panic")
+		}
+	}()
+	c.Add(-1)
+}
+
+func TestGauge(t *testing.T) {
+	r := NewRegistry()
+	g := r.NewGaugeVec("slots", "Slots.", "pool")
+	g.Set(3, "onflight")
+	g.Add(-1, "onflight")
+	g.Set(1.5, "preflight")
+	g.Set(7, "gone")
+	g.Delete("gone")
+
+	nolabels := r.NewGaugeVec("up", "Up.")
+	nolabels.Set(1)
+
+	want := `# HELP slots Slots.
+# TYPE slots gauge
+slots{pool="onflight"} 2
+slots{pool="preflight"} 1.5
+# HELP up Up.
+# TYPE up gauge
+up 1
+`
+	if got := scrape(t, r); got != want {
+		t.Fatalf("scrape:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestLabelEscaping(t *testing.T) {
+	r := NewRegistry()
+	g := r.NewGaugeVec("g", `help with \ backslash`, "v")
+	g.Set(1, "a\"quote\\back\nline")
+
+	want := `# HELP g help with \\ backslash
+# TYPE g gauge
+g{v="a\"quote\\back\nline"} 1
+`
+	if got := scrape(t, r); got != want {
+		t.Fatalf("scrape:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogramVec("latency_seconds", "Latency.", []float64{0.1, 1}, "op")
+	h.Observe(0.05, "get")
+	h.Observe(0.1, "get") // bounds are inclusive (le)
+	h.Observe(0.5, "get")
+	h.Observe(3, "get") // +Inf only
+
+	want := `# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{op="get",le="0.1"} 2
+latency_seconds_bucket{op="get",le="1"} 3
+latency_seconds_bucket{op="get",le="+Inf"} 4
+latency_seconds_sum{op="get"} 3.65
+latency_seconds_count{op="get"} 4
+`
+	if got := scrape(t, r); got != want {
+		t.Fatalf("scrape:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCollectorFunc(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounterVec("first_total", "First.").Inc()
+	r.Register(func(w *Writer) {
+		w.Family("table_size", "Entries.", "gauge")
+		w.Sample("table_size", 4, "table", "units")
+		w.Sample("table_size", math.Inf(1), "table", "inf")
+		w.Sample("table_size", math.NaN(), "table", "nan")
+	})
+
+	want := `# HELP first_total First.
+# TYPE first_total counter
+first_total 1
+# HELP table_size Entries.
+# TYPE table_size gauge
+table_size{table="units"} 4
+table_size{table="inf"} +Inf
+table_size{table="nan"} NaN
+`
+	if got := scrape(t, r); got != want {
+		t.Fatalf("scrape:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDuplicateRegistrationPanics(t *testing.T) {
+	r := NewRegistry()
+	r.NewGaugeVec("g", "g")
+	defer func() {
+		if recover() == nil {
+			t.Fatal("duplicate registration did not panic")
+		}
+	}()
+	r.NewCounterVec("g", "g")
+}
+
+func TestLabelCountPanics(t *testing.T) {
+	g := NewRegistry().NewGaugeVec("g", "g", "a", "b")
+	defer func() {
+		if recover() == nil {
+			t.Fatal("wrong label count did not panic")
+		}
+	}()
+	g.Set(1, "only-one")
+}
+
+func TestHandler(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounterVec("c_total", "C.").Inc()
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Fatalf("Content-Type = %q, want %q", ct, ContentType)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	if !strings.Contains(string(body), "\nc_total 1\n") {
+		t.Fatalf("body = %q", body)
+	}
+}
@@ -8,6 +8,13 @@
 //     quoted command string (for logging/systemd), or a prepared *exec.Cmd
 //     (unstarted; caller configures stdio/env/cwd).
 //
+// Secrets policy:
+//
+//   - Input credentials are passed through the environment (BuildEnv), never
+//     argv: argv is visible to every local user via ps and /proc. This
+//     requires remux >= MinRemuxVersion.
+//   - BuildString scrubs URL userinfo, as its output is meant for logs.
+//
 // Emission policy is deterministic and explicit:
 //
 //   - Numeric + boolean flags are ALWAYS emitted (including 0/false).
@@ -27,6 +34,7 @@
 //	argv := remuxcmd.BuildArgv(ch)      // []string{ "remux", "--id", ... }
 //	s    := remuxcmd.BuildString(ch)    // "'remux' '--id' '42_7' ..."
 //	cmd  := remuxcmd.BuildCommand(ch)   // *exec.Cmd (not started)
+//	env  := remuxcmd.BuildEnv(ch)       // []string{ "REMUX_INPUT_USERNAME=...", ... }
 //
 // Extensibility:
 //
@@ -39,6 +47,7 @@ import (
 	"strings"
 
 	"github.com/edirooss/zmux-server/internal/domain/channel"
+	"github.com/edirooss/zmux-server/pkg/avurl"
 )
 
 // Builder constructs argv and shell-safe command strings for `remux`.
@@ -143,7 +152,8 @@ func (b *Builder) BuildArgv() []string {
 	return out
 }
 
-// BuildString returns a single shell-quoted command string.
+// BuildString returns a single shell-quoted command string, with the userinfo
+// of any URL argument replaced by "***" (see avurl.ScrubUserinfo).
 //
 // Quoting strategy:
 //   - Single-quote wrapping with inner single quotes escaped as:  ' -> '\”
@@ -151,7 +161,7 @@ func (b *Builder) BuildArgv() []string {
 func (b *Builder) BuildString() string {
 	quoted := make([]string, len(b.args))
 	for i, a := range b.args {
-		quoted[i] = shQuote(a)
+		quoted[i] = shQuote(avurl.ScrubUserinfo(a))
 	}
 	return strings.Join(quoted, " ")
 }
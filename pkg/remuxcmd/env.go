@@ -0,0 +1,33 @@
+package remuxcmd
+
+import "github.com/edirooss/zmux-server/internal/domain/channel"
+
+// Input credential environment variables read by remux (since
+// MinRemuxVersion). When set, remux applies them as the userinfo of the
+// --input URL. Credentials never appear in argv, which is world-readable via
+// /proc and ps.
+const (
+	EnvInputUsername = "REMUX_INPUT_USERNAME"
+	EnvInputPassword = "REMUX_INPUT_PASSWORD"
+)
+
+// MinRemuxVersion is the first remux release that reads the input credential
+// environment variables. Older releases ignore them and connect without
+// credentials. Keep in sync with the remux dependency in nfpm.yaml.
+const MinRemuxVersion = "1.4.0"
+
+// BuildEnv returns the per-process environment (KEY=VALUE) carrying the
+// channel's input credentials; nil when the input has none.
+//
+// Only set fields are emitted, mirroring the URL form: a password without a
+// username is never sent.
+func BuildEnv(c *channel.ZmuxChannel) []string {
+	if c.Input.Username == nil {
+		return nil
+	}
+	env := []string{EnvInputUsername + "=" + *c.Input.Username}
+	if c.Input.Password != nil {
+		env = append(env, EnvInputPassword+"="+*c.Input.Password)
+	}
+	return env
+}
@@ -4,7 +4,6 @@ import (
 	"strconv"
 
 	"github.com/edirooss/zmux-server/internal/domain/channel"
-	"github.com/edirooss/zmux-server/pkg/avurl"
 )
 
 // FromChannel materializes a Builder from a domain-level ZmuxChannel.
@@ -20,6 +19,8 @@ import (
 // - Optional strings (pointers) are emitted only when non-nil and non-empty.
 // - Flags the CLI defines as StringVar are emitted via decimal strings.
 //
+// Input credentials are not part of argv; see BuildEnv (remux >= MinRemuxVersion).
+//
 // NOTE: This function does *not* validate domain fields; it encodes them.
 // Validation belongs in the domain layer.
 func FromChannel(c *channel.ZmuxChannel) *Builder {
@@ -31,7 +32,7 @@ func FromChannel(c *channel.ZmuxChannel) *Builder {
 
 	// --- Positional: --input <url> ---
 	b.WithString("--input")
-	b.WithStringP(avurl.EmbeddUserinfo(c.Input.URL, c.Input.Username, c.Input.Password))
+	b.WithStringP(c.Input.URL)
 
 	// --- Input flags (CLI: StringVar unless noted) ---
 	b.